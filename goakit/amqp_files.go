@@ -0,0 +1,541 @@
+package goakit
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+
+	"goa.design/goa/codegen"
+	goaexpr "goa.design/goa/expr"
+	httpcodegen "goa.design/goa/http/codegen"
+	"goa.design/plugins/goakit/expr"
+)
+
+// amqpData contains the data needed to render the AMQP templates of an
+// endpoint.
+type amqpData struct {
+	*httpcodegen.EndpointData
+	// Exchange is the AMQP exchange the requests are published to.
+	Exchange string
+	// Queue is the AMQP queue the requests are consumed from.
+	Queue string
+	// Example is the Go expression that initializes the example payload
+	// used by the generated tests, empty if the method has no payload.
+	Example string
+	// Testable is false if the example payload cannot be initialized in Go.
+	Testable bool
+	// Invalid is the JSON encoded payload used by the generated tests to
+	// check that the subscriber rejects the invalid payloads, empty if the
+	// method has no payload.
+	Invalid string
+	// ValidatePayload is true if the decoded payload must be validated.
+	ValidatePayload bool
+	// PayloadPointer is true if the payload type is a pointer.
+	PayloadPointer bool
+	// Request is the body used to encode the payloads, nil if the
+	// payloads are encoded as is.
+	Request *bodyData
+}
+
+// AMQPFiles produces the files defining the go-kit AMQP subscribers and
+// publishers for the methods served over AMQP together with the tests that
+// run them against an in-memory AMQP channel.
+func AMQPFiles(genpkg string, root *goaexpr.RootExpr) []*codegen.File {
+	var fw []*codegen.File
+	for _, svc := range root.API.HTTP.Services {
+		eps := amqpEndpoints(svc)
+		if len(eps) == 0 {
+			continue
+		}
+		fw = append(fw, serverAMQP(genpkg, svc, eps), clientAMQP(genpkg, svc, eps))
+		if f := amqpTest(genpkg, svc, eps); f != nil {
+			fw = append(fw, f)
+		}
+	}
+	return fw
+}
+
+// amqpEndpoints returns the data of the given service endpoints served over
+// AMQP. Streaming endpoints are not supported.
+func amqpEndpoints(svc *goaexpr.HTTPServiceExpr) []*amqpData {
+	data := httpcodegen.HTTPServices.Get(svc.Name())
+	var eps []*amqpData
+	for _, e := range data.Endpoints {
+		if isStreaming(e) {
+			continue
+		}
+		m := expr.Method(svc.Name(), e.Method.Name)
+		if m == nil || m.AMQPQueue == "" {
+			continue
+		}
+		ad := &amqpData{EndpointData: e, Exchange: m.AMQPExchange, Queue: m.AMQPQueue, Testable: true}
+		if pm := svc.ServiceExpr.Method(e.Method.Name); pm != nil && pm.Payload.Type != goaexpr.Empty {
+			ad.Example, ad.Testable = exampleLiteral(pm.Payload, pm.Payload.Example(goaexpr.Root.API.Random()), e.Payload.Ref)
+			ad.Invalid = invalidPayload(pm.Payload)
+		}
+		ad.ValidatePayload, ad.PayloadPointer = payloadValidation(svc, e)
+		eps = append(eps, ad)
+	}
+	return eps
+}
+
+// invalidPayload returns a JSON encoded payload that fails to decode or to
+// validate: an empty object if the payload is an object with required
+// attributes, a truncated JSON document otherwise.
+func invalidPayload(att *goaexpr.AttributeExpr) string {
+	if ut, ok := att.Type.(goaexpr.UserType); ok {
+		att = ut.Attribute()
+	}
+	if goaexpr.IsObject(att.Type) && att.Validation != nil && len(att.Validation.Required) > 0 {
+		return "{}"
+	}
+	return "{"
+}
+
+// serverAMQP returns the file defining the go-kit AMQP subscribers.
+func serverAMQP(genpkg string, svc *goaexpr.HTTPServiceExpr, eps []*amqpData) *codegen.File {
+	fpath := filepath.Join(codegen.Gendir, "amqp", codegen.SnakeCase(svc.Name()), "kitserver", "subscribers.go")
+	data := httpcodegen.HTTPServices.Get(svc.Name())
+	title := fmt.Sprintf("%s go-kit AMQP subscribers", svc.Name())
+	bb := newBodyBuilder(data.Service.PkgName, data.Service.Scope)
+	var subs []*codegen.SectionTemplate
+	for _, e := range eps {
+		ed := *e
+		if m := svc.ServiceExpr.Method(e.Method.Name); m != nil {
+			ed.Request = bb.Decoder(m.Payload, e.Method.VarName+"RequestBody", amqpDesc(e), "New"+e.Method.VarName+"Payload", e.Payload.Ref)
+		}
+		subs = append(subs, &codegen.SectionTemplate{
+			Name:   "goakit-amqp-subscriber",
+			Source: amqpSubscriberT,
+			Data:   &ed,
+		})
+	}
+	sections := []*codegen.SectionTemplate{
+		codegen.Header(title, "server", []*codegen.ImportSpec{
+			{Path: "context"},
+			{Path: "encoding/json"},
+			{Path: "unicode/utf8"},
+			{Path: "github.com/go-kit/kit/endpoint"},
+			{Path: "github.com/go-kit/kit/log"},
+			{Path: "github.com/go-kit/kit/transport"},
+			{Path: "github.com/go-kit/kit/transport/amqp", Name: "kitamqp"},
+			{Path: "github.com/streadway/amqp", Name: "amqp"},
+			{Path: "goa.design/goa", Name: "goa"},
+			{Path: path.Join(genpkg, codegen.SnakeCase(svc.Name())), Name: data.Service.PkgName},
+			{Path: path.Join(genpkg, codegen.SnakeCase(svc.Name()), "kitendpoint"), Name: "kitendpoint"},
+		}),
+		{
+			Name:   "goakit-amqp-ack",
+			Source: amqpAckT,
+		},
+	}
+	if len(bb.Types) > 0 {
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "goakit-amqp-body-types",
+			Source: bodyTypesT,
+			Data:   bb,
+		})
+	}
+	sections = append(sections, subs...)
+	sections = append(sections, &codegen.SectionTemplate{
+		Name:   "goakit-amqp-consume",
+		Source: amqpConsumeT,
+		Data: map[string]interface{}{
+			"Service":   data.Service,
+			"Endpoints": eps,
+		},
+	})
+
+	return &codegen.File{Path: fpath, SectionTemplates: sections}
+}
+
+// clientAMQP returns the file defining the go-kit AMQP publishers.
+func clientAMQP(genpkg string, svc *goaexpr.HTTPServiceExpr, eps []*amqpData) *codegen.File {
+	fpath := filepath.Join(codegen.Gendir, "amqp", codegen.SnakeCase(svc.Name()), "kitclient", "publishers.go")
+	data := httpcodegen.HTTPServices.Get(svc.Name())
+	title := fmt.Sprintf("%s go-kit AMQP publishers", svc.Name())
+	bb := newBodyBuilder(data.Service.PkgName, data.Service.Scope)
+	var pubs []*codegen.SectionTemplate
+	for _, e := range eps {
+		ed := *e
+		if m := svc.ServiceExpr.Method(e.Method.Name); m != nil {
+			ed.Request = bb.Encoder(m.Payload, e.Method.VarName+"RequestBody", amqpDesc(e), "New"+e.Method.VarName+"RequestBody", e.Payload.Ref)
+		}
+		pubs = append(pubs, &codegen.SectionTemplate{
+			Name:   "goakit-amqp-publisher",
+			Source: amqpPublisherT,
+			Data:   &ed,
+		})
+	}
+	sections := []*codegen.SectionTemplate{
+		codegen.Header(title, "client", []*codegen.ImportSpec{
+			{Path: "context"},
+			{Path: "encoding/json"},
+			{Path: "github.com/go-kit/kit/transport/amqp", Name: "kitamqp"},
+			{Path: "github.com/streadway/amqp", Name: "amqp"},
+			{Path: path.Join(genpkg, codegen.SnakeCase(svc.Name())), Name: data.Service.PkgName},
+		}),
+		{
+			Name:   "goakit-amqp-encode-request",
+			Source: amqpEncodeRequestT,
+		},
+	}
+	if len(bb.Types) > 0 {
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "goakit-amqp-body-types",
+			Source: bodyTypesT,
+			Data:   bb,
+		})
+	}
+	sections = append(sections, pubs...)
+
+	return &codegen.File{Path: fpath, SectionTemplates: sections}
+}
+
+// amqpTest returns the file defining the tests that publish the example
+// payloads using the go-kit AMQP publishers and check that the subscribers
+// serve them. It returns nil if none of the example payloads can be
+// initialized in Go, see exampleLiteral.
+func amqpTest(genpkg string, svc *goaexpr.HTTPServiceExpr, eps []*amqpData) *codegen.File {
+	var sections []*codegen.SectionTemplate
+	for _, e := range eps {
+		if !e.Testable {
+			continue
+		}
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "goakit-amqp-test",
+			Source: amqpTestT,
+			Data:   e,
+		}, &codegen.SectionTemplate{
+			Name:   "goakit-amqp-reject-test",
+			Source: amqpRejectTestT,
+			Data:   e,
+		})
+	}
+	if len(sections) == 0 {
+		return nil
+	}
+	fpath := filepath.Join(codegen.Gendir, "amqp", codegen.SnakeCase(svc.Name()), "kitserver", "subscribers_test.go")
+	data := httpcodegen.HTTPServices.Get(svc.Name())
+	title := fmt.Sprintf("%s go-kit AMQP transport tests", svc.Name())
+	header := codegen.Header(title, "server", []*codegen.ImportSpec{
+		{Path: "context"},
+		{Path: "errors"},
+		{Path: "reflect"},
+		{Path: "sync"},
+		{Path: "testing"},
+		{Path: "time"},
+		{Path: "github.com/go-kit/kit/log"},
+		{Path: "github.com/streadway/amqp", Name: "amqp"},
+		{Path: path.Join(genpkg, codegen.SnakeCase(svc.Name())), Name: data.Service.PkgName},
+		{Path: path.Join(genpkg, "amqp", codegen.SnakeCase(svc.Name()), "kitclient"), Name: "kitclient"},
+	})
+	channel := &codegen.SectionTemplate{Name: "goakit-amqp-channel", Source: amqpChannelT}
+
+	return &codegen.File{Path: fpath, SectionTemplates: append([]*codegen.SectionTemplate{header, channel}, sections...)}
+}
+
+// amqpDesc describes the payloads of the given endpoint.
+func amqpDesc(e *amqpData) string {
+	return fmt.Sprintf("%s %s payloads", e.ServiceName, e.Method.Name)
+}
+
+// input: amqpData
+const amqpSubscriberT = `{{ printf "Decode%sRequest is a go-kit AMQP DecodeRequestFunc that decodes JSON encoded %s %s payloads." .Method.VarName .ServiceName .Method.Name | comment }}
+func Decode{{ .Method.VarName }}Request(_ context.Context, deliv *amqp.Delivery) (interface{}, error) {
+{{- if .Request }}
+	var body {{ .Request.Name }}
+	if err := json.Unmarshal(deliv.Body, &body); err != nil {
+		return nil, err
+	}
+	{{- if .Request.Validate }}
+	if err := Validate{{ .Request.Name }}({{ if .Request.Pointer }}&{{ end }}body); err != nil {
+		return nil, err
+	}
+	{{- end }}
+	return {{ .Request.Init }}({{ if .Request.Pointer }}&{{ end }}body), nil
+{{- else if .Payload.Ref }}
+	var payload {{ .Payload.Ref }}
+	if err := json.Unmarshal(deliv.Body, &payload); err != nil {
+		return nil, err
+	}
+{{- if .ValidatePayload }}
+	{{- if .PayloadPointer }}
+	if payload != nil {
+		if err := kitendpoint.Validate{{ .Method.VarName }}Payload(payload); err != nil {
+			return nil, err
+		}
+	}
+	{{- else }}
+	if err := kitendpoint.Validate{{ .Method.VarName }}Payload(payload); err != nil {
+		return nil, err
+	}
+	{{- end }}
+{{- end }}
+	return payload, nil
+{{- else }}
+	return nil, nil
+{{- end }}
+}
+
+{{ printf "New%sSubscriber returns a go-kit AMQP subscriber that serves %s %s requests using the given endpoint. The requests are fire-and-forget: the subscriber does not publish the endpoint results and errors. The deliveries are acknowledged once served and rejected if the request fails, see AckResponsePublisher and EncodeError." .Method.VarName .ServiceName .Method.Name | comment }}
+func New{{ .Method.VarName }}Subscriber(e endpoint.Endpoint, options ...kitamqp.SubscriberOption) *kitamqp.Subscriber {
+	return kitamqp.NewSubscriber(
+		e,
+		Decode{{ .Method.VarName }}Request,
+		kitamqp.EncodeNopResponse,
+		append([]kitamqp.SubscriberOption{
+			kitamqp.SubscriberResponsePublisher(AckResponsePublisher),
+			kitamqp.SubscriberErrorEncoder(EncodeError),
+		}, options...)...,
+	)
+}
+`
+
+// input: map[string]interface{}{"Service": ServiceData, "Endpoints": []amqpData}
+const amqpConsumeT = `{{ printf "Consume consumes the messages of the %s service queues on the given channel and serves them using the service endpoints. The messages are acknowledged once served and rejected if the request fails, the errors are logged with logger. Consume returns once all the queues are consumed, the messages are served until the channel closes the delivery channels." .Service.Name | comment }}
+func Consume(ch kitamqp.Channel, endpoints *{{ .Service.PkgName }}.Endpoints, logger log.Logger, options ...kitamqp.SubscriberOption) error {
+	options = append([]kitamqp.SubscriberOption{kitamqp.SubscriberErrorHandler(transport.NewLogErrorHandler(logger))}, options...)
+{{- range .Endpoints }}
+	if err := consume(ch, {{ printf "%q" .Queue }}, New{{ .Method.VarName }}Subscriber(endpoints.{{ .Method.VarName }}, options...)); err != nil {
+		return err
+	}
+{{- end }}
+	return nil
+}
+
+// consume serves the messages delivered by the given queue using sub.
+func consume(ch kitamqp.Channel, queue string, sub *kitamqp.Subscriber) error {
+	deliveries, err := ch.Consume(queue, "", false, false, false, false, nil)
+	if err != nil {
+		return err
+	}
+	serve := sub.ServeDelivery(ch)
+	go func() {
+		for deliv := range deliveries {
+			deliv := deliv
+			serve(&deliv)
+		}
+	}()
+	return nil
+}
+`
+
+// input: none
+const amqpAckT = `// AckResponsePublisher is a go-kit AMQP ResponsePublisher that acknowledges
+// the deliveries served successfully. It does not publish the responses.
+func AckResponsePublisher(_ context.Context, deliv *amqp.Delivery, _ kitamqp.Channel, _ *amqp.Publishing) error {
+	return deliv.Ack(false)
+}
+
+// EncodeError is a go-kit AMQP ErrorEncoder that rejects the deliveries whose
+// request failed. The deliveries are requeued if the error is a goa service
+// error marked as temporary or timeout in the design and discarded (or dead
+// lettered if the queue is configured so) otherwise.
+func EncodeError(_ context.Context, err error, deliv *amqp.Delivery, _ kitamqp.Channel, _ *amqp.Publishing) {
+	var requeue bool
+	if se, ok := err.(*goa.ServiceError); ok {
+		requeue = se.Temporary || se.Timeout
+	}
+	deliv.Nack(false, requeue)
+}
+`
+
+// input: none
+const amqpEncodeRequestT = `// EncodeJSONRequest is a go-kit AMQP EncodeRequestFunc that encodes the
+// requests using JSON.
+func EncodeJSONRequest(_ context.Context, pub *amqp.Publishing, request interface{}) error {
+	b, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	pub.ContentType = "application/json"
+	pub.Body = b
+	return nil
+}
+`
+
+// input: amqpData
+const amqpPublisherT = `{{- if .Request -}}
+{{ printf "Encode%sRequest is a go-kit AMQP EncodeRequestFunc that encodes %s %s payloads using JSON." .Method.VarName .ServiceName .Method.Name | comment }}
+func Encode{{ .Method.VarName }}Request(ctx context.Context, pub *amqp.Publishing, request interface{}) error {
+	return EncodeJSONRequest(ctx, pub, {{ .Request.Init }}(request.({{ .Payload.Ref }})))
+}
+
+{{ end -}}
+{{ printf "New%sPublisher returns a go-kit AMQP publisher that publishes %s %s requests to the %q exchange with the %q routing key. The requests are fire-and-forget: the publisher does not wait for a response and its endpoint returns a nil response." .Method.VarName .ServiceName .Method.Name .Exchange .Queue | comment }}
+func New{{ .Method.VarName }}Publisher(ch kitamqp.Channel, options ...kitamqp.PublisherOption) *kitamqp.Publisher {
+	return kitamqp.NewPublisher(
+		ch,
+		&amqp.Queue{},
+		{{ if .Request }}Encode{{ .Method.VarName }}Request{{ else }}EncodeJSONRequest{{ end }},
+		func(context.Context, *amqp.Delivery) (interface{}, error) { return nil, nil },
+		append([]kitamqp.PublisherOption{
+			kitamqp.PublisherBefore(kitamqp.SetPublishExchange({{ printf "%q" .Exchange }}), kitamqp.SetPublishKey({{ printf "%q" .Queue }})),
+			kitamqp.PublisherDeliverer(kitamqp.SendAndForgetDeliverer),
+		}, options...)...,
+	)
+}
+`
+
+// input: none
+const amqpChannelT = `// channel is an in-memory implementation of the go-kit AMQP Channel interface.
+// It ignores the exchanges and delivers the published messages to the queue
+// named after their routing key. It records the acknowledgements of the
+// deliveries in acks: "ack", "nack" or "requeue".
+type channel struct {
+	mu     sync.Mutex
+	queues map[string]chan amqp.Delivery
+	acks   chan string
+}
+
+// newChannel returns an in-memory AMQP channel with no queue.
+func newChannel() *channel {
+	return &channel{queues: make(map[string]chan amqp.Delivery), acks: make(chan string, 16)}
+}
+
+// Publish delivers msg to the queue named key.
+func (c *channel) Publish(exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error {
+	c.queue(key) <- amqp.Delivery{
+		Acknowledger:  c,
+		Exchange:      exchange,
+		RoutingKey:    key,
+		ContentType:   msg.ContentType,
+		CorrelationId: msg.CorrelationId,
+		ReplyTo:       msg.ReplyTo,
+		Body:          msg.Body,
+	}
+	return nil
+}
+
+// Consume returns the deliveries of the given queue.
+func (c *channel) Consume(queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp.Table) (<-chan amqp.Delivery, error) {
+	return c.queue(queue), nil
+}
+
+// Ack records the acknowledgement of a delivery.
+func (c *channel) Ack(tag uint64, multiple bool) error {
+	c.acks <- "ack"
+	return nil
+}
+
+// Nack records the negative acknowledgement of a delivery.
+func (c *channel) Nack(tag uint64, multiple, requeue bool) error {
+	if requeue {
+		c.acks <- "requeue"
+	} else {
+		c.acks <- "nack"
+	}
+	return nil
+}
+
+// Reject records the rejection of a delivery.
+func (c *channel) Reject(tag uint64, requeue bool) error {
+	return c.Nack(tag, false, requeue)
+}
+
+// queue returns the channel holding the deliveries of the queue with the given
+// name, creating it if needed.
+func (c *channel) queue(name string) chan amqp.Delivery {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	q, ok := c.queues[name]
+	if !ok {
+		q = make(chan amqp.Delivery, 16)
+		c.queues[name] = q
+	}
+	return q
+}
+`
+
+// input: amqpData
+const amqpTestT = `{{ printf "Test%sAMQP makes sure that the %s %s requests published by the go-kit AMQP publisher are served by the subscriber with the design example payload." .Method.VarName .ServiceName .Method.Name | comment }}
+func Test{{ .Method.VarName }}AMQP(t *testing.T) {
+	ch := newChannel()
+	received := make(chan interface{}, 1)
+	endpoints := &{{ .ServicePkgName }}.Endpoints{
+		{{ .Method.VarName }}: func(_ context.Context, request interface{}) (interface{}, error) {
+			received <- request
+			return nil, nil
+		},
+	}
+	if err := Consume(ch, endpoints, log.NewNopLogger()); err != nil {
+		t.Fatalf("failed to consume: %s", err)
+	}
+{{- if .Example }}
+	payload := {{ .Example }}
+{{- end }}
+	if _, err := kitclient.New{{ .Method.VarName }}Publisher(ch).Endpoint()(context.Background(), {{ if .Example }}payload{{ else }}nil{{ end }}); err != nil {
+		t.Fatalf("failed to publish: %s", err)
+	}
+	select {
+	case got := <-received:
+	{{- if .Example }}
+		if !reflect.DeepEqual(got, payload) {
+			t.Errorf("invalid payload, got %#v, expected %#v", got, payload)
+		}
+	{{- else }}
+		if got != nil {
+			t.Errorf("invalid payload, got %#v, expected nil", got)
+		}
+	{{- end }}
+	case <-time.After(time.Second):
+		t.Fatal("request not served")
+	}
+	select {
+	case ack := <-ch.acks:
+		if ack != "ack" {
+			t.Errorf("invalid acknowledgement, got %q, expected \"ack\"", ack)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("delivery not acknowledged")
+	}
+}
+`
+
+// input: amqpData
+const amqpRejectTestT = `{{ printf "Test%sAMQPReject makes sure that the %s %s deliveries are rejected without being requeued when the payload is invalid or when the endpoint fails." .Method.VarName .ServiceName .Method.Name | comment }}
+func Test{{ .Method.VarName }}AMQPReject(t *testing.T) {
+	ch := newChannel()
+	received := make(chan interface{}, 1)
+	endpoints := &{{ .ServicePkgName }}.Endpoints{
+		{{ .Method.VarName }}: func(_ context.Context, request interface{}) (interface{}, error) {
+			received <- request
+			return nil, errors.New("failed")
+		},
+	}
+	if err := Consume(ch, endpoints, log.NewNopLogger()); err != nil {
+		t.Fatalf("failed to consume: %s", err)
+	}
+	rejected := func(name string) {
+		select {
+		case ack := <-ch.acks:
+			if ack != "nack" {
+				t.Errorf("%s: invalid acknowledgement, got %q, expected \"nack\"", name, ack)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("%s: delivery not rejected", name)
+		}
+	}
+{{- if .Invalid }}
+	if err := ch.Publish({{ printf "%q" .Exchange }}, {{ printf "%q" .Queue }}, false, false, amqp.Publishing{ContentType: "application/json", Body: []byte({{ printf "%q" .Invalid }})}); err != nil {
+		t.Fatalf("failed to publish: %s", err)
+	}
+	rejected("invalid payload")
+	select {
+	case got := <-received:
+		t.Errorf("invalid payload served, got %#v", got)
+	default:
+	}
+{{- end }}
+	if _, err := kitclient.New{{ .Method.VarName }}Publisher(ch).Endpoint()(context.Background(), {{ if .Example }}{{ .Example }}{{ else }}nil{{ end }}); err != nil {
+		t.Fatalf("failed to publish: %s", err)
+	}
+	rejected("endpoint error")
+	select {
+	case <-received:
+	default:
+		t.Error("request not served")
+	}
+}
+`
@@ -0,0 +1,64 @@
+package goakit
+
+import (
+	"strings"
+	"testing"
+
+	"goa.design/goa/expr"
+	"goa.design/plugins/goakit/testdata"
+)
+
+func TestAMQPFiles(t *testing.T) {
+	cases := map[string]struct {
+		DSL        func()
+		ServerCode map[string][]string
+		ClientCode map[string][]string
+		TestCode   map[string][]string
+	}{
+		"amqp": {
+			DSL: testdata.AMQPDSL,
+			ServerCode: map[string][]string{
+				"goakit-amqp-subscriber": []string{testdata.AMQPMethodSubscriberCode, testdata.AMQPNoPayloadMethodSubscriberCode},
+				"goakit-amqp-ack":        []string{testdata.AMQPAckCode},
+				"goakit-amqp-body-types": []string{testdata.AMQPServerBodyTypesCode},
+				"goakit-amqp-consume":    []string{testdata.AMQPConsumeCode},
+			},
+			ClientCode: map[string][]string{
+				"goakit-amqp-body-types": []string{testdata.AMQPClientBodyTypesCode},
+				"goakit-amqp-publisher":  []string{testdata.AMQPMethodPublisherCode, testdata.AMQPNoPayloadMethodPublisherCode},
+			},
+			TestCode: map[string][]string{
+				"goakit-amqp-test":        []string{testdata.AMQPMethodTestCode, testdata.AMQPNoPayloadMethodTestCode},
+				"goakit-amqp-reject-test": []string{testdata.AMQPMethodRejectTestCode, testdata.AMQPNoPayloadMethodRejectTestCode},
+			},
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			runDSL(t, c.DSL)
+			fs := AMQPFiles("", expr.Root)
+			if len(fs) != 3 {
+				t.Fatalf("got %d files, expected 3", len(fs))
+			}
+			for _, f := range fs {
+				code := c.ClientCode
+				switch {
+				case strings.HasSuffix(f.Path, "_test.go"):
+					code = c.TestCode
+				case strings.Contains(f.Path, "kitserver"):
+					code = c.ServerCode
+				}
+				for sec, secCode := range code {
+					testCode(t, f, sec, secCode)
+				}
+			}
+		})
+	}
+}
+
+func TestAMQPFilesDisabled(t *testing.T) {
+	runDSL(t, testdata.SimpleServiceDSL)
+	if fs := AMQPFiles("", expr.Root); len(fs) != 0 {
+		t.Errorf("got %d files, expected none", len(fs))
+	}
+}
@@ -0,0 +1,308 @@
+package goakit
+
+import (
+	"fmt"
+	"strings"
+
+	"goa.design/goa/codegen"
+	goaexpr "goa.design/goa/expr"
+)
+
+type (
+	// bodyBuilder builds the body types used by the message based transports
+	// (NATS and AMQP) to encode the service payloads and results. The body
+	// types are encoded using JSON the same way goa encodes the HTTP bodies:
+	// the JSON keys are the design attribute names and the decoded bodies
+	// are validated before being transformed into the service types.
+	bodyBuilder struct {
+		// svcCtx is the attribute context of the service types.
+		svcCtx *codegen.AttributeContext
+		// scope is the name scope of the body types.
+		scope *codegen.NameScope
+		// renamed maps the IDs of the design user types to the
+		// corresponding body user types, keyed by body type suffix.
+		renamed map[string]*goaexpr.UserTypeExpr
+		// Types lists the body types definitions.
+		Types []*bodyTypeData
+		// Inits lists the functions that transform the body types into
+		// the service types and vice versa.
+		Inits []*bodyInitData
+		// Helpers lists the transform helper functions used by Inits.
+		Helpers []*codegen.TransformFunctionData
+		// helpers records the names of the functions listed in Helpers.
+		helpers map[string]bool
+	}
+
+	// bodyTypeData describes a body type definition.
+	bodyTypeData struct {
+		// Name is the name of the body type.
+		Name string
+		// Description is the body type description.
+		Description string
+		// Def is the body type definition.
+		Def string
+		// Ref is the reference to the body type used by the validation
+		// function.
+		Ref string
+		// Validation is the code that validates the value held by the
+		// "body" variable, empty if there is no validation to run.
+		Validation string
+	}
+
+	// bodyInitData describes a function that transforms a body type into a
+	// service type or vice versa.
+	bodyInitData struct {
+		// Name is the name of the function.
+		Name string
+		// Description is the function description.
+		Description string
+		// ParamName is the name of the function parameter.
+		ParamName string
+		// ParamRef is the reference to the parameter type.
+		ParamRef string
+		// ReturnRef is the reference to the returned type.
+		ReturnRef string
+		// Code is the code that initializes the value held by the "v"
+		// variable from the parameter.
+		Code string
+	}
+
+	// bodyData describes the body used to encode or decode a payload or a
+	// result.
+	bodyData struct {
+		// Name is the name of the body type.
+		Name string
+		// Pointer is true if the functions taking the body expect a
+		// pointer.
+		Pointer bool
+		// Validate is true if the decoded body must be validated by
+		// calling the Validate<Name> function.
+		Validate bool
+		// Init is the name of the function that transforms the body into
+		// the service type or vice versa.
+		Init string
+	}
+)
+
+// newBodyBuilder returns a body builder for the service with the given package
+// name and name scope.
+func newBodyBuilder(svcPkg string, svcScope *codegen.NameScope) *bodyBuilder {
+	return &bodyBuilder{
+		svcCtx:  codegen.NewAttributeContext(false, false, true, svcPkg, svcScope),
+		scope:   codegen.NewNameScope(),
+		renamed: make(map[string]*goaexpr.UserTypeExpr),
+		helpers: make(map[string]bool),
+	}
+}
+
+// Decoder returns the body that decodes the values of the given service type
+// attribute received by the transport. name is the name of the body type, desc
+// describes the encoded values and init is the name of the function that
+// transforms the body into the service type referenced by ref. Decoder returns
+// nil if the JSON encoding of the attribute does not depend on the Go field
+// names, the service type can be decoded as is then.
+func (b *bodyBuilder) Decoder(att *goaexpr.AttributeExpr, name, desc, init, ref string) *bodyData {
+	if !hasObject(att, make(map[string]bool)) {
+		return nil
+	}
+	// The decoded body fields are pointers so that the validations can
+	// detect the missing required fields.
+	body, td := b.bodyAttribute(att, name, desc, true)
+	bd := &bodyData{Name: name, Pointer: goaexpr.IsObject(att.Type), Validate: td.Validation != "", Init: init}
+	b.init(body, att, codegen.NewAttributeContext(true, false, false, "", b.scope), b.svcCtx, &bodyInitData{
+		Name:        init,
+		Description: fmt.Sprintf("%s builds a value of type %s from the decoded %s body.", init, ref, name),
+		ParamName:   "body",
+		ParamRef:    bodyRef(name, bd.Pointer),
+		ReturnRef:   ref,
+	}, "unmarshal")
+	return bd
+}
+
+// Encoder returns the body that encodes the values of the given service type
+// attribute sent by the transport. name is the name of the body type, desc
+// describes the encoded values and init is the name of the function that
+// transforms the service type referenced by ref into the body. Encoder returns
+// nil if the JSON encoding of the attribute does not depend on the Go field
+// names, the service type can be encoded as is then.
+func (b *bodyBuilder) Encoder(att *goaexpr.AttributeExpr, name, desc, init, ref string) *bodyData {
+	if !hasObject(att, make(map[string]bool)) {
+		return nil
+	}
+	body, _ := b.bodyAttribute(att, name, desc, false)
+	bd := &bodyData{Name: name, Pointer: goaexpr.IsObject(att.Type), Init: init}
+	param := "res"
+	if strings.HasSuffix(name, "RequestBody") {
+		param = "p"
+	}
+	b.init(att, body, b.svcCtx, codegen.NewAttributeContext(false, false, true, "", b.scope), &bodyInitData{
+		Name:        init,
+		Description: fmt.Sprintf("%s builds the %s body from a value of type %s.", init, name, ref),
+		ParamName:   param,
+		ParamRef:    ref,
+		ReturnRef:   bodyRef(name, bd.Pointer),
+	}, "marshal")
+	return bd
+}
+
+// init records the function that transforms source into target.
+func (b *bodyBuilder) init(source, target *goaexpr.AttributeExpr, sourceCtx, targetCtx *codegen.AttributeContext, id *bodyInitData, prefix string) {
+	code, helpers, err := codegen.GoTransform(source, target, id.ParamName, "v", sourceCtx, targetCtx, prefix)
+	if err != nil {
+		panic(err) // bug: the body attributes are copies of the service attributes
+	}
+	id.Code = code
+	b.Inits = append(b.Inits, id)
+	for _, h := range helpers {
+		if b.helpers[h.Name] {
+			continue
+		}
+		b.helpers[h.Name] = true
+		b.Helpers = append(b.Helpers, h)
+	}
+}
+
+// bodyAttribute returns the body type named name whose attribute is a copy of
+// att where the object user types are replaced with body types named after
+// them. desc describes the encoded values. decode is true if the body is
+// decoded, the decoded body types use pointer fields and are validated.
+// bodyAttribute records the definitions of the body types in b.Types and
+// returns the definition of the body type named name.
+func (b *bodyBuilder) bodyAttribute(att *goaexpr.AttributeExpr, name, desc string, decode bool) (*goaexpr.AttributeExpr, *bodyTypeData) {
+	// The nested body types use the same suffix as the top level body type
+	// so that the request and response body types do not collide.
+	suffix := "ResponseBody"
+	if strings.HasSuffix(name, "RequestBody") {
+		suffix = "RequestBody"
+	}
+	if ut, ok := att.Type.(goaexpr.UserType); ok {
+		att = ut.Attribute()
+	}
+	ut := &goaexpr.UserTypeExpr{
+		AttributeExpr: b.rename(goaexpr.DupAtt(att), suffix, decode),
+		TypeName:      name,
+	}
+	td := b.addType(ut, desc, decode)
+	return &goaexpr.AttributeExpr{Type: ut}, td
+}
+
+// rename replaces the object user types used by att with the corresponding
+// body types. The body types are named after the user types followed by
+// suffix. The other user types are replaced with their underlying type.
+func (b *bodyBuilder) rename(att *goaexpr.AttributeExpr, suffix string, decode bool) *goaexpr.AttributeExpr {
+	switch dt := att.Type.(type) {
+	case goaexpr.UserType:
+		if dt == goaexpr.Empty {
+			return att
+		}
+		if !goaexpr.IsObject(dt) {
+			att.Type = b.rename(goaexpr.DupAtt(dt.Attribute()), suffix, decode).Type
+			return att
+		}
+		key := dt.ID() + suffix
+		if ut, ok := b.renamed[key]; ok {
+			att.Type = ut
+			return att
+		}
+		name := codegen.Goify(dt.Name(), true) + suffix
+		ut := &goaexpr.UserTypeExpr{TypeName: name}
+		b.renamed[key] = ut
+		ut.AttributeExpr = b.rename(goaexpr.DupAtt(dt.Attribute()), suffix, decode)
+		b.addType(ut, fmt.Sprintf("%s values", dt.Name()), decode)
+		att.Type = ut
+	case *goaexpr.Object:
+		obj := make(goaexpr.Object, len(*dt))
+		for i, nat := range *dt {
+			obj[i] = &goaexpr.NamedAttributeExpr{
+				Name:      nat.Name,
+				Attribute: b.rename(goaexpr.DupAtt(nat.Attribute), suffix, decode),
+			}
+		}
+		att.Type = &obj
+	case *goaexpr.Array:
+		att.Type = &goaexpr.Array{ElemType: b.rename(goaexpr.DupAtt(dt.ElemType), suffix, decode)}
+	case *goaexpr.Map:
+		att.Type = &goaexpr.Map{
+			KeyType:  b.rename(goaexpr.DupAtt(dt.KeyType), suffix, decode),
+			ElemType: b.rename(goaexpr.DupAtt(dt.ElemType), suffix, decode),
+		}
+	}
+	return att
+}
+
+// addType records and returns the definition of the given body type. desc
+// describes the encoded values.
+func (b *bodyBuilder) addType(ut *goaexpr.UserTypeExpr, desc string, decode bool) *bodyTypeData {
+	td := &bodyTypeData{
+		Name:        ut.TypeName,
+		Description: fmt.Sprintf("%s is the type of the JSON encoded %s.", ut.TypeName, desc),
+		Def:         b.scope.GoTypeDef(ut.Attribute(), decode, !decode),
+		Ref:         bodyRef(ut.TypeName, goaexpr.IsObject(ut)),
+	}
+	if decode {
+		td.Validation = codegen.RecursiveValidationCode(ut.Attribute(), true, true, false, "body")
+	}
+	b.Types = append(b.Types, td)
+	return td
+}
+
+// hasObject returns true if the type of att is or contains an object. The JSON
+// encoding of the other types does not depend on the Go field names.
+func hasObject(att *goaexpr.AttributeExpr, seen map[string]bool) bool {
+	switch dt := att.Type.(type) {
+	case goaexpr.UserType:
+		if dt == goaexpr.Empty || seen[dt.ID()] {
+			return false
+		}
+		seen[dt.ID()] = true
+		return hasObject(dt.Attribute(), seen)
+	case *goaexpr.Object:
+		return true
+	case *goaexpr.Array:
+		return hasObject(dt.ElemType, seen)
+	case *goaexpr.Map:
+		return hasObject(dt.KeyType, seen) || hasObject(dt.ElemType, seen)
+	}
+	return false
+}
+
+// bodyRef returns the reference to the body type with the given name.
+func bodyRef(name string, pointer bool) string {
+	if pointer {
+		return "*" + name
+	}
+	return name
+}
+
+// input: bodyBuilder
+const bodyTypesT = `{{ range .Types }}
+{{ comment .Description }}
+type {{ .Name }} {{ .Def }}
+{{ end }}
+{{- range .Types }}
+	{{- if .Validation }}
+
+{{ printf "Validate%s runs the validations defined in the design on %s." .Name .Name | comment }}
+func Validate{{ .Name }}(body {{ .Ref }}) (err error) {
+	{{ .Validation }}
+	return
+}
+	{{- end }}
+{{- end }}
+{{- range .Inits }}
+
+{{ comment .Description }}
+func {{ .Name }}({{ .ParamName }} {{ .ParamRef }}) {{ .ReturnRef }} {
+	{{ .Code }}
+	return v
+}
+{{- end }}
+{{- range .Helpers }}
+
+{{ printf "%s builds a value of type %s from a value of type %s." .Name .ResultTypeRef .ParamTypeRef | comment }}
+func {{ .Name }}(v {{ .ParamTypeRef }}) {{ .ResultTypeRef }} {
+	{{ .Code }}
+	return res
+}
+{{- end }}
+`
@@ -0,0 +1,187 @@
+package goakit
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"goa.design/goa/codegen"
+	"goa.design/goa/expr"
+	httpcodegen "goa.design/goa/http/codegen"
+	goakitexpr "goa.design/plugins/goakit/expr"
+)
+
+// ClientFiles produces the files defining the go-kit HTTP clients of the
+// services.
+func ClientFiles(genpkg string, root *expr.RootExpr) []*codegen.File {
+	var fw []*codegen.File
+	for _, svc := range root.API.HTTP.Services {
+		if f := client(genpkg, svc); f != nil {
+			fw = append(fw, f)
+		}
+	}
+	return fw
+}
+
+// client returns the file defining the go-kit HTTP client of the given
+// service. It returns nil if the service has no endpoint or if the go-kit
+// client cannot be built with the same arguments as the goa client, see
+// hasKitClient.
+func client(genpkg string, svc *expr.HTTPServiceExpr) *codegen.File {
+	data := httpcodegen.HTTPServices.Get(svc.Name())
+	if !hasKitClient(data) {
+		return nil
+	}
+	path := filepath.Join(codegen.Gendir, "http", codegen.SnakeCase(svc.Name()), "kitclient", "client.go")
+	title := fmt.Sprintf("%s go-kit HTTP client", svc.Name())
+	sections := []*codegen.SectionTemplate{
+		codegen.Header(title, "client", []*codegen.ImportSpec{
+			{Path: "context"},
+			{Path: "net/http"},
+			{Path: "github.com/go-kit/kit/endpoint"},
+			{Path: "github.com/go-kit/kit/log"},
+			{Path: "github.com/go-kit/kit/transport/http", Name: "kithttp"},
+			{Path: "github.com/opentracing/opentracing-go", Name: "opentracing"},
+			{Path: "goa.design/goa/http", Name: "goahttp"},
+			{Path: genpkg + "/http/" + data.Service.Name + "/client"},
+		}),
+	}
+	fm := clientFuncs()
+	sections = append(sections, &codegen.SectionTemplate{
+		Name:    "goakit-client-struct",
+		Source:  clientStructT,
+		Data:    data,
+		FuncMap: fm,
+	})
+	for _, e := range data.Endpoints {
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:    "goakit-client-method",
+			Source:  clientMethodT,
+			Data:    e,
+			FuncMap: fm,
+		})
+	}
+
+	return &codegen.File{Path: path, SectionTemplates: sections}
+}
+
+// hasKitClient returns true if the go-kit HTTP client of the given service can
+// be generated, that is if the service has at least one endpoint and if the
+// goa HTTP client requires no more than the default arguments to be built (no
+// endpoint uses streaming or multipart requests).
+func hasKitClient(data *httpcodegen.ServiceData) bool {
+	if len(data.Endpoints) == 0 {
+		return false
+	}
+	for _, e := range data.Endpoints {
+		if isStreaming(e) || e.MultipartRequestEncoder != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// clientFuncs returns the functions used by the templates of the go-kit HTTP
+// client endpoints.
+func clientFuncs() map[string]interface{} {
+	fm := policyFuncs()
+	fm["isTraced"] = goakitexpr.Traced
+	fm["spanName"] = goakitexpr.SpanName
+	return fm
+}
+
+// input: ServiceData
+const clientStructT = `{{ printf "Client lists the %s service endpoints that make HTTP requests using go-kit clients." .Service.Name | comment }}
+type Client struct {
+	c       *client.Client
+	enc     func(*http.Request) goahttp.Encoder
+	dec     func(*http.Response) goahttp.Decoder
+	options []kithttp.ClientOption
+{{- if isTraced .Service.Name }}
+	tracer  opentracing.Tracer
+	logger  log.Logger
+{{- end }}
+}
+
+{{ printf "NewClient instantiates go-kit HTTP clients for all the %s service servers. The arguments are the same as the goa client ones, doer is used to make the HTTP requests and options are appended to the default options returned by ClientOptions." .Service.Name | comment }}
+{{- if isTraced .Service.Name }}
+{{ comment "The endpoints record the request spans using the global tracer, see WithTracer." }}
+{{- end }}
+func NewClient(
+	scheme string,
+	host string,
+	doer goahttp.Doer,
+	enc func(*http.Request) goahttp.Encoder,
+	dec func(*http.Response) goahttp.Decoder,
+	restoreBody bool,
+	options ...kithttp.ClientOption,
+) *Client {
+	return &Client{
+		c:       client.NewClient(scheme, host, doer, enc, dec, restoreBody),
+		enc:     enc,
+		dec:     dec,
+		options: ClientOptions(append([]kithttp.ClientOption{kithttp.SetClient(doer)}, options...)...),
+	{{- if isTraced .Service.Name }}
+		tracer:  opentracing.GlobalTracer(),
+		logger:  log.NewNopLogger(),
+	{{- end }}
+	}
+}
+{{- if isTraced .Service.Name }}
+
+// WithTracer sets the tracer used by the client endpoints to record the
+// request spans and the logger used to log the errors that occur when
+// injecting the span context into the requests. It returns c.
+func (c *Client) WithTracer(tracer opentracing.Tracer, logger log.Logger) *Client {
+	c.tracer = tracer
+	c.logger = logger
+	return c
+}
+{{- end }}
+`
+
+// input: EndpointData
+const clientMethodT = `{{ printf "%s returns an endpoint that makes HTTP requests to the %s service %s server using a go-kit client." .Method.VarName .ServiceName .Method.Name | comment }}
+{{- if or (hasTimeout .ServiceName .Method.Name) (hasRetry .ServiceName .Method.Name) }}
+{{ comment "The endpoint applies the timeout and retry policies defined in the design." }}
+{{- end }}
+{{- if isTraced .ServiceName }}
+{{ printf "The endpoint records a %q span for each request using the client tracer." (spanName .ServiceName .Method.Name) | comment }}
+{{- end }}
+func (c *Client) {{ .Method.VarName }}() endpoint.Endpoint {
+	{{- $timeout := hasTimeout .ServiceName .Method.Name }}
+	{{- $retry := hasRetry .ServiceName .Method.Name }}
+	{{- $traced := isTraced .ServiceName }}
+	{{ if or $timeout $retry $traced }}e := {{ else }}return {{ end }}kithttp.NewExplicitClient(
+		func(ctx context.Context, v interface{}) (*http.Request, error) {
+			req, err := c.c.{{ .RequestInit.Name }}(ctx, v)
+			if err != nil {
+				return nil, err
+			}
+		{{- if .RequestEncoder }}
+			if err := {{ .RequestEncoder }}(c.enc)(ctx, req, v); err != nil {
+				return nil, err
+			}
+		{{- end }}
+			return req, nil
+		},
+		{{ .ResponseDecoder }}(c.dec),
+	{{- if $traced }}
+		append({{ .Method.VarName }}TraceClientOptions(c.tracer, c.logger), c.options...)...,
+	{{- else }}
+		c.options...,
+	{{- end }}
+	).Endpoint()
+{{- if $timeout }}
+	e = Timeout{{ .Method.VarName }}Endpoint()(e)
+{{- end }}
+{{- if $retry }}
+	e = Retry{{ .Method.VarName }}Endpoint()(e)
+{{- end }}
+{{- if $traced }}
+	e = Trace{{ .Method.VarName }}Endpoint(c.tracer)(e)
+{{- end }}
+{{- if or $timeout $retry $traced }}
+	return e
+{{- end }}
+}
+`
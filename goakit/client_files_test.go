@@ -0,0 +1,64 @@
+package goakit
+
+import (
+	"testing"
+
+	"goa.design/goa/expr"
+	"goa.design/plugins/goakit/testdata"
+)
+
+func TestClientFiles(t *testing.T) {
+	cases := map[string]struct {
+		DSL  func()
+		Code map[string][]string
+	}{
+		"multi-endpoints": {
+			DSL: testdata.MultiEndpointDSL,
+			Code: map[string][]string{
+				"goakit-client-struct": []string{testdata.MultiEndpointClientStructCode},
+				"goakit-client-method": []string{testdata.Endpoint1ClientMethodCode, testdata.Endpoint2ClientMethodCode},
+			},
+		},
+		"policy": {
+			DSL: testdata.PolicyDSL,
+			Code: map[string][]string{
+				"goakit-client-method": []string{testdata.PolicyMethodClientMethodCode, testdata.NoPolicyMethodClientMethodCode},
+			},
+		},
+		"tracing": {
+			DSL: testdata.TracingDSL,
+			Code: map[string][]string{
+				"goakit-client-struct": []string{testdata.TracingClientStructCode},
+				"goakit-client-method": []string{testdata.TracingMethodClientMethodCode},
+			},
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			runDSL(t, c.DSL)
+			fs := ClientFiles("", expr.Root)
+			if len(fs) != 1 {
+				t.Fatalf("got %d files, expected 1", len(fs))
+			}
+			for sec, secCode := range c.Code {
+				testCode(t, fs[0], sec, secCode)
+			}
+		})
+	}
+}
+
+func TestClientFilesSkipped(t *testing.T) {
+	cases := map[string]func(){
+		"file-server": testdata.FileServerDSL,
+		"multipart":   testdata.MultipartDSL,
+		"streaming":   testdata.StreamingDSL,
+	}
+	for name, dsl := range cases {
+		t.Run(name, func(t *testing.T) {
+			runDSL(t, dsl)
+			if fs := ClientFiles("", expr.Root); len(fs) != 0 {
+				t.Errorf("got %d files, expected none", len(fs))
+			}
+		})
+	}
+}
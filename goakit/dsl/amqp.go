@@ -0,0 +1,45 @@
+package dsl
+
+import (
+	"goa.design/goa/eval"
+	goaexpr "goa.design/goa/expr"
+	"goa.design/plugins/goakit/expr"
+)
+
+// AMQP serves the method over AMQP using the go-kit AMQP transport. The
+// requests are fire-and-forget: the plugin generates a go-kit AMQP publisher
+// that publishes the JSON encoded payloads to the given exchange without
+// waiting for a response and a subscriber that consumes the payloads from the
+// given queue. The method results and errors are discarded.
+//
+// AMQP must appear in a Method expression.
+//
+// AMQP takes the exchange and the queue names as arguments. The queue name is
+// also the routing key of the published messages, the exchange may be empty
+// to use the AMQP default exchange. The plugin does not declare the exchange
+// and queue nor bind them.
+//
+// Example:
+//
+//    import goakit "goa.design/plugins/goakit/dsl"
+//
+//    var _ = Service("archiver", func() {
+//        Method("archive", func() {
+//            goakit.AMQP("archiver", "archiver.archive")
+//        })
+//    })
+//
+func AMQP(exchange, queue string) {
+	if queue == "" {
+		eval.ReportError("AMQP queue cannot be empty")
+		return
+	}
+	m, ok := eval.Current().(*goaexpr.MethodExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	s := expr.MethodSettings(m)
+	s.AMQPExchange = exchange
+	s.AMQPQueue = queue
+}
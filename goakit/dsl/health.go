@@ -0,0 +1,42 @@
+package dsl
+
+import (
+	"goa.design/goa/eval"
+	goaexpr "goa.design/goa/expr"
+	"goa.design/plugins/goakit/expr"
+)
+
+// Health adds a "health" service to the design. The service defines two
+// methods:
+//
+//    - "liveness" served on "GET /livez" always succeeds while the server is
+//      running.
+//    - "readiness" served on "GET /readyz" succeeds if all the dependency
+//      checkers registered by the server succeed and returns a "not_ready"
+//      error (HTTP status code 503) otherwise.
+//
+// Both methods return a "HealthStatus" type which lists the status of each
+// checker. The plugin generates the "kithealth" package which implements the
+// service and exposes a Register method used to register the checkers.
+//
+// Health must appear in the API expression. The health service is added to the
+// design once all the DSLs have run, the design must not define a service named
+// "health".
+//
+// Health takes no argument.
+//
+// Example:
+//
+//    import goakit "goa.design/plugins/goakit/dsl"
+//
+//    var _ = API("fetcher", func() {
+//        goakit.Health()
+//    })
+//
+func Health() {
+	if _, ok := eval.Current().(*goaexpr.APIExpr); !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	expr.Root.Health = true
+}
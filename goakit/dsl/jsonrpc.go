@@ -0,0 +1,33 @@
+package dsl
+
+import (
+	"goa.design/goa/eval"
+	goaexpr "goa.design/goa/expr"
+	"goa.design/plugins/goakit/expr"
+)
+
+// JSONRPC serves the service methods over JSON-RPC 2.0 using the go-kit
+// JSON-RPC transport. The plugin generates a go-kit JSON-RPC server and
+// clients that encode the method payloads and results using JSON. The
+// JSON-RPC method names are the service method names.
+//
+// JSONRPC must appear in a Service expression.
+//
+// JSONRPC takes no argument.
+//
+// Example:
+//
+//    import goakit "goa.design/plugins/goakit/dsl"
+//
+//    var _ = Service("calc", func() {
+//        goakit.JSONRPC()
+//    })
+//
+func JSONRPC() {
+	switch e := eval.Current().(type) {
+	case *goaexpr.ServiceExpr:
+		expr.ServiceSettings(e).JSONRPC = true
+	default:
+		eval.IncompatibleDSL()
+	}
+}
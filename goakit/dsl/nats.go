@@ -0,0 +1,47 @@
+package dsl
+
+import (
+	"goa.design/goa/eval"
+	goaexpr "goa.design/goa/expr"
+	"goa.design/plugins/goakit/expr"
+)
+
+// NATS serves the service methods over NATS request/reply using the go-kit
+// NATS transport. The plugin generates go-kit NATS subscribers and publishers
+// that encode the method payloads and results using JSON.
+//
+// NATS must appear in a Service or Method expression.
+//
+// When used in a Service expression NATS takes the prefix of the subjects
+// the methods are served on as argument, the subject of each method is the
+// prefix followed by a dot and the method name. When used in a Method
+// expression NATS takes the method subject as argument, overriding the
+// subject computed from the service prefix if any.
+//
+// Example:
+//
+//    import goakit "goa.design/plugins/goakit/dsl"
+//
+//    var _ = Service("archiver", func() {
+//        goakit.NATS("archiver") // Serve "read" on subject "archiver.read"
+//        Method("archive", func() {
+//            goakit.NATS("archiver.archive.v1")
+//        })
+//        Method("read", func() {
+//        })
+//    })
+//
+func NATS(subject string) {
+	if subject == "" {
+		eval.ReportError("NATS subject cannot be empty")
+		return
+	}
+	switch e := eval.Current().(type) {
+	case *goaexpr.ServiceExpr:
+		expr.ServiceSettings(e).NATSSubjectPrefix = subject
+	case *goaexpr.MethodExpr:
+		expr.MethodSettings(e).NATSSubject = subject
+	default:
+		eval.IncompatibleDSL()
+	}
+}
@@ -0,0 +1,85 @@
+package dsl
+
+import (
+	"time"
+
+	"goa.design/goa/eval"
+	goaexpr "goa.design/goa/expr"
+	"goa.design/plugins/goakit/expr"
+)
+
+// Timeout sets the maximum duration of the method requests. The plugin
+// generates server and client endpoint middlewares that set the request
+// context deadline accordingly. The go-kit HTTP clients generated by the
+// plugin apply the client middleware to each request attempt.
+//
+// Timeout must appear in a Method expression.
+//
+// Timeout takes the maximum duration of the requests as argument.
+//
+// Example:
+//
+//    import goakit "goa.design/plugins/goakit/dsl"
+//
+//    var _ = Service("archiver", func() {
+//        Method("archive", func() {
+//            goakit.Timeout(5 * time.Second)
+//        })
+//    })
+//
+func Timeout(d time.Duration) {
+	if d <= 0 {
+		eval.ReportError("timeout must be positive, got %s", d)
+		return
+	}
+	m, ok := eval.Current().(*goaexpr.MethodExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	expr.MethodSettings(m).Timeout = d
+}
+
+// Retry makes the clients retry the method requests that fail with an error
+// marked as Temporary or Timeout in the design. The plugin generates a client
+// endpoint middleware that retries the requests up to max times, waiting for
+// backoff before each retry. The go-kit HTTP clients generated by the plugin
+// apply the middleware.
+//
+// Retry must appear in a Method expression. The method must define at least
+// one error marked as Temporary or Timeout.
+//
+// Retry takes the maximum number of retries and the duration to wait before
+// each retry as arguments.
+//
+// Example:
+//
+//    import goakit "goa.design/plugins/goakit/dsl"
+//
+//    var _ = Service("archiver", func() {
+//        Method("archive", func() {
+//            goakit.Retry(3, 100*time.Millisecond)
+//            Error("unavailable", func() {
+//                Temporary()
+//            })
+//        })
+//    })
+//
+func Retry(max int, backoff time.Duration) {
+	if max <= 0 {
+		eval.ReportError("maximum number of retries must be positive, got %d", max)
+		return
+	}
+	if backoff < 0 {
+		eval.ReportError("retry backoff cannot be negative, got %s", backoff)
+		return
+	}
+	m, ok := eval.Current().(*goaexpr.MethodExpr)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	s := expr.MethodSettings(m)
+	s.RetryMax = max
+	s.RetryBackoff = backoff
+}
@@ -0,0 +1,44 @@
+package dsl
+
+import (
+	"goa.design/goa/eval"
+	goaexpr "goa.design/goa/expr"
+	"goa.design/plugins/goakit/expr"
+
+	// Register code generators for the goakit plugin
+	_ "goa.design/plugins/goakit"
+)
+
+// Tracing enables distributed tracing for the service endpoints. The plugin
+// generates go-kit HTTP server and client options that extract and inject the
+// OpenTracing span context from and into the HTTP requests together with
+// endpoint middlewares that create one span per request. Spans are named after
+// the service and method names, e.g. "fetcher.fetch".
+//
+// Tracing must appear in API or Service expression. Using Tracing in the API
+// expression enables tracing for all the services.
+//
+// Tracing takes no argument.
+//
+// Example:
+//
+//    import goakit "goa.design/plugins/goakit/dsl"
+//
+//    var _ = API("fetcher", func() {
+//        goakit.Tracing() // Trace all the services endpoints
+//    })
+//
+//    var _ = Service("archiver", func() {
+//        goakit.Tracing() // Trace the archiver service endpoints only
+//    })
+//
+func Tracing() {
+	switch e := eval.Current().(type) {
+	case *goaexpr.APIExpr:
+		expr.Root.Tracing = true
+	case *goaexpr.ServiceExpr:
+		expr.ServiceSettings(e).Tracing = true
+	default:
+		eval.IncompatibleDSL()
+	}
+}
@@ -41,6 +41,10 @@ func serverEncodeDecode(genpkg string, svc *expr.HTTPServiceExpr) *codegen.File
 	}
 
 	for _, e := range data.Endpoints {
+		if isStreaming(e) {
+			// Streaming endpoints are served by the goa handlers.
+			continue
+		}
 		sections = append(sections, &codegen.SectionTemplate{
 			Name:   "goakit-response-encoder",
 			Source: responseEncoderT,
@@ -86,6 +90,9 @@ func clientEncodeDecode(genpkg string, svc *expr.HTTPServiceExpr) *codegen.File
 	}
 
 	for _, e := range data.Endpoints {
+		if isStreaming(e) {
+			continue
+		}
 		if e.RequestEncoder != "" {
 			sections = append(sections, &codegen.SectionTemplate{
 				Name:   "goakit-request-encoder",
@@ -93,22 +100,34 @@ func clientEncodeDecode(genpkg string, svc *expr.HTTPServiceExpr) *codegen.File
 				Data:   e,
 			})
 		}
-		if e.Result != nil || len(e.Errors) > 0 {
-			sections = append(sections, &codegen.SectionTemplate{
-				Name:   "goakit-response-decoder",
-				Source: responseDecoderT,
-				Data:   e,
-			})
-		}
+		// goa generates a response decoder for all the methods, it checks
+		// the response status code even if the method has no result.
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "goakit-response-decoder",
+			Source: responseDecoderT,
+			Data:   e,
+		})
 	}
 
 	return &codegen.File{Path: path, SectionTemplates: sections}
 }
 
+// isStreaming returns true if the given endpoint uses websocket streaming.
+// go-kit HTTP transports do not support streaming so the generated code falls
+// back to the goa handlers for these endpoints.
+func isStreaming(e *httpcodegen.EndpointData) bool {
+	return e.ServerStream != nil || e.ClientStream != nil
+}
+
 // input: EndpointData
 const requestEncoderT = `{{ printf "%s returns a go-kit EncodeRequestFunc suitable for encoding %s %s requests." .RequestEncoder .ServiceName .Method.Name | comment }}
+{{- if .MultipartRequestEncoder }}
+func {{ .RequestEncoder }}({{ .MultipartRequestEncoder.VarName }} client.{{ .MultipartRequestEncoder.FuncName }}) kithttp.EncodeRequestFunc {
+	enc := client.{{ .RequestEncoder }}(client.{{ .MultipartRequestEncoder.InitName }}({{ .MultipartRequestEncoder.VarName }}))
+{{- else }}
 func {{ .RequestEncoder }}(encoder func(*http.Request) goahttp.Encoder) kithttp.EncodeRequestFunc {
 	enc := client.{{ .RequestEncoder }}(encoder)
+{{- end }}
 	return func(_ context.Context, r *http.Request, v interface{}) error {
 		return enc(r, v)
 	}
@@ -117,8 +136,13 @@ func {{ .RequestEncoder }}(encoder func(*http.Request) goahttp.Encoder) kithttp.
 
 // input: EndpointData
 const requestDecoderT = `{{ printf "%s returns a go-kit DecodeRequestFunc suitable for decoding %s %s requests." .RequestDecoder .ServiceName .Method.Name | comment }}
+{{- if .MultipartRequestDecoder }}
+func {{ .RequestDecoder }}(mux goahttp.Muxer, {{ .MultipartRequestDecoder.VarName }} server.{{ .MultipartRequestDecoder.FuncName }}) kithttp.DecodeRequestFunc {
+	dec := server.{{ .RequestDecoder }}(mux, server.{{ .MultipartRequestDecoder.InitName }}(mux, {{ .MultipartRequestDecoder.VarName }}))
+{{- else }}
 func {{ .RequestDecoder }}(mux goahttp.Muxer, decoder func(*http.Request) goahttp.Decoder) kithttp.DecodeRequestFunc {
 	dec := server.{{ .RequestDecoder }}(mux, decoder)
+{{- end }}
 	return func(ctx context.Context, r *http.Request) (interface{}, error) {
 		r = r.WithContext(ctx)
 		return dec(r)
@@ -6,7 +6,6 @@ import (
 
 	"goa.design/goa/codegen"
 	"goa.design/goa/expr"
-	httpcodegen "goa.design/goa/http/codegen"
 	"goa.design/plugins/goakit/testdata"
 )
 
@@ -47,10 +46,24 @@ func TestServerEncodeDecode(t *testing.T) {
 				"goakit-error-encoder":    []string{testdata.Endpoint1GoakitErrorEncoderCode, testdata.Endpoint2GoakitErrorEncoderCode},
 			},
 		},
+		"multipart": {
+			DSL: testdata.MultipartDSL,
+			Code: map[string][]string{
+				"goakit-request-decoder": []string{testdata.MultipartMethodGoakitRequestDecoderCode},
+			},
+		},
+		"streaming": {
+			DSL: testdata.StreamingDSL,
+			Code: map[string][]string{
+				"goakit-response-encoder": []string{testdata.NonStreamingMethodGoakitResponseEncoderCode},
+				"goakit-request-decoder":  []string{},
+				"goakit-error-encoder":    []string{},
+			},
+		},
 	}
 	for name, c := range cases {
 		t.Run(name, func(t *testing.T) {
-			httpcodegen.RunHTTPDSL(t, c.DSL)
+			runDSL(t, c.DSL)
 			fs := EncodeDecodeFiles("", expr.Root)
 			if len(fs) != 2 {
 				t.Fatalf("got %d files, expected 2", len(fs))
@@ -104,10 +117,23 @@ func TestClientEncodeDecode(t *testing.T) {
 				"goakit-request-encoder":  []string{testdata.Endpoint1GoakitRequestEncoderCode},
 			},
 		},
+		"multipart": {
+			DSL: testdata.MultipartDSL,
+			Code: map[string][]string{
+				"goakit-request-encoder": []string{testdata.MultipartMethodGoakitRequestEncoderCode},
+			},
+		},
+		"streaming": {
+			DSL: testdata.StreamingDSL,
+			Code: map[string][]string{
+				"goakit-response-decoder": []string{},
+				"goakit-request-encoder":  []string{},
+			},
+		},
 	}
 	for name, c := range cases {
 		t.Run(name, func(t *testing.T) {
-			httpcodegen.RunHTTPDSL(t, c.DSL)
+			runDSL(t, c.DSL)
 			fs := EncodeDecodeFiles("", expr.Root)
 			if len(fs) != 2 {
 				t.Fatalf("got %d files, expected 2", len(fs))
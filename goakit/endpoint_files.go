@@ -0,0 +1,153 @@
+package goakit
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"goa.design/goa/codegen"
+	"goa.design/goa/expr"
+	httpcodegen "goa.design/goa/http/codegen"
+)
+
+// endpointData contains the data needed to render the go-kit endpoint adapter
+// templates of a method.
+type endpointData struct {
+	*httpcodegen.EndpointData
+	// PayloadRef is the reference to the payload type, empty if the method
+	// has no payload.
+	PayloadRef string
+	// ResultRef is the reference to the result type, empty if the method
+	// has no result.
+	ResultRef string
+}
+
+// EndpointFiles produces the files defining the go-kit endpoint adapters of the
+// services.
+func EndpointFiles(genpkg string, root *expr.RootExpr) []*codegen.File {
+	var fw []*codegen.File
+	for _, svc := range root.API.HTTP.Services {
+		if f := endpointSet(genpkg, svc); f != nil {
+			fw = append(fw, f)
+		}
+	}
+	return fw
+}
+
+// endpointSet returns the file defining the go-kit endpoint constructors and
+// endpoint set of the given service. It returns nil if the service has no
+// method or if the endpoint set cannot implement the service interface, see
+// hasEndpointSet.
+func endpointSet(genpkg string, svc *expr.HTTPServiceExpr) *codegen.File {
+	data := httpcodegen.HTTPServices.Get(svc.Name())
+	if !hasEndpointSet(data) {
+		return nil
+	}
+	path := filepath.Join(codegen.Gendir, codegen.SnakeCase(svc.Name()), "kitendpoint", "endpoint.go")
+	title := fmt.Sprintf("%s go-kit endpoints", svc.Name())
+	sections := []*codegen.SectionTemplate{
+		codegen.Header(title, "kitendpoint", []*codegen.ImportSpec{
+			{Path: "context"},
+			{Path: "github.com/go-kit/kit/endpoint"},
+			{Path: genpkg + "/" + codegen.SnakeCase(svc.Name()), Name: data.Service.PkgName},
+		}),
+		{
+			Name:   "goakit-endpoint-set",
+			Source: endpointSetT,
+			Data:   data,
+		},
+	}
+	eps := make([]*endpointData, len(data.Endpoints))
+	for i, e := range data.Endpoints {
+		eps[i] = &endpointData{EndpointData: e, PayloadRef: e.Payload.Ref}
+		if e.Result != nil {
+			eps[i].ResultRef = e.Result.Ref
+		}
+	}
+	for _, e := range eps {
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "goakit-make-endpoint",
+			Source: makeEndpointT,
+			Data:   e,
+		})
+	}
+	for _, e := range eps {
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "goakit-endpoint-set-method",
+			Source: endpointSetMethodT,
+			Data:   e,
+		})
+	}
+
+	return &codegen.File{Path: path, SectionTemplates: sections}
+}
+
+// hasEndpointSet returns true if the endpoint set of the given service can
+// implement the service interface, that is if the service has at least one
+// method and if no method uses streaming, views or security: the
+// corresponding service interface methods accept or return values that are
+// not carried by the endpoint requests and responses.
+func hasEndpointSet(data *httpcodegen.ServiceData) bool {
+	if len(data.Endpoints) == 0 {
+		return false
+	}
+	for _, e := range data.Endpoints {
+		if isStreaming(e) || e.Method.ViewedResult != nil || len(e.Method.Requirements) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// input: ServiceData
+const endpointSetT = `{{ printf "Set collects the go-kit endpoints of the %s service. Set implements the service interface on top of the endpoints so that they can be used anywhere the service is expected, e.g. to make requests using go-kit clients." .Service.Name | comment }}
+type Set struct {
+{{- range .Endpoints }}
+	{{ .Method.VarName }}Endpoint endpoint.Endpoint
+{{- end }}
+}
+
+// Set implements the service interface.
+var _ {{ .Service.PkgName }}.Service = Set{}
+
+// NewSet returns the set of go-kit endpoints that call the methods of svc.
+func NewSet(svc {{ .Service.PkgName }}.Service) Set {
+	return Set{
+	{{- range .Endpoints }}
+		{{ .Method.VarName }}Endpoint: Make{{ .Method.VarName }}Endpoint(svc),
+	{{- end }}
+	}
+}
+`
+
+// input: endpointData
+const makeEndpointT = `{{ printf "Make%sEndpoint returns a go-kit endpoint that calls the %s service %s method." .Method.VarName .ServiceName .Method.Name | comment }}
+func Make{{ .Method.VarName }}Endpoint(svc {{ .ServicePkgName }}.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+{{- if .PayloadRef }}
+		p := request.({{ .PayloadRef }})
+{{- end }}
+{{- if .ResultRef }}
+		return svc.{{ .Method.VarName }}(ctx{{ if .PayloadRef }}, p{{ end }})
+{{- else }}
+		return nil, svc.{{ .Method.VarName }}(ctx{{ if .PayloadRef }}, p{{ end }})
+{{- end }}
+	}
+}
+`
+
+// input: endpointData
+const endpointSetMethodT = `{{ printf "%s calls the %s endpoint." .Method.VarName .Method.Name | comment }}
+func (s Set) {{ .Method.VarName }}(ctx context.Context{{ if .PayloadRef }}, p {{ .PayloadRef }}{{ end }}) ({{ if .ResultRef }}res {{ .ResultRef }}, {{ end }}err error) {
+{{- if .ResultRef }}
+	var resp interface{}
+	resp, err = s.{{ .Method.VarName }}Endpoint(ctx, {{ if .PayloadRef }}p{{ else }}nil{{ end }})
+	if err != nil {
+		return
+	}
+	return resp.({{ .ResultRef }}), nil
+{{- else }}
+	_, err = s.{{ .Method.VarName }}Endpoint(ctx, {{ if .PayloadRef }}p{{ else }}nil{{ end }})
+	return
+{{- end }}
+}
+`
@@ -0,0 +1,58 @@
+package goakit
+
+import (
+	"testing"
+
+	"goa.design/goa/expr"
+	"goa.design/plugins/goakit/testdata"
+)
+
+func TestEndpointFiles(t *testing.T) {
+	cases := map[string]struct {
+		DSL  func()
+		Code map[string][]string
+	}{
+		"simple": {
+			DSL: testdata.SimpleServiceDSL,
+			Code: map[string][]string{
+				"goakit-make-endpoint":       []string{testdata.SimpleMethodMakeEndpointCode},
+				"goakit-endpoint-set-method": []string{testdata.SimpleMethodEndpointSetMethodCode},
+			},
+		},
+		"transport": {
+			DSL: testdata.TransportDSL,
+			Code: map[string][]string{
+				"goakit-endpoint-set":        []string{testdata.TransportServiceEndpointSetCode},
+				"goakit-make-endpoint":       []string{testdata.TransportMethodMakeEndpointCode},
+				"goakit-endpoint-set-method": []string{testdata.TransportMethodEndpointSetMethodCode},
+			},
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			runDSL(t, c.DSL)
+			fs := EndpointFiles("", expr.Root)
+			if len(fs) != 1 {
+				t.Fatalf("got %d files, expected 1", len(fs))
+			}
+			for sec, secCode := range c.Code {
+				testCode(t, fs[0], sec, secCode)
+			}
+		})
+	}
+}
+
+func TestEndpointFilesSkipped(t *testing.T) {
+	cases := map[string]func(){
+		"file-server": testdata.FileServerDSL,
+		"streaming":   testdata.StreamingDSL,
+	}
+	for name, dsl := range cases {
+		t.Run(name, func(t *testing.T) {
+			runDSL(t, dsl)
+			if fs := EndpointFiles("", expr.Root); len(fs) != 0 {
+				t.Errorf("got %d files, expected none", len(fs))
+			}
+		})
+	}
+}
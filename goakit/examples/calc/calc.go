@@ -9,7 +9,7 @@ import (
 )
 
 // calc service example implementation.
-// The example methods log the requests and return zero values.
+// The example methods log the requests.
 type calcSvc struct {
 	logger log.Logger
 }
@@ -22,5 +22,5 @@ func NewCalc(logger log.Logger) calcsvc.Service {
 // Add adds up the two integer parameters and returns the results.
 func (s *calcSvc) Add(ctx context.Context, p *calcsvc.AddPayload) (res int, err error) {
 	s.logger.Log("info", fmt.Sprintf("calc.add"))
-	return
+	return p.A + p.B, nil
 }
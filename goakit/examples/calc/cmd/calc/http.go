@@ -2,7 +2,6 @@ package main
 
 import (
 	"context"
-	"fmt"
 	"net/http"
 	"net/url"
 	"os"
@@ -11,6 +10,7 @@ import (
 
 	"github.com/go-kit/kit/endpoint"
 	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
 	kithttp "github.com/go-kit/kit/transport/http"
 	goahttp "goa.design/goa/http"
 	httpmdlwr "goa.design/goa/http/middleware"
@@ -51,9 +51,10 @@ func handleHTTPServer(ctx context.Context, u *url.URL, calcEndpoints *calcsvc.En
 	{
 		eh := errorHandler(logger)
 		calcAddHandler = kithttp.NewServer(
-			endpoint.Endpoint(calcEndpoints.Add),
+			calcsvckitsvr.LogAddEndpoint(logger)(endpoint.Endpoint(calcEndpoints.Add)),
 			calcsvckitsvr.DecodeAddRequest(mux, dec),
 			calcsvckitsvr.EncodeAddResponse(enc),
+			calcsvckitsvr.ServerOptions()...,
 		)
 		calcServer = calcsvcsvr.New(calcEndpoints, mux, dec, enc, eh)
 	}
@@ -76,7 +77,7 @@ func handleHTTPServer(ctx context.Context, u *url.URL, calcEndpoints *calcsvc.En
 	// configure the server as required by your service.
 	srv := &http.Server{Addr: u.Host, Handler: handler}
 	for _, m := range calcServer.Mounts {
-		logger.Log("info", fmt.Sprintf("HTTP %q mounted on %s %s", m.Method, m.Verb, m.Pattern))
+		level.Info(logger).Log("msg", "HTTP endpoint mounted", "method", m.Method, "verb", m.Verb, "pattern", m.Pattern)
 	}
 
 	(*wg).Add(1)
@@ -85,13 +86,13 @@ func handleHTTPServer(ctx context.Context, u *url.URL, calcEndpoints *calcsvc.En
 
 		// Start HTTP server in a separate goroutine.
 		go func() {
-			logger.Log("info", fmt.Sprintf("HTTP server listening on %q", u.Host))
+			level.Info(logger).Log("msg", "HTTP server listening", "addr", u.Host)
 			errc <- srv.ListenAndServe()
 		}()
 
 		select {
 		case <-ctx.Done():
-			logger.Log("info", fmt.Sprintf("shutting down HTTP server at %q", u.Host))
+			level.Info(logger).Log("msg", "shutting down HTTP server", "addr", u.Host)
 
 			// Shutdown gracefully with a 30s timeout.
 			ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
@@ -110,6 +111,6 @@ func errorHandler(logger log.Logger) func(context.Context, http.ResponseWriter,
 	return func(ctx context.Context, w http.ResponseWriter, err error) {
 		id := ctx.Value(middleware.RequestIDKey).(string)
 		w.Write([]byte("[" + id + "] encoding: " + err.Error()))
-		logger.Log("info", fmt.Sprintf("[%s] ERROR: %s", id, err.Error()))
+		level.Error(logger).Log("msg", "request failed", "request_id", id, "err", err.Error())
 	}
 }
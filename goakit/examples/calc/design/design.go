@@ -3,6 +3,7 @@ package design
 import (
 	. "goa.design/goa/dsl"
 	_ "goa.design/plugins/goakit"
+	goakit "goa.design/plugins/goakit/dsl"
 )
 
 var _ = API("calc", func() {
@@ -12,6 +13,7 @@ var _ = API("calc", func() {
 
 var _ = Service("calc", func() {
 	Description("The calc service exposes public endpoints that uses go-kit.")
+	goakit.NATS("calc") // "add" is also served on subject "calc.add"
 	Method("add", func() {
 		Description("Add adds up the two integer parameters and returns the results.")
 		Payload(func() {
@@ -17,6 +17,7 @@ import (
 	"github.com/go-kit/kit/endpoint"
 	goahttp "goa.design/goa/http"
 	calcsvcc "goa.design/plugins/goakit/examples/calc/gen/http/calc/client"
+	calcsvckc "goa.design/plugins/goakit/examples/calc/gen/http/calc/kitclient"
 )
 
 // UsageCommands returns the set of commands and sub-commands using the format
@@ -113,7 +114,7 @@ func ParseEndpoint(
 	{
 		switch svcn {
 		case "calc":
-			c := calcsvcc.NewClient(scheme, host, doer, enc, dec, restore)
+			c := calcsvckc.NewClient(scheme, host, doer, enc, dec, restore)
 			switch epn {
 			case "add":
 				endpoint = c.Add()
@@ -0,0 +1,90 @@
+package calc_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	natstest "github.com/nats-io/nats-server/v2/test"
+	"github.com/nats-io/nats.go"
+	goa "goa.design/goa"
+	"goa.design/plugins/goakit/examples/calc"
+	calcsvc "goa.design/plugins/goakit/examples/calc/gen/calc"
+	calcnatskc "goa.design/plugins/goakit/examples/calc/gen/nats/calc/kitclient"
+	calcnatskitsvr "goa.design/plugins/goakit/examples/calc/gen/nats/calc/kitserver"
+)
+
+// Example_nats serves the calc service over NATS using an embedded NATS server
+// and makes a request using the generated go-kit NATS publisher.
+func Example_nats() {
+	// Start an embedded NATS server listening on a random port.
+	srv := natstest.RunRandClientPortServer()
+	defer srv.Shutdown()
+
+	nc, err := nats.Connect(srv.ClientURL())
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer nc.Close()
+
+	// Subscribe the service endpoints to the "calc.add" subject.
+	endpoints := calcsvc.NewEndpoints(calc.NewCalc(log.NewNopLogger()))
+	if _, err := calcnatskitsvr.Subscribe(nc, endpoints); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	// Make the request using a goa client built on the go-kit publisher.
+	c := calcsvc.NewClient(calcnatskc.NewAddPublisher(nc).Endpoint())
+	res, err := c.Add(context.Background(), &calcsvc.AddPayload{A: 1, B: 2})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(res)
+	// Output: 3
+}
+
+// Example_natsJSON makes requests using the JSON encoding of the calc add
+// payloads. The subscriber validates the decoded payloads and the publisher
+// decodes the validation errors into goa service errors.
+func Example_natsJSON() {
+	srv := natstest.RunRandClientPortServer()
+	defer srv.Shutdown()
+
+	nc, err := nats.Connect(srv.ClientURL())
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer nc.Close()
+
+	endpoints := calcsvc.NewEndpoints(calc.NewCalc(log.NewNopLogger()))
+	if _, err := calcnatskitsvr.Subscribe(nc, endpoints); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	for _, req := range []string{`{"a":1,"b":2}`, `{"a":1}`} {
+		msg, err := nc.Request("calc.add", []byte(req), time.Second)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		res, err := calcnatskc.DecodeAddResponse(context.Background(), msg)
+		if err != nil {
+			if serr, ok := err.(*goa.ServiceError); ok {
+				fmt.Println(serr.Name)
+				continue
+			}
+			fmt.Println(err)
+			return
+		}
+		fmt.Println(res)
+	}
+	// Output:
+	// 3
+	// missing_field
+}
@@ -2,7 +2,6 @@ package main
 
 import (
 	"context"
-	"fmt"
 	"net/http"
 	"net/url"
 	"os"
@@ -11,7 +10,9 @@ import (
 
 	"github.com/go-kit/kit/endpoint"
 	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
 	kithttp "github.com/go-kit/kit/transport/http"
+	opentracing "github.com/opentracing/opentracing-go"
 	goahttp "goa.design/goa/http"
 	httpmdlwr "goa.design/goa/http/middleware"
 	"goa.design/goa/middleware"
@@ -51,26 +52,37 @@ func handleHTTPServer(ctx context.Context, u *url.URL, archiverEndpoints *archiv
 		archiverArchiveHandler *kithttp.Server
 		archiverReadHandler    *kithttp.Server
 		archiverServer         *archiversvcsvr.Server
-		healthShowHandler      *kithttp.Server
+		healthLivenessHandler  *kithttp.Server
+		healthReadinessHandler *kithttp.Server
 		healthServer           *healthsvr.Server
 	)
 	{
 		eh := errorHandler(logger)
+		tracer := opentracing.GlobalTracer()
 		archiverArchiveHandler = kithttp.NewServer(
-			endpoint.Endpoint(archiverEndpoints.Archive),
+			archiversvckitsvr.TraceArchiveEndpoint(tracer)(archiversvckitsvr.LogArchiveEndpoint(logger)(archiversvckitsvr.TimeoutArchiveEndpoint()(endpoint.Endpoint(archiverEndpoints.Archive)))),
 			archiversvckitsvr.DecodeArchiveRequest(mux, dec),
 			archiversvckitsvr.EncodeArchiveResponse(enc),
+			archiversvckitsvr.ServerOptions(archiversvckitsvr.ArchiveTraceServerOptions(tracer, logger)...)...,
 		)
 		archiverReadHandler = kithttp.NewServer(
-			endpoint.Endpoint(archiverEndpoints.Read),
+			archiversvckitsvr.TraceReadEndpoint(tracer)(archiversvckitsvr.LogReadEndpoint(logger)(endpoint.Endpoint(archiverEndpoints.Read))),
 			archiversvckitsvr.DecodeReadRequest(mux, dec),
 			archiversvckitsvr.EncodeReadResponse(enc),
+			archiversvckitsvr.ServerOptions(archiversvckitsvr.ReadTraceServerOptions(tracer, logger)...)...,
 		)
 		archiverServer = archiversvcsvr.New(archiverEndpoints, mux, dec, enc, eh)
-		healthShowHandler = kithttp.NewServer(
-			endpoint.Endpoint(healthEndpoints.Show),
+		healthLivenessHandler = kithttp.NewServer(
+			healthkitsvr.LogLivenessEndpoint(logger)(endpoint.Endpoint(healthEndpoints.Liveness)),
 			func(context.Context, *http.Request) (request interface{}, err error) { return nil, nil },
-			healthkitsvr.EncodeShowResponse(enc),
+			healthkitsvr.EncodeLivenessResponse(enc),
+			healthkitsvr.ServerOptions()...,
+		)
+		healthReadinessHandler = kithttp.NewServer(
+			healthkitsvr.LogReadinessEndpoint(logger)(endpoint.Endpoint(healthEndpoints.Readiness)),
+			func(context.Context, *http.Request) (request interface{}, err error) { return nil, nil },
+			healthkitsvr.EncodeReadinessResponse(enc),
+			healthkitsvr.ServerOptions()...,
 		)
 		healthServer = healthsvr.New(healthEndpoints, mux, dec, enc, eh)
 	}
@@ -78,7 +90,8 @@ func handleHTTPServer(ctx context.Context, u *url.URL, archiverEndpoints *archiv
 	// Configure the mux.
 	archiversvckitsvr.MountArchiveHandler(mux, archiverArchiveHandler)
 	archiversvckitsvr.MountReadHandler(mux, archiverReadHandler)
-	healthkitsvr.MountShowHandler(mux, healthShowHandler)
+	healthkitsvr.MountLivenessHandler(mux, healthLivenessHandler)
+	healthkitsvr.MountReadinessHandler(mux, healthReadinessHandler)
 
 	// Wrap the multiplexer with additional middlewares. Middlewares mounted
 	// here apply to all the service endpoints.
@@ -95,10 +108,10 @@ func handleHTTPServer(ctx context.Context, u *url.URL, archiverEndpoints *archiv
 	// configure the server as required by your service.
 	srv := &http.Server{Addr: u.Host, Handler: handler}
 	for _, m := range archiverServer.Mounts {
-		logger.Log("info", fmt.Sprintf("HTTP %q mounted on %s %s", m.Method, m.Verb, m.Pattern))
+		level.Info(logger).Log("msg", "HTTP endpoint mounted", "method", m.Method, "verb", m.Verb, "pattern", m.Pattern)
 	}
 	for _, m := range healthServer.Mounts {
-		logger.Log("info", fmt.Sprintf("HTTP %q mounted on %s %s", m.Method, m.Verb, m.Pattern))
+		level.Info(logger).Log("msg", "HTTP endpoint mounted", "method", m.Method, "verb", m.Verb, "pattern", m.Pattern)
 	}
 
 	(*wg).Add(1)
@@ -107,13 +120,13 @@ func handleHTTPServer(ctx context.Context, u *url.URL, archiverEndpoints *archiv
 
 		// Start HTTP server in a separate goroutine.
 		go func() {
-			logger.Log("info", fmt.Sprintf("HTTP server listening on %q", u.Host))
+			level.Info(logger).Log("msg", "HTTP server listening", "addr", u.Host)
 			errc <- srv.ListenAndServe()
 		}()
 
 		select {
 		case <-ctx.Done():
-			logger.Log("info", fmt.Sprintf("shutting down HTTP server at %q", u.Host))
+			level.Info(logger).Log("msg", "shutting down HTTP server", "addr", u.Host)
 
 			// Shutdown gracefully with a 30s timeout.
 			ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
@@ -132,6 +145,6 @@ func errorHandler(logger log.Logger) func(context.Context, http.ResponseWriter,
 	return func(ctx context.Context, w http.ResponseWriter, err error) {
 		id := ctx.Value(middleware.RequestIDKey).(string)
 		w.Write([]byte("[" + id + "] encoding: " + err.Error()))
-		logger.Log("info", fmt.Sprintf("[%s] ERROR: %s", id, err.Error()))
+		level.Error(logger).Log("msg", "request failed", "request_id", id, "err", err.Error())
 	}
 }
@@ -11,6 +11,7 @@ import (
 	"sync"
 
 	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
 	archiver "goa.design/plugins/goakit/examples/fetcher/archiver"
 	archiversvc "goa.design/plugins/goakit/examples/fetcher/archiver/gen/archiver"
 	health "goa.design/plugins/goakit/examples/fetcher/archiver/gen/health"
@@ -26,6 +27,7 @@ func main() {
 		secureF   = flag.Bool("secure", false, "Use secure scheme (https or grpcs)")
 		dbgF      = flag.Bool("debug", false, "Log request and response bodies")
 	)
+	logFormatF := flag.String("log-format", "logfmt", "Log format (valid values: logfmt, json)")
 	flag.Parse()
 
 	// Setup gokit logger.
@@ -33,7 +35,13 @@ func main() {
 		logger log.Logger
 	)
 	{
-		logger = log.NewLogfmtLogger(os.Stderr)
+		w := log.NewSyncWriter(os.Stderr)
+		switch *logFormatF {
+		case "json":
+			logger = log.NewJSONLogger(w)
+		default:
+			logger = log.NewLogfmtLogger(w)
+		}
 		logger = log.With(logger, "ts", log.DefaultTimestampUTC)
 		logger = log.With(logger, "caller", log.DefaultCaller)
 	}
@@ -104,11 +112,11 @@ func main() {
 	}
 
 	// Wait for signal.
-	logger.Log("info", fmt.Sprintf("exiting (%v)", <-errc))
+	level.Info(logger).Log("msg", "exiting", "reason", <-errc)
 
 	// Send cancellation signal to the goroutines.
 	cancel()
 
 	wg.Wait()
-	logger.Log("info", fmt.Sprintf("exited"))
+	level.Info(logger).Log("msg", "exited")
 }
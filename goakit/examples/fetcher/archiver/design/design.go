@@ -1,22 +1,27 @@
 package design
 
 import (
+	"time"
+
 	. "goa.design/goa/dsl"
-	_ "goa.design/plugins/goakit"
+	goakit "goa.design/plugins/goakit/dsl"
 )
 
 var _ = API("archiver", func() {
 	Title("The goakit example downstream service")
 	Description("Archiver is a service that manages the content of HTTP responses")
+	goakit.Health()
 })
 
 var _ = Service("archiver", func() {
+	goakit.Tracing()
 	HTTP(func() {
 		Path("/archive")
 	})
 
 	Method("archive", func() {
 		Description("Archive HTTP response")
+		goakit.Timeout(5 * time.Second)
 		Payload(ArchivePayload)
 		Result(ArchiveMedia)
 		HTTP(func() {
@@ -77,21 +82,3 @@ var ArchiveMedia = ResultType("application/vnd.goa.archive", func() {
 		Attribute("body")
 	})
 })
-
-var _ = Service("health", func() {
-	HTTP(func() {
-		Path("/health")
-	})
-
-	Method("show", func() {
-		Description("Health check endpoint")
-		Result(String)
-		HTTP(func() {
-			GET("/")
-			Response(func() {
-				Code(StatusOK)
-				ContentType("text/plain")
-			})
-		})
-	})
-})
@@ -0,0 +1,15 @@
+package design
+
+import (
+	"testing"
+
+	"goa.design/plugins/goakit/examples/internal/exampletest"
+)
+
+func TestExample(t *testing.T) {
+	exampletest.Verify(t, exampletest.Example{
+		DesignPkg: "goa.design/plugins/goakit/examples/fetcher/archiver/design",
+		Dir:       "..",
+		Commands:  []string{"gen", "example"},
+	})
+}
@@ -17,7 +17,8 @@ import (
 	"github.com/go-kit/kit/endpoint"
 	goahttp "goa.design/goa/http"
 	archiversvcc "goa.design/plugins/goakit/examples/fetcher/archiver/gen/http/archiver/client"
-	healthc "goa.design/plugins/goakit/examples/fetcher/archiver/gen/http/health/client"
+	archiversvckc "goa.design/plugins/goakit/examples/fetcher/archiver/gen/http/archiver/kitclient"
+	healthkc "goa.design/plugins/goakit/examples/fetcher/archiver/gen/http/health/kitclient"
 )
 
 // UsageCommands returns the set of commands and sub-commands using the format
@@ -26,7 +27,7 @@ import (
 //
 func UsageCommands() string {
 	return `archiver (archive|read)
-health show
+health (liveness|readiness)
 `
 }
 
@@ -36,7 +37,7 @@ func UsageExamples() string {
       "body": "Unde sed nulla.",
       "status": 200
    }'` + "\n" +
-		os.Args[0] + ` health show` + "\n" +
+		os.Args[0] + ` health liveness` + "\n" +
 		""
 }
 
@@ -60,14 +61,17 @@ func ParseEndpoint(
 
 		healthFlags = flag.NewFlagSet("health", flag.ContinueOnError)
 
-		healthShowFlags = flag.NewFlagSet("show", flag.ExitOnError)
+		healthLivenessFlags = flag.NewFlagSet("liveness", flag.ExitOnError)
+
+		healthReadinessFlags = flag.NewFlagSet("readiness", flag.ExitOnError)
 	)
 	archiverFlags.Usage = archiverUsage
 	archiverArchiveFlags.Usage = archiverArchiveUsage
 	archiverReadFlags.Usage = archiverReadUsage
 
 	healthFlags.Usage = healthUsage
-	healthShowFlags.Usage = healthShowUsage
+	healthLivenessFlags.Usage = healthLivenessUsage
+	healthReadinessFlags.Usage = healthReadinessUsage
 
 	if err := flag.CommandLine.Parse(os.Args[1:]); err != nil {
 		return nil, nil, err
@@ -115,8 +119,11 @@ func ParseEndpoint(
 
 		case "health":
 			switch epn {
-			case "show":
-				epf = healthShowFlags
+			case "liveness":
+				epf = healthLivenessFlags
+
+			case "readiness":
+				epf = healthReadinessFlags
 
 			}
 
@@ -141,7 +148,7 @@ func ParseEndpoint(
 	{
 		switch svcn {
 		case "archiver":
-			c := archiversvcc.NewClient(scheme, host, doer, enc, dec, restore)
+			c := archiversvckc.NewClient(scheme, host, doer, enc, dec, restore)
 			switch epn {
 			case "archive":
 				endpoint = c.Archive()
@@ -151,10 +158,13 @@ func ParseEndpoint(
 				data, err = archiversvcc.BuildReadPayload(*archiverReadIDFlag)
 			}
 		case "health":
-			c := healthc.NewClient(scheme, host, doer, enc, dec, restore)
+			c := healthkc.NewClient(scheme, host, doer, enc, dec, restore)
 			switch epn {
-			case "show":
-				endpoint = c.Show()
+			case "liveness":
+				endpoint = c.Liveness()
+				data = nil
+			case "readiness":
+				endpoint = c.Readiness()
 				data = nil
 			}
 		}
@@ -207,23 +217,34 @@ Example:
 
 // healthUsage displays the usage of the health command and its subcommands.
 func healthUsage() {
-	fmt.Fprintf(os.Stderr, `Service is the health service interface.
+	fmt.Fprintf(os.Stderr, `The health service reports the liveness and readiness of the server.
 Usage:
     %s [globalflags] health COMMAND [flags]
 
 COMMAND:
-    show: Health check endpoint
+    liveness: Liveness reports whether the server is running.
+    readiness: Readiness reports whether the server dependencies are ready to serve requests.
 
 Additional help:
     %s health COMMAND --help
 `, os.Args[0], os.Args[0])
 }
-func healthShowUsage() {
-	fmt.Fprintf(os.Stderr, `%s [flags] health show
+func healthLivenessUsage() {
+	fmt.Fprintf(os.Stderr, `%s [flags] health liveness
+
+Liveness reports whether the server is running.
+
+Example:
+    `+os.Args[0]+` health liveness
+`, os.Args[0])
+}
+
+func healthReadinessUsage() {
+	fmt.Fprintf(os.Stderr, `%s [flags] health readiness
 
-Health check endpoint
+Readiness reports whether the server dependencies are ready to serve requests.
 
 Example:
-    `+os.Args[0]+` health show
+    `+os.Args[0]+` health readiness
 `, os.Args[0])
 }
@@ -18,8 +18,13 @@ import (
 
 // Client lists the health service endpoint HTTP clients.
 type Client struct {
-	// Show Doer is the HTTP client used to make requests to the show endpoint.
-	ShowDoer goahttp.Doer
+	// Liveness Doer is the HTTP client used to make requests to the liveness
+	// endpoint.
+	LivenessDoer goahttp.Doer
+
+	// Readiness Doer is the HTTP client used to make requests to the readiness
+	// endpoint.
+	ReadinessDoer goahttp.Doer
 
 	// RestoreResponseBody controls whether the response bodies are reset after
 	// decoding so they can be read again.
@@ -41,7 +46,8 @@ func NewClient(
 	restoreBody bool,
 ) *Client {
 	return &Client{
-		ShowDoer:            doer,
+		LivenessDoer:        doer,
+		ReadinessDoer:       doer,
 		RestoreResponseBody: restoreBody,
 		scheme:              scheme,
 		host:                host,
@@ -50,21 +56,41 @@ func NewClient(
 	}
 }
 
-// Show returns an endpoint that makes HTTP requests to the health service show
-// server.
-func (c *Client) Show() endpoint.Endpoint {
+// Liveness returns an endpoint that makes HTTP requests to the health service
+// liveness server.
+func (c *Client) Liveness() endpoint.Endpoint {
+	var (
+		decodeResponse = DecodeLivenessResponse(c.decoder, c.RestoreResponseBody)
+	)
+	return func(ctx context.Context, v interface{}) (interface{}, error) {
+		req, err := c.BuildLivenessRequest(ctx, v)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := c.LivenessDoer.Do(req)
+
+		if err != nil {
+			return nil, goahttp.ErrRequestError("health", "liveness", err)
+		}
+		return decodeResponse(resp)
+	}
+}
+
+// Readiness returns an endpoint that makes HTTP requests to the health service
+// readiness server.
+func (c *Client) Readiness() endpoint.Endpoint {
 	var (
-		decodeResponse = DecodeShowResponse(c.decoder, c.RestoreResponseBody)
+		decodeResponse = DecodeReadinessResponse(c.decoder, c.RestoreResponseBody)
 	)
 	return func(ctx context.Context, v interface{}) (interface{}, error) {
-		req, err := c.BuildShowRequest(ctx, v)
+		req, err := c.BuildReadinessRequest(ctx, v)
 		if err != nil {
 			return nil, err
 		}
-		resp, err := c.ShowDoer.Do(req)
+		resp, err := c.ReadinessDoer.Do(req)
 
 		if err != nil {
-			return nil, goahttp.ErrRequestError("health", "show", err)
+			return nil, goahttp.ErrRequestError("health", "readiness", err)
 		}
 		return decodeResponse(resp)
 	}
@@ -18,13 +18,13 @@ import (
 	goahttp "goa.design/goa/http"
 )
 
-// BuildShowRequest instantiates a HTTP request object with method and path set
-// to call the "health" service "show" endpoint
-func (c *Client) BuildShowRequest(ctx context.Context, v interface{}) (*http.Request, error) {
-	u := &url.URL{Scheme: c.scheme, Host: c.host, Path: ShowHealthPath()}
+// BuildLivenessRequest instantiates a HTTP request object with method and path
+// set to call the "health" service "liveness" endpoint
+func (c *Client) BuildLivenessRequest(ctx context.Context, v interface{}) (*http.Request, error) {
+	u := &url.URL{Scheme: c.scheme, Host: c.host, Path: LivenessHealthPath()}
 	req, err := http.NewRequest("GET", u.String(), nil)
 	if err != nil {
-		return nil, goahttp.ErrInvalidURL("health", "show", u.String(), err)
+		return nil, goahttp.ErrInvalidURL("health", "liveness", u.String(), err)
 	}
 	if ctx != nil {
 		req = req.WithContext(ctx)
@@ -33,10 +33,10 @@ func (c *Client) BuildShowRequest(ctx context.Context, v interface{}) (*http.Req
 	return req, nil
 }
 
-// DecodeShowResponse returns a decoder for responses returned by the health
-// show endpoint. restoreBody controls whether the response body should be
-// restored after having been read.
-func DecodeShowResponse(decoder func(*http.Response) goahttp.Decoder, restoreBody bool) func(*http.Response) (interface{}, error) {
+// DecodeLivenessResponse returns a decoder for responses returned by the
+// health liveness endpoint. restoreBody controls whether the response body
+// should be restored after having been read.
+func DecodeLivenessResponse(decoder func(*http.Response) goahttp.Decoder, restoreBody bool) func(*http.Response) (interface{}, error) {
 	return func(resp *http.Response) (interface{}, error) {
 		if restoreBody {
 			b, err := ioutil.ReadAll(resp.Body)
@@ -53,17 +53,94 @@ func DecodeShowResponse(decoder func(*http.Response) goahttp.Decoder, restoreBod
 		switch resp.StatusCode {
 		case http.StatusOK:
 			var (
-				body string
+				body LivenessResponseBody
 				err  error
 			)
 			err = decoder(resp).Decode(&body)
 			if err != nil {
-				return nil, goahttp.ErrDecodingError("health", "show", err)
+				return nil, goahttp.ErrDecodingError("health", "liveness", err)
 			}
-			return body, nil
+			err = ValidateLivenessResponseBody(&body)
+			if err != nil {
+				return nil, goahttp.ErrValidationError("health", "liveness", err)
+			}
+			res := NewLivenessHealthStatusOK(&body)
+			return res, nil
+		default:
+			body, _ := ioutil.ReadAll(resp.Body)
+			return nil, goahttp.ErrInvalidResponse("health", "liveness", resp.StatusCode, string(body))
+		}
+	}
+}
+
+// BuildReadinessRequest instantiates a HTTP request object with method and
+// path set to call the "health" service "readiness" endpoint
+func (c *Client) BuildReadinessRequest(ctx context.Context, v interface{}) (*http.Request, error) {
+	u := &url.URL{Scheme: c.scheme, Host: c.host, Path: ReadinessHealthPath()}
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, goahttp.ErrInvalidURL("health", "readiness", u.String(), err)
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+
+	return req, nil
+}
+
+// DecodeReadinessResponse returns a decoder for responses returned by the
+// health readiness endpoint. restoreBody controls whether the response body
+// should be restored after having been read.
+// DecodeReadinessResponse may return the following errors:
+//	- "not_ready" (type *goa.ServiceError): http.StatusServiceUnavailable
+//	- error: internal error
+func DecodeReadinessResponse(decoder func(*http.Response) goahttp.Decoder, restoreBody bool) func(*http.Response) (interface{}, error) {
+	return func(resp *http.Response) (interface{}, error) {
+		if restoreBody {
+			b, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				return nil, err
+			}
+			resp.Body = ioutil.NopCloser(bytes.NewBuffer(b))
+			defer func() {
+				resp.Body = ioutil.NopCloser(bytes.NewBuffer(b))
+			}()
+		} else {
+			defer resp.Body.Close()
+		}
+		switch resp.StatusCode {
+		case http.StatusOK:
+			var (
+				body ReadinessResponseBody
+				err  error
+			)
+			err = decoder(resp).Decode(&body)
+			if err != nil {
+				return nil, goahttp.ErrDecodingError("health", "readiness", err)
+			}
+			err = ValidateReadinessResponseBody(&body)
+			if err != nil {
+				return nil, goahttp.ErrValidationError("health", "readiness", err)
+			}
+			res := NewReadinessHealthStatusOK(&body)
+			return res, nil
+		case http.StatusServiceUnavailable:
+			var (
+				body ReadinessNotReadyResponseBody
+				err  error
+			)
+			err = decoder(resp).Decode(&body)
+			if err != nil {
+				return nil, goahttp.ErrDecodingError("health", "readiness", err)
+			}
+			err = ValidateReadinessNotReadyResponseBody(&body)
+			if err != nil {
+				return nil, goahttp.ErrValidationError("health", "readiness", err)
+			}
+			return nil, NewReadinessNotReady(&body)
 		default:
 			body, _ := ioutil.ReadAll(resp.Body)
-			return nil, goahttp.ErrInvalidResponse("health", "show", resp.StatusCode, string(body))
+			return nil, goahttp.ErrInvalidResponse("health", "readiness", resp.StatusCode, string(body))
 		}
 	}
 }
@@ -7,3 +7,136 @@
 // $(GOPATH)/src/goa.design/plugins/goakit/examples/fetcher/archiver
 
 package client
+
+import (
+	goa "goa.design/goa"
+	health "goa.design/plugins/goakit/examples/fetcher/archiver/gen/health"
+)
+
+// LivenessResponseBody is the type of the "health" service "liveness" endpoint
+// HTTP response body.
+type LivenessResponseBody struct {
+	// Status of the server
+	Status *string `form:"status,omitempty" json:"status,omitempty" xml:"status,omitempty"`
+	// Status of the dependencies indexed by checker name, either "ok" or the
+	// error returned by the checker
+	Checks map[string]string `form:"checks,omitempty" json:"checks,omitempty" xml:"checks,omitempty"`
+}
+
+// ReadinessResponseBody is the type of the "health" service "readiness"
+// endpoint HTTP response body.
+type ReadinessResponseBody struct {
+	// Status of the server
+	Status *string `form:"status,omitempty" json:"status,omitempty" xml:"status,omitempty"`
+	// Status of the dependencies indexed by checker name, either "ok" or the
+	// error returned by the checker
+	Checks map[string]string `form:"checks,omitempty" json:"checks,omitempty" xml:"checks,omitempty"`
+}
+
+// ReadinessNotReadyResponseBody is the type of the "health" service
+// "readiness" endpoint HTTP response body for the "not_ready" error.
+type ReadinessNotReadyResponseBody struct {
+	// Name is the name of this class of errors.
+	Name *string `form:"name,omitempty" json:"name,omitempty" xml:"name,omitempty"`
+	// ID is a unique identifier for this particular occurrence of the problem.
+	ID *string `form:"id,omitempty" json:"id,omitempty" xml:"id,omitempty"`
+	// Message is a human-readable explanation specific to this occurrence of the
+	// problem.
+	Message *string `form:"message,omitempty" json:"message,omitempty" xml:"message,omitempty"`
+	// Is the error temporary?
+	Temporary *bool `form:"temporary,omitempty" json:"temporary,omitempty" xml:"temporary,omitempty"`
+	// Is the error a timeout?
+	Timeout *bool `form:"timeout,omitempty" json:"timeout,omitempty" xml:"timeout,omitempty"`
+	// Is the error a server-side fault?
+	Fault *bool `form:"fault,omitempty" json:"fault,omitempty" xml:"fault,omitempty"`
+}
+
+// NewLivenessHealthStatusOK builds a "health" service "liveness" endpoint
+// result from a HTTP "OK" response.
+func NewLivenessHealthStatusOK(body *LivenessResponseBody) *health.HealthStatus {
+	v := &health.HealthStatus{
+		Status: *body.Status,
+	}
+	if body.Checks != nil {
+		v.Checks = make(map[string]string, len(body.Checks))
+		for key, val := range body.Checks {
+			tk := key
+			tv := val
+			v.Checks[tk] = tv
+		}
+	}
+	return v
+}
+
+// NewReadinessHealthStatusOK builds a "health" service "readiness" endpoint
+// result from a HTTP "OK" response.
+func NewReadinessHealthStatusOK(body *ReadinessResponseBody) *health.HealthStatus {
+	v := &health.HealthStatus{
+		Status: *body.Status,
+	}
+	if body.Checks != nil {
+		v.Checks = make(map[string]string, len(body.Checks))
+		for key, val := range body.Checks {
+			tk := key
+			tv := val
+			v.Checks[tk] = tv
+		}
+	}
+	return v
+}
+
+// NewReadinessNotReady builds a health service readiness endpoint not_ready
+// error.
+func NewReadinessNotReady(body *ReadinessNotReadyResponseBody) *goa.ServiceError {
+	v := &goa.ServiceError{
+		Name:      *body.Name,
+		ID:        *body.ID,
+		Message:   *body.Message,
+		Temporary: *body.Temporary,
+		Timeout:   *body.Timeout,
+		Fault:     *body.Fault,
+	}
+	return v
+}
+
+// ValidateLivenessResponseBody runs the validations defined on
+// LivenessResponseBody
+func ValidateLivenessResponseBody(body *LivenessResponseBody) (err error) {
+	if body.Status == nil {
+		err = goa.MergeErrors(err, goa.MissingFieldError("status", "body"))
+	}
+	return
+}
+
+// ValidateReadinessResponseBody runs the validations defined on
+// ReadinessResponseBody
+func ValidateReadinessResponseBody(body *ReadinessResponseBody) (err error) {
+	if body.Status == nil {
+		err = goa.MergeErrors(err, goa.MissingFieldError("status", "body"))
+	}
+	return
+}
+
+// ValidateReadinessNotReadyResponseBody runs the validations defined on
+// readiness_not_ready_response_body
+func ValidateReadinessNotReadyResponseBody(body *ReadinessNotReadyResponseBody) (err error) {
+	if body.Name == nil {
+		err = goa.MergeErrors(err, goa.MissingFieldError("name", "body"))
+	}
+	if body.ID == nil {
+		err = goa.MergeErrors(err, goa.MissingFieldError("id", "body"))
+	}
+	if body.Message == nil {
+		err = goa.MergeErrors(err, goa.MissingFieldError("message", "body"))
+	}
+	if body.Temporary == nil {
+		err = goa.MergeErrors(err, goa.MissingFieldError("temporary", "body"))
+	}
+	if body.Timeout == nil {
+		err = goa.MergeErrors(err, goa.MissingFieldError("timeout", "body"))
+	}
+	if body.Fault == nil {
+		err = goa.MergeErrors(err, goa.MissingFieldError("fault", "body"))
+	}
+	return
+}
@@ -17,10 +17,19 @@ import (
 	"goa.design/plugins/goakit/examples/fetcher/archiver/gen/http/health/client"
 )
 
-// DecodeShowResponse returns a go-kit DecodeResponseFunc suitable for decoding
-// health show responses.
-func DecodeShowResponse(decoder func(*http.Response) goahttp.Decoder) kithttp.DecodeResponseFunc {
-	dec := client.DecodeShowResponse(decoder, false)
+// DecodeLivenessResponse returns a go-kit DecodeResponseFunc suitable for
+// decoding health liveness responses.
+func DecodeLivenessResponse(decoder func(*http.Response) goahttp.Decoder) kithttp.DecodeResponseFunc {
+	dec := client.DecodeLivenessResponse(decoder, false)
+	return func(ctx context.Context, resp *http.Response) (interface{}, error) {
+		return dec(resp)
+	}
+}
+
+// DecodeReadinessResponse returns a go-kit DecodeResponseFunc suitable for
+// decoding health readiness responses.
+func DecodeReadinessResponse(decoder func(*http.Response) goahttp.Decoder) kithttp.DecodeResponseFunc {
+	dec := client.DecodeReadinessResponse(decoder, false)
 	return func(ctx context.Context, resp *http.Response) (interface{}, error) {
 		return dec(resp)
 	}
@@ -17,8 +17,23 @@ import (
 	"goa.design/plugins/goakit/examples/fetcher/archiver/gen/http/health/server"
 )
 
-// EncodeShowResponse returns a go-kit EncodeResponseFunc suitable for encoding
-// health show responses.
-func EncodeShowResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) kithttp.EncodeResponseFunc {
-	return server.EncodeShowResponse(encoder)
+// EncodeLivenessResponse returns a go-kit EncodeResponseFunc suitable for
+// encoding health liveness responses.
+func EncodeLivenessResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) kithttp.EncodeResponseFunc {
+	return server.EncodeLivenessResponse(encoder)
+}
+
+// EncodeReadinessResponse returns a go-kit EncodeResponseFunc suitable for
+// encoding health readiness responses.
+func EncodeReadinessResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) kithttp.EncodeResponseFunc {
+	return server.EncodeReadinessResponse(encoder)
+}
+
+// EncodeReadinessError returns a go-kit EncodeResponseFunc suitable for
+// encoding errors returned by the health readiness endpoint.
+func EncodeReadinessError(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) kithttp.ErrorEncoder {
+	enc := server.EncodeReadinessError(encoder)
+	return func(ctx context.Context, err error, w http.ResponseWriter) {
+		enc(ctx, w, err)
+	}
 }
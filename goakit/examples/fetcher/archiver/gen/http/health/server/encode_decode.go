@@ -12,18 +12,54 @@ import (
 	"context"
 	"net/http"
 
+	goa "goa.design/goa"
 	goahttp "goa.design/goa/http"
+	health "goa.design/plugins/goakit/examples/fetcher/archiver/gen/health"
 )
 
-// EncodeShowResponse returns an encoder for responses returned by the health
-// show endpoint.
-func EncodeShowResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, interface{}) error {
+// EncodeLivenessResponse returns an encoder for responses returned by the
+// health liveness endpoint.
+func EncodeLivenessResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, interface{}) error {
 	return func(ctx context.Context, w http.ResponseWriter, v interface{}) error {
-		res := v.(string)
-		ctx = context.WithValue(ctx, goahttp.ContentTypeKey, "text/plain")
+		res := v.(*health.HealthStatus)
 		enc := encoder(ctx, w)
-		body := res
+		body := NewLivenessResponseBody(res)
 		w.WriteHeader(http.StatusOK)
 		return enc.Encode(body)
 	}
 }
+
+// EncodeReadinessResponse returns an encoder for responses returned by the
+// health readiness endpoint.
+func EncodeReadinessResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, interface{}) error {
+	return func(ctx context.Context, w http.ResponseWriter, v interface{}) error {
+		res := v.(*health.HealthStatus)
+		enc := encoder(ctx, w)
+		body := NewReadinessResponseBody(res)
+		w.WriteHeader(http.StatusOK)
+		return enc.Encode(body)
+	}
+}
+
+// EncodeReadinessError returns an encoder for errors returned by the readiness
+// health endpoint.
+func EncodeReadinessError(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, error) error {
+	encodeError := goahttp.ErrorEncoder(encoder)
+	return func(ctx context.Context, w http.ResponseWriter, v error) error {
+		en, ok := v.(ErrorNamer)
+		if !ok {
+			return encodeError(ctx, w, v)
+		}
+		switch en.ErrorName() {
+		case "not_ready":
+			res := v.(*goa.ServiceError)
+			enc := encoder(ctx, w)
+			body := NewReadinessNotReadyResponseBody(res)
+			w.Header().Set("goa-error", "not_ready")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return enc.Encode(body)
+		default:
+			return encodeError(ctx, w, v)
+		}
+	}
+}
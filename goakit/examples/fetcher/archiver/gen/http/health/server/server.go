@@ -20,8 +20,9 @@ import (
 
 // Server lists the health service endpoint HTTP handlers.
 type Server struct {
-	Mounts []*MountPoint
-	Show   http.Handler
+	Mounts    []*MountPoint
+	Liveness  http.Handler
+	Readiness http.Handler
 }
 
 // ErrorNamer is an interface implemented by generated error structs that
@@ -51,9 +52,11 @@ func New(
 ) *Server {
 	return &Server{
 		Mounts: []*MountPoint{
-			{"Show", "GET", "/health"},
+			{"Liveness", "GET", "/livez"},
+			{"Readiness", "GET", "/readyz"},
 		},
-		Show: NewShowHandler(e.Show, mux, dec, enc, eh),
+		Liveness:  NewLivenessHandler(e.Liveness, mux, dec, enc, eh),
+		Readiness: NewReadinessHandler(e.Readiness, mux, dec, enc, eh),
 	}
 }
 
@@ -62,29 +65,31 @@ func (s *Server) Service() string { return "health" }
 
 // Use wraps the server handlers with the given middleware.
 func (s *Server) Use(m func(http.Handler) http.Handler) {
-	s.Show = m(s.Show)
+	s.Liveness = m(s.Liveness)
+	s.Readiness = m(s.Readiness)
 }
 
 // Mount configures the mux to serve the health endpoints.
 func Mount(mux goahttp.Muxer, h *Server) {
-	MountShowHandler(mux, h.Show)
+	MountLivenessHandler(mux, h.Liveness)
+	MountReadinessHandler(mux, h.Readiness)
 }
 
-// MountShowHandler configures the mux to serve the "health" service "show"
-// endpoint.
-func MountShowHandler(mux goahttp.Muxer, h http.Handler) {
+// MountLivenessHandler configures the mux to serve the "health" service
+// "liveness" endpoint.
+func MountLivenessHandler(mux goahttp.Muxer, h http.Handler) {
 	f, ok := h.(http.HandlerFunc)
 	if !ok {
 		f = func(w http.ResponseWriter, r *http.Request) {
 			h.ServeHTTP(w, r)
 		}
 	}
-	mux.Handle("GET", "/health", f)
+	mux.Handle("GET", "/livez", f)
 }
 
-// NewShowHandler creates a HTTP handler which loads the HTTP request and calls
-// the "health" service "show" endpoint.
-func NewShowHandler(
+// NewLivenessHandler creates a HTTP handler which loads the HTTP request and
+// calls the "health" service "liveness" endpoint.
+func NewLivenessHandler(
 	endpoint endpoint.Endpoint,
 	mux goahttp.Muxer,
 	dec func(*http.Request) goahttp.Decoder,
@@ -92,12 +97,56 @@ func NewShowHandler(
 	eh func(context.Context, http.ResponseWriter, error),
 ) http.Handler {
 	var (
-		encodeResponse = EncodeShowResponse(enc)
+		encodeResponse = EncodeLivenessResponse(enc)
 		encodeError    = goahttp.ErrorEncoder(enc)
 	)
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := context.WithValue(r.Context(), goahttp.AcceptTypeKey, r.Header.Get("Accept"))
-		ctx = context.WithValue(ctx, goa.MethodKey, "show")
+		ctx = context.WithValue(ctx, goa.MethodKey, "liveness")
+		ctx = context.WithValue(ctx, goa.ServiceKey, "health")
+
+		res, err := endpoint(ctx, nil)
+
+		if err != nil {
+			if err := encodeError(ctx, w, err); err != nil {
+				eh(ctx, w, err)
+			}
+			return
+		}
+		if err := encodeResponse(ctx, w, res); err != nil {
+			eh(ctx, w, err)
+		}
+	})
+}
+
+// MountReadinessHandler configures the mux to serve the "health" service
+// "readiness" endpoint.
+func MountReadinessHandler(mux goahttp.Muxer, h http.Handler) {
+	f, ok := h.(http.HandlerFunc)
+	if !ok {
+		f = func(w http.ResponseWriter, r *http.Request) {
+			h.ServeHTTP(w, r)
+		}
+	}
+	mux.Handle("GET", "/readyz", f)
+}
+
+// NewReadinessHandler creates a HTTP handler which loads the HTTP request and
+// calls the "health" service "readiness" endpoint.
+func NewReadinessHandler(
+	endpoint endpoint.Endpoint,
+	mux goahttp.Muxer,
+	dec func(*http.Request) goahttp.Decoder,
+	enc func(context.Context, http.ResponseWriter) goahttp.Encoder,
+	eh func(context.Context, http.ResponseWriter, error),
+) http.Handler {
+	var (
+		encodeResponse = EncodeReadinessResponse(enc)
+		encodeError    = EncodeReadinessError(enc)
+	)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), goahttp.AcceptTypeKey, r.Header.Get("Accept"))
+		ctx = context.WithValue(ctx, goa.MethodKey, "readiness")
 		ctx = context.WithValue(ctx, goa.ServiceKey, "health")
 
 		res, err := endpoint(ctx, nil)
@@ -1,26 +1,13 @@
 package archiver
 
 import (
-	"context"
-
 	"github.com/go-kit/kit/log"
 	health "goa.design/plugins/goakit/examples/fetcher/archiver/gen/health"
+	"goa.design/plugins/goakit/examples/fetcher/archiver/gen/health/kithealth"
 )
 
-// health service example implementation.
-// The example methods log the requests and return zero values.
-type healthsvc struct {
-	logger log.Logger
-}
-
-// NewHealth returns the health service implementation.
-func NewHealth(logger log.Logger) health.Service {
-	return &healthsvc{logger}
-}
-
-// Health check endpoint
-func (s *healthsvc) Show(ctx context.Context) (string, error) {
-	var res string
-	s.logger.Log("msg", "health.show")
-	return res, nil
+// NewHealth returns the health service implementation. The archiver has no
+// dependency so that its readiness checks always succeed.
+func NewHealth(log.Logger) health.Service {
+	return kithealth.New()
 }
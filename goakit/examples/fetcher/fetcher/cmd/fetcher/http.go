@@ -2,7 +2,6 @@ package main
 
 import (
 	"context"
-	"fmt"
 	"net/http"
 	"net/url"
 	"os"
@@ -11,7 +10,9 @@ import (
 
 	"github.com/go-kit/kit/endpoint"
 	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
 	kithttp "github.com/go-kit/kit/transport/http"
+	opentracing "github.com/opentracing/opentracing-go"
 	goahttp "goa.design/goa/http"
 	httpmdlwr "goa.design/goa/http/middleware"
 	"goa.design/goa/middleware"
@@ -48,30 +49,41 @@ func handleHTTPServer(ctx context.Context, u *url.URL, fetcherEndpoints *fetcher
 	// the service input and output data structures to HTTP requests and
 	// responses.
 	var (
-		fetcherFetchHandler *kithttp.Server
-		fetcherServer       *fetchersvcsvr.Server
-		healthShowHandler   *kithttp.Server
-		healthServer        *healthsvr.Server
+		fetcherFetchHandler    *kithttp.Server
+		fetcherServer          *fetchersvcsvr.Server
+		healthLivenessHandler  *kithttp.Server
+		healthReadinessHandler *kithttp.Server
+		healthServer           *healthsvr.Server
 	)
 	{
 		eh := errorHandler(logger)
+		tracer := opentracing.GlobalTracer()
 		fetcherFetchHandler = kithttp.NewServer(
-			endpoint.Endpoint(fetcherEndpoints.Fetch),
+			fetchersvckitsvr.TraceFetchEndpoint(tracer)(fetchersvckitsvr.LogFetchEndpoint(logger)(endpoint.Endpoint(fetcherEndpoints.Fetch))),
 			fetchersvckitsvr.DecodeFetchRequest(mux, dec),
 			fetchersvckitsvr.EncodeFetchResponse(enc),
+			fetchersvckitsvr.ServerOptions(fetchersvckitsvr.FetchTraceServerOptions(tracer, logger)...)...,
 		)
 		fetcherServer = fetchersvcsvr.New(fetcherEndpoints, mux, dec, enc, eh)
-		healthShowHandler = kithttp.NewServer(
-			endpoint.Endpoint(healthEndpoints.Show),
+		healthLivenessHandler = kithttp.NewServer(
+			healthkitsvr.LogLivenessEndpoint(logger)(endpoint.Endpoint(healthEndpoints.Liveness)),
 			func(context.Context, *http.Request) (request interface{}, err error) { return nil, nil },
-			healthkitsvr.EncodeShowResponse(enc),
+			healthkitsvr.EncodeLivenessResponse(enc),
+			healthkitsvr.ServerOptions()...,
+		)
+		healthReadinessHandler = kithttp.NewServer(
+			healthkitsvr.LogReadinessEndpoint(logger)(endpoint.Endpoint(healthEndpoints.Readiness)),
+			func(context.Context, *http.Request) (request interface{}, err error) { return nil, nil },
+			healthkitsvr.EncodeReadinessResponse(enc),
+			healthkitsvr.ServerOptions()...,
 		)
 		healthServer = healthsvr.New(healthEndpoints, mux, dec, enc, eh)
 	}
 
 	// Configure the mux.
 	fetchersvckitsvr.MountFetchHandler(mux, fetcherFetchHandler)
-	healthkitsvr.MountShowHandler(mux, healthShowHandler)
+	healthkitsvr.MountLivenessHandler(mux, healthLivenessHandler)
+	healthkitsvr.MountReadinessHandler(mux, healthReadinessHandler)
 
 	// Wrap the multiplexer with additional middlewares. Middlewares mounted
 	// here apply to all the service endpoints.
@@ -88,10 +100,10 @@ func handleHTTPServer(ctx context.Context, u *url.URL, fetcherEndpoints *fetcher
 	// configure the server as required by your service.
 	srv := &http.Server{Addr: u.Host, Handler: handler}
 	for _, m := range fetcherServer.Mounts {
-		logger.Log("info", fmt.Sprintf("HTTP %q mounted on %s %s", m.Method, m.Verb, m.Pattern))
+		level.Info(logger).Log("msg", "HTTP endpoint mounted", "method", m.Method, "verb", m.Verb, "pattern", m.Pattern)
 	}
 	for _, m := range healthServer.Mounts {
-		logger.Log("info", fmt.Sprintf("HTTP %q mounted on %s %s", m.Method, m.Verb, m.Pattern))
+		level.Info(logger).Log("msg", "HTTP endpoint mounted", "method", m.Method, "verb", m.Verb, "pattern", m.Pattern)
 	}
 
 	(*wg).Add(1)
@@ -100,13 +112,13 @@ func handleHTTPServer(ctx context.Context, u *url.URL, fetcherEndpoints *fetcher
 
 		// Start HTTP server in a separate goroutine.
 		go func() {
-			logger.Log("info", fmt.Sprintf("HTTP server listening on %q", u.Host))
+			level.Info(logger).Log("msg", "HTTP server listening", "addr", u.Host)
 			errc <- srv.ListenAndServe()
 		}()
 
 		select {
 		case <-ctx.Done():
-			logger.Log("info", fmt.Sprintf("shutting down HTTP server at %q", u.Host))
+			level.Info(logger).Log("msg", "shutting down HTTP server", "addr", u.Host)
 
 			// Shutdown gracefully with a 30s timeout.
 			ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
@@ -125,6 +137,6 @@ func errorHandler(logger log.Logger) func(context.Context, http.ResponseWriter,
 	return func(ctx context.Context, w http.ResponseWriter, err error) {
 		id := ctx.Value(middleware.RequestIDKey).(string)
 		w.Write([]byte("[" + id + "] encoding: " + err.Error()))
-		logger.Log("info", fmt.Sprintf("[%s] ERROR: %s", id, err.Error()))
+		level.Error(logger).Log("msg", "request failed", "request_id", id, "err", err.Error())
 	}
 }
@@ -11,6 +11,7 @@ import (
 	"sync"
 
 	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
 	fetcher "goa.design/plugins/goakit/examples/fetcher/fetcher"
 	fetchersvc "goa.design/plugins/goakit/examples/fetcher/fetcher/gen/fetcher"
 	health "goa.design/plugins/goakit/examples/fetcher/fetcher/gen/health"
@@ -27,6 +28,7 @@ func main() {
 		dbgF         = flag.Bool("debug", false, "Log request and response bodies")
 		archiverHost = flag.String("archiver", "localhost:8081", "archiver service `host:port`")
 	)
+	logFormatF := flag.String("log-format", "logfmt", "Log format (valid values: logfmt, json)")
 	flag.Parse()
 
 	// Setup gokit logger.
@@ -34,7 +36,13 @@ func main() {
 		logger log.Logger
 	)
 	{
-		logger = log.NewLogfmtLogger(os.Stderr)
+		w := log.NewSyncWriter(os.Stderr)
+		switch *logFormatF {
+		case "json":
+			logger = log.NewJSONLogger(w)
+		default:
+			logger = log.NewLogfmtLogger(w)
+		}
 		logger = log.With(logger, "ts", log.DefaultTimestampUTC)
 		logger = log.With(logger, "caller", log.DefaultCaller)
 	}
@@ -46,7 +54,7 @@ func main() {
 	)
 	{
 		fetcherSvc = fetcher.NewFetcher(logger, *archiverHost)
-		healthSvc = fetcher.NewHealth(logger)
+		healthSvc = fetcher.NewHealth(*archiverHost)
 	}
 
 	// Wrap the services in endpoints that can be invoked from other services
@@ -105,11 +113,11 @@ func main() {
 	}
 
 	// Wait for signal.
-	logger.Log("info", fmt.Sprintf("exiting (%v)", <-errc))
+	level.Info(logger).Log("msg", "exiting", "reason", <-errc)
 
 	// Send cancellation signal to the goroutines.
 	cancel()
 
 	wg.Wait()
-	logger.Log("info", fmt.Sprintf("exited"))
+	level.Info(logger).Log("msg", "exited")
 }
@@ -2,15 +2,17 @@ package design
 
 import (
 	. "goa.design/goa/dsl"
-	_ "goa.design/plugins/goakit"
+	goakit "goa.design/plugins/goakit/dsl"
 )
 
 var _ = API("fetcher", func() {
 	Title("The goakit example upstream service")
 	Description("Fetcher is a service that makes GET requests to arbitrary URLs and stores the results in the downstream 'archiver' service.")
+	goakit.Health()
 })
 
 var _ = Service("fetcher", func() {
+	goakit.Tracing()
 	Method("fetch", func() {
 		Description("Fetch makes a GET request to the given URL and stores the results in the archiver service which must be running or the request fails")
 		Payload(func() {
@@ -50,20 +52,3 @@ var FetchMedia = ResultType("application/vnd.goa.fetch", func() {
 		Attribute("archive_href")
 	})
 })
-
-var _ = Service("health", func() {
-	HTTP(func() {
-		Path("/health")
-	})
-	Method("show", func() {
-		Description("Health check endpoint")
-		Result(String)
-		HTTP(func() {
-			GET("/")
-			Response(func() {
-				Code(StatusOK)
-				ContentType("text/plain")
-			})
-		})
-	})
-})
@@ -0,0 +1,17 @@
+package design
+
+import (
+	"testing"
+
+	"goa.design/plugins/goakit/examples/internal/exampletest"
+)
+
+func TestExample(t *testing.T) {
+	// The example server is edited to make requests to the archiver service,
+	// the output of the example command is not verified.
+	exampletest.Verify(t, exampletest.Example{
+		DesignPkg: "goa.design/plugins/goakit/examples/fetcher/fetcher/design",
+		Dir:       "..",
+		Commands:  []string{"gen"},
+	})
+}
@@ -5,15 +5,15 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
-	"net/url"
+	"time"
 
 	"github.com/go-kit/kit/endpoint"
 	"github.com/go-kit/kit/log"
-	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/go-kit/kit/sd"
+	opentracing "github.com/opentracing/opentracing-go"
 	goahttp "goa.design/goa/http"
 	archiversvc "goa.design/plugins/goakit/examples/fetcher/archiver/gen/archiver"
 	archiverkc "goa.design/plugins/goakit/examples/fetcher/archiver/gen/http/archiver/kitclient"
-	archiverks "goa.design/plugins/goakit/examples/fetcher/archiver/gen/http/archiver/server"
 	fetchersvc "goa.design/plugins/goakit/examples/fetcher/fetcher/gen/fetcher"
 )
 
@@ -26,22 +26,25 @@ type fetchersvcsvc struct {
 
 // NewFetcher returns the fetcher service implementation.
 func NewFetcher(logger log.Logger, archiverHost string) fetchersvc.Service {
-	u := url.URL{
-		Scheme: "http",
-		Host:   archiverHost,
-		Path:   archiverks.ArchiveArchiverPath(),
-	}
 	var (
 		dec = goahttp.ResponseDecoder
 		enc = goahttp.RequestEncoder
 	)
-	arc := kithttp.NewClient(
-		"POST",
-		&u,
-		archiverkc.EncodeArchiveRequest(enc),
-		archiverkc.DecodeArchiveResponse(dec),
+	// The archiver instances are static, use a sd.Instancer backed by a
+	// service registry (e.g. Consul or etcd) to discover them dynamically.
+	// The archiver design defines no retry policy for the archive requests,
+	// the failed requests are retried once on the next instance whatever the
+	// error. The archive requests spans are children of the fetch request
+	// spans so that the traces cover both services.
+	instancer := sd.FixedInstancer{archiverHost}
+	arc := archiverkc.BalancedArchiveEndpoint(
+		instancer,
+		archiverkc.ArchiveFactory("http", enc, dec, opentracing.GlobalTracer(), logger),
+		logger,
+		1,
+		5*time.Second,
 	)
-	return &fetchersvcsvc{logger: logger, archive: arc.Endpoint()}
+	return &fetchersvcsvc{logger: logger, archive: arc}
 }
 
 // Fetch makes a GET request to the given URL and stores the results in the
@@ -0,0 +1,121 @@
+package fetcher_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/sd"
+	kithttp "github.com/go-kit/kit/transport/http"
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	goahttp "goa.design/goa/http"
+	"goa.design/plugins/goakit/examples/fetcher/archiver"
+	archiversvc "goa.design/plugins/goakit/examples/fetcher/archiver/gen/archiver"
+	archiverkc "goa.design/plugins/goakit/examples/fetcher/archiver/gen/http/archiver/kitclient"
+	archiverkitsvr "goa.design/plugins/goakit/examples/fetcher/archiver/gen/http/archiver/kitserver"
+	"goa.design/plugins/goakit/examples/fetcher/fetcher"
+	fetchersvc "goa.design/plugins/goakit/examples/fetcher/fetcher/gen/fetcher"
+)
+
+// TestFetchTracing makes sure that the spans recorded for the archive requests
+// made by the fetcher service belong to the trace of the fetch request, on
+// both the client and the archiver server sides.
+func TestFetchTracing(t *testing.T) {
+	tracer := mocktracer.New()
+	prev := opentracing.GlobalTracer()
+	opentracing.SetGlobalTracer(tracer)
+	defer opentracing.SetGlobalTracer(prev)
+
+	// Serve the archiver archive endpoint with the generated tracing options
+	// and middleware.
+	logger := log.NewNopLogger()
+	mux := goahttp.NewMuxer()
+	endpoints := archiversvc.NewEndpoints(archiver.NewArchiver(logger))
+	archiverkitsvr.MountArchiveHandler(mux, kithttp.NewServer(
+		archiverkitsvr.TraceArchiveEndpoint(tracer)(endpoint.Endpoint(endpoints.Archive)),
+		archiverkitsvr.DecodeArchiveRequest(mux, goahttp.RequestDecoder),
+		archiverkitsvr.EncodeArchiveResponse(goahttp.ResponseEncoder),
+		archiverkitsvr.ArchiveTraceServerOptions(tracer, logger)...,
+	))
+	arc := httptest.NewServer(mux)
+	defer arc.Close()
+
+	// Serve the content fetched by the fetcher service.
+	src := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte("content"))
+	}))
+	defer src.Close()
+
+	svc := fetcher.NewFetcher(logger, strings.TrimPrefix(arc.URL, "http://"))
+	parent := tracer.StartSpan("fetcher.fetch").(*mocktracer.MockSpan)
+	ctx := opentracing.ContextWithSpan(context.Background(), parent)
+	if _, err := svc.Fetch(ctx, &fetchersvc.FetchPayload{URL: src.URL}); err != nil {
+		t.Fatalf("fetch failed: %s", err)
+	}
+	parent.Finish()
+
+	var archives []*mocktracer.MockSpan
+	for _, span := range tracer.FinishedSpans() {
+		if span.OperationName == "archiver.archive" {
+			archives = append(archives, span)
+		}
+	}
+	if len(archives) != 2 {
+		t.Fatalf("got %d archiver.archive spans, expected 2 (client and server)", len(archives))
+	}
+	byParent := make(map[int]*mocktracer.MockSpan)
+	for _, span := range archives {
+		if span.SpanContext.TraceID != parent.SpanContext.TraceID {
+			t.Errorf("invalid trace ID, got %d, expected %d", span.SpanContext.TraceID, parent.SpanContext.TraceID)
+		}
+		byParent[span.ParentID] = span
+	}
+	client, ok := byParent[parent.SpanContext.SpanID]
+	if !ok {
+		t.Fatal("no archiver.archive client span is a child of the fetch span")
+	}
+	if _, ok := byParent[client.SpanContext.SpanID]; !ok {
+		t.Error("the archiver.archive server span is not a child of the client span")
+	}
+}
+
+// TestArchiveRetries makes sure that the balanced archive endpoint makes the
+// first request and retries it retryMax times when all the instances fail.
+func TestArchiveRetries(t *testing.T) {
+	cases := map[string]struct {
+		RetryMax int
+		Requests int
+	}{
+		"no retry":  {0, 1},
+		"one retry": {1, 2},
+		"retries":   {3, 4},
+	}
+	for k, tc := range cases {
+		t.Run(k, func(t *testing.T) {
+			var requests int
+			failed := errors.New("failed")
+			factory := func(string) (endpoint.Endpoint, io.Closer, error) {
+				return func(context.Context, interface{}) (interface{}, error) {
+					requests++
+					return nil, failed
+				}, nil, nil
+			}
+			instancer := sd.FixedInstancer{"archiver1:8080", "archiver2:8080"}
+			arc := archiverkc.BalancedArchiveEndpoint(instancer, factory, log.NewNopLogger(), tc.RetryMax, time.Second)
+			if _, err := arc(context.Background(), &archiversvc.ArchivePayload{Status: 200, Body: "content"}); err != failed {
+				t.Errorf("invalid error, got %v, expected %v", err, failed)
+			}
+			if requests != tc.Requests {
+				t.Errorf("got %d requests, expected %d", requests, tc.Requests)
+			}
+		})
+	}
+}
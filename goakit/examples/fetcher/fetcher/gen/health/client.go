@@ -16,22 +16,37 @@ import (
 
 // Client is the "health" service client.
 type Client struct {
-	ShowEndpoint endpoint.Endpoint
+	LivenessEndpoint  endpoint.Endpoint
+	ReadinessEndpoint endpoint.Endpoint
 }
 
 // NewClient initializes a "health" service client given the endpoints.
-func NewClient(show endpoint.Endpoint) *Client {
+func NewClient(liveness, readiness endpoint.Endpoint) *Client {
 	return &Client{
-		ShowEndpoint: show,
+		LivenessEndpoint:  liveness,
+		ReadinessEndpoint: readiness,
 	}
 }
 
-// Show calls the "show" endpoint of the "health" service.
-func (c *Client) Show(ctx context.Context) (res string, err error) {
+// Liveness calls the "liveness" endpoint of the "health" service.
+func (c *Client) Liveness(ctx context.Context) (res *HealthStatus, err error) {
 	var ires interface{}
-	ires, err = c.ShowEndpoint(ctx, nil)
+	ires, err = c.LivenessEndpoint(ctx, nil)
 	if err != nil {
 		return
 	}
-	return ires.(string), nil
+	return ires.(*HealthStatus), nil
+}
+
+// Readiness calls the "readiness" endpoint of the "health" service.
+// Readiness may return the following errors:
+//	- "not_ready" (type *goa.ServiceError): One or more dependency checks failed
+//	- error: internal error
+func (c *Client) Readiness(ctx context.Context) (res *HealthStatus, err error) {
+	var ires interface{}
+	ires, err = c.ReadinessEndpoint(ctx, nil)
+	if err != nil {
+		return
+	}
+	return ires.(*HealthStatus), nil
 }
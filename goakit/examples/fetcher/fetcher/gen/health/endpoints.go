@@ -16,25 +16,36 @@ import (
 
 // Endpoints wraps the "health" service endpoints.
 type Endpoints struct {
-	Show endpoint.Endpoint
+	Liveness  endpoint.Endpoint
+	Readiness endpoint.Endpoint
 }
 
 // NewEndpoints wraps the methods of the "health" service with endpoints.
 func NewEndpoints(s Service) *Endpoints {
 	return &Endpoints{
-		Show: NewShowEndpoint(s),
+		Liveness:  NewLivenessEndpoint(s),
+		Readiness: NewReadinessEndpoint(s),
 	}
 }
 
 // Use applies the given middleware to all the "health" service endpoints.
 func (e *Endpoints) Use(m func(endpoint.Endpoint) endpoint.Endpoint) {
-	e.Show = m(e.Show)
+	e.Liveness = m(e.Liveness)
+	e.Readiness = m(e.Readiness)
 }
 
-// NewShowEndpoint returns an endpoint function that calls the method "show" of
-// service "health".
-func NewShowEndpoint(s Service) endpoint.Endpoint {
+// NewLivenessEndpoint returns an endpoint function that calls the method
+// "liveness" of service "health".
+func NewLivenessEndpoint(s Service) endpoint.Endpoint {
 	return func(ctx context.Context, req interface{}) (interface{}, error) {
-		return s.Show(ctx)
+		return s.Liveness(ctx)
+	}
+}
+
+// NewReadinessEndpoint returns an endpoint function that calls the method
+// "readiness" of service "health".
+func NewReadinessEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.Readiness(ctx)
 	}
 }
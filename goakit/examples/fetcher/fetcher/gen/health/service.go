@@ -10,12 +10,17 @@ package health
 
 import (
 	"context"
+
+	"goa.design/goa"
 )
 
-// Service is the health service interface.
+// The health service reports the liveness and readiness of the server.
 type Service interface {
-	// Health check endpoint
-	Show(context.Context) (res string, err error)
+	// Liveness reports whether the server is running.
+	Liveness(context.Context) (res *HealthStatus, err error)
+	// Readiness reports whether the server dependencies are ready to serve
+	// requests.
+	Readiness(context.Context) (res *HealthStatus, err error)
 }
 
 // ServiceName is the name of the service as defined in the design. This is the
@@ -26,4 +31,23 @@ const ServiceName = "health"
 // MethodNames lists the service method names as defined in the design. These
 // are the same values that are set in the endpoint request contexts under the
 // MethodKey key.
-var MethodNames = [1]string{"show"}
+var MethodNames = [2]string{"liveness", "readiness"}
+
+// HealthStatus describes the health of the server.
+type HealthStatus struct {
+	// Status of the server
+	Status string
+	// Status of the dependencies indexed by checker name, either "ok" or the
+	// error returned by the checker
+	Checks map[string]string
+}
+
+// MakeNotReady builds a goa.ServiceError from an error.
+func MakeNotReady(err error) *goa.ServiceError {
+	return &goa.ServiceError{
+		Name:      "not_ready",
+		ID:        goa.NewErrorID(),
+		Message:   err.Error(),
+		Temporary: true,
+	}
+}
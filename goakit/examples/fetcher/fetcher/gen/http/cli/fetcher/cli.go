@@ -17,7 +17,8 @@ import (
 	"github.com/go-kit/kit/endpoint"
 	goahttp "goa.design/goa/http"
 	fetchersvcc "goa.design/plugins/goakit/examples/fetcher/fetcher/gen/http/fetcher/client"
-	healthc "goa.design/plugins/goakit/examples/fetcher/fetcher/gen/http/health/client"
+	fetchersvckc "goa.design/plugins/goakit/examples/fetcher/fetcher/gen/http/fetcher/kitclient"
+	healthkc "goa.design/plugins/goakit/examples/fetcher/fetcher/gen/http/health/kitclient"
 )
 
 // UsageCommands returns the set of commands and sub-commands using the format
@@ -32,7 +33,7 @@ fetcher fetch
 
 // UsageExamples produces an example of a valid invocation of the CLI tool.
 func UsageExamples() string {
-	return os.Args[0] + ` health show` + "\n" +
+	return os.Args[0] + ` health liveness` + "\n" +
 		os.Args[0] + ` fetcher fetch --url "http://keeblerherman.name/jazlyn.ruecker"` + "\n" +
 		""
 }
@@ -49,7 +50,9 @@ func ParseEndpoint(
 	var (
 		healthFlags = flag.NewFlagSet("health", flag.ContinueOnError)
 
-		healthShowFlags = flag.NewFlagSet("show", flag.ExitOnError)
+		healthLivenessFlags = flag.NewFlagSet("liveness", flag.ExitOnError)
+
+		healthReadinessFlags = flag.NewFlagSet("readiness", flag.ExitOnError)
 
 		fetcherFlags = flag.NewFlagSet("fetcher", flag.ContinueOnError)
 
@@ -57,7 +60,8 @@ func ParseEndpoint(
 		fetcherFetchURLFlag = fetcherFetchFlags.String("url", "REQUIRED", "URL to be fetched")
 	)
 	healthFlags.Usage = healthUsage
-	healthShowFlags.Usage = healthShowUsage
+	healthLivenessFlags.Usage = healthLivenessUsage
+	healthReadinessFlags.Usage = healthReadinessUsage
 
 	fetcherFlags.Usage = fetcherUsage
 	fetcherFetchFlags.Usage = fetcherFetchUsage
@@ -98,8 +102,11 @@ func ParseEndpoint(
 		switch svcn {
 		case "health":
 			switch epn {
-			case "show":
-				epf = healthShowFlags
+			case "liveness":
+				epf = healthLivenessFlags
+
+			case "readiness":
+				epf = healthReadinessFlags
 
 			}
 
@@ -131,14 +138,17 @@ func ParseEndpoint(
 	{
 		switch svcn {
 		case "health":
-			c := healthc.NewClient(scheme, host, doer, enc, dec, restore)
+			c := healthkc.NewClient(scheme, host, doer, enc, dec, restore)
 			switch epn {
-			case "show":
-				endpoint = c.Show()
+			case "liveness":
+				endpoint = c.Liveness()
+				data = nil
+			case "readiness":
+				endpoint = c.Readiness()
 				data = nil
 			}
 		case "fetcher":
-			c := fetchersvcc.NewClient(scheme, host, doer, enc, dec, restore)
+			c := fetchersvckc.NewClient(scheme, host, doer, enc, dec, restore)
 			switch epn {
 			case "fetch":
 				endpoint = c.Fetch()
@@ -155,47 +165,34 @@ func ParseEndpoint(
 
 // healthUsage displays the usage of the health command and its subcommands.
 func healthUsage() {
-	fmt.Fprintf(os.Stderr, `Service is the health service interface.
+	fmt.Fprintf(os.Stderr, `The health service reports the liveness and readiness of the server.
 Usage:
     %s [globalflags] health COMMAND [flags]
 
 COMMAND:
-    show: Health check endpoint
+    liveness: Liveness reports whether the server is running.
+    readiness: Readiness reports whether the server dependencies are ready to serve requests.
 
 Additional help:
     %s health COMMAND --help
 `, os.Args[0], os.Args[0])
 }
-func healthShowUsage() {
-	fmt.Fprintf(os.Stderr, `%s [flags] health show
+func healthLivenessUsage() {
+	fmt.Fprintf(os.Stderr, `%s [flags] health liveness
 
-Health check endpoint
+Liveness reports whether the server is running.
 
 Example:
-    `+os.Args[0]+` health show
+    `+os.Args[0]+` health liveness
 `, os.Args[0])
 }
 
-// fetcherUsage displays the usage of the fetcher command and its subcommands.
-func fetcherUsage() {
-	fmt.Fprintf(os.Stderr, `Service is the fetcher service interface.
-Usage:
-    %s [globalflags] fetcher COMMAND [flags]
-
-COMMAND:
-    fetch: Fetch makes a GET request to the given URL and stores the results in the archiver service which must be running or the request fails
-
-Additional help:
-    %s fetcher COMMAND --help
-`, os.Args[0], os.Args[0])
-}
-func fetcherFetchUsage() {
-	fmt.Fprintf(os.Stderr, `%s [flags] fetcher fetch -url STRING
+func healthReadinessUsage() {
+	fmt.Fprintf(os.Stderr, `%s [flags] health readiness
 
-Fetch makes a GET request to the given URL and stores the results in the archiver service which must be running or the request fails
-    -url STRING: URL to be fetched
+Readiness reports whether the server dependencies are ready to serve requests.
 
 Example:
-    `+os.Args[0]+` fetcher fetch --url "http://keeblerherman.name/jazlyn.ruecker"
+    `+os.Args[0]+` health readiness
 `, os.Args[0])
 }
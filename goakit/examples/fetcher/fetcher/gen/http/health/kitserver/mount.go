@@ -14,14 +14,26 @@ import (
 	goahttp "goa.design/goa/http"
 )
 
-// MountShowHandler configures the mux to serve the "health" service "show"
-// endpoint.
-func MountShowHandler(mux goahttp.Muxer, h http.Handler) {
+// MountLivenessHandler configures the mux to serve the "health" service
+// "liveness" endpoint.
+func MountLivenessHandler(mux goahttp.Muxer, h http.Handler) {
 	f, ok := h.(http.HandlerFunc)
 	if !ok {
 		f = func(w http.ResponseWriter, r *http.Request) {
 			h.ServeHTTP(w, r)
 		}
 	}
-	mux.Handle("GET", "/health", f)
+	mux.Handle("GET", "/livez", f)
+}
+
+// MountReadinessHandler configures the mux to serve the "health" service
+// "readiness" endpoint.
+func MountReadinessHandler(mux goahttp.Muxer, h http.Handler) {
+	f, ok := h.(http.HandlerFunc)
+	if !ok {
+		f = func(w http.ResponseWriter, r *http.Request) {
+			h.ServeHTTP(w, r)
+		}
+	}
+	mux.Handle("GET", "/readyz", f)
 }
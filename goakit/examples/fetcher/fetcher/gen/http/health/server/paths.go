@@ -8,7 +8,14 @@
 
 package server
 
-// ShowHealthPath returns the URL path to the health service show HTTP endpoint.
-func ShowHealthPath() string {
-	return "/health"
+// LivenessHealthPath returns the URL path to the health service liveness HTTP
+// endpoint.
+func LivenessHealthPath() string {
+	return "/livez"
+}
+
+// ReadinessHealthPath returns the URL path to the health service readiness
+// HTTP endpoint.
+func ReadinessHealthPath() string {
+	return "/readyz"
 }
@@ -7,3 +7,94 @@
 // $(GOPATH)/src/goa.design/plugins/goakit/examples/fetcher/fetcher
 
 package server
+
+import (
+	goa "goa.design/goa"
+	health "goa.design/plugins/goakit/examples/fetcher/fetcher/gen/health"
+)
+
+// LivenessResponseBody is the type of the "health" service "liveness" endpoint
+// HTTP response body.
+type LivenessResponseBody struct {
+	// Status of the server
+	Status string `form:"status" json:"status" xml:"status"`
+	// Status of the dependencies indexed by checker name, either "ok" or the
+	// error returned by the checker
+	Checks map[string]string `form:"checks,omitempty" json:"checks,omitempty" xml:"checks,omitempty"`
+}
+
+// ReadinessResponseBody is the type of the "health" service "readiness"
+// endpoint HTTP response body.
+type ReadinessResponseBody struct {
+	// Status of the server
+	Status string `form:"status" json:"status" xml:"status"`
+	// Status of the dependencies indexed by checker name, either "ok" or the
+	// error returned by the checker
+	Checks map[string]string `form:"checks,omitempty" json:"checks,omitempty" xml:"checks,omitempty"`
+}
+
+// ReadinessNotReadyResponseBody is the type of the "health" service
+// "readiness" endpoint HTTP response body for the "not_ready" error.
+type ReadinessNotReadyResponseBody struct {
+	// Name is the name of this class of errors.
+	Name string `form:"name" json:"name" xml:"name"`
+	// ID is a unique identifier for this particular occurrence of the problem.
+	ID string `form:"id" json:"id" xml:"id"`
+	// Message is a human-readable explanation specific to this occurrence of the
+	// problem.
+	Message string `form:"message" json:"message" xml:"message"`
+	// Is the error temporary?
+	Temporary bool `form:"temporary" json:"temporary" xml:"temporary"`
+	// Is the error a timeout?
+	Timeout bool `form:"timeout" json:"timeout" xml:"timeout"`
+	// Is the error a server-side fault?
+	Fault bool `form:"fault" json:"fault" xml:"fault"`
+}
+
+// NewLivenessResponseBody builds the HTTP response body from the result of the
+// "liveness" endpoint of the "health" service.
+func NewLivenessResponseBody(res *health.HealthStatus) *LivenessResponseBody {
+	body := &LivenessResponseBody{
+		Status: res.Status,
+	}
+	if res.Checks != nil {
+		body.Checks = make(map[string]string, len(res.Checks))
+		for key, val := range res.Checks {
+			tk := key
+			tv := val
+			body.Checks[tk] = tv
+		}
+	}
+	return body
+}
+
+// NewReadinessResponseBody builds the HTTP response body from the result of
+// the "readiness" endpoint of the "health" service.
+func NewReadinessResponseBody(res *health.HealthStatus) *ReadinessResponseBody {
+	body := &ReadinessResponseBody{
+		Status: res.Status,
+	}
+	if res.Checks != nil {
+		body.Checks = make(map[string]string, len(res.Checks))
+		for key, val := range res.Checks {
+			tk := key
+			tv := val
+			body.Checks[tk] = tv
+		}
+	}
+	return body
+}
+
+// NewReadinessNotReadyResponseBody builds the HTTP response body from the
+// result of the "readiness" endpoint of the "health" service.
+func NewReadinessNotReadyResponseBody(res *goa.ServiceError) *ReadinessNotReadyResponseBody {
+	body := &ReadinessNotReadyResponseBody{
+		Name:      res.Name,
+		ID:        res.ID,
+		Message:   res.Message,
+		Temporary: res.Temporary,
+		Timeout:   res.Timeout,
+		Fault:     res.Fault,
+	}
+	return body
+}
@@ -1,26 +1,17 @@
 package fetcher
 
 import (
-	"context"
+	"net/http"
 
-	"github.com/go-kit/kit/log"
 	health "goa.design/plugins/goakit/examples/fetcher/fetcher/gen/health"
+	"goa.design/plugins/goakit/examples/fetcher/fetcher/gen/health/kithealth"
 )
 
-// health service example implementation.
-// The example methods log the requests and return zero values.
-type healthsvc struct {
-	logger log.Logger
-}
-
-// NewHealth returns the health service implementation.
-func NewHealth(logger log.Logger) health.Service {
-	return &healthsvc{logger}
-}
-
-// Health check endpoint
-func (s *healthsvc) Show(ctx context.Context) (string, error) {
-	var res string
-	s.logger.Log("msg", "health.show")
-	return res, nil
+// NewHealth returns the health service implementation. The fetcher is ready
+// only if the archiver service is, its readiness checks make requests to the
+// archiver readiness endpoint.
+func NewHealth(archiverHost string) health.Service {
+	svc := kithealth.New()
+	svc.Register("archiver", kithealth.HTTPChecker(http.DefaultClient, "http://"+archiverHost+"/readyz"))
+	return svc
 }
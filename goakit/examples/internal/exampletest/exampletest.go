@@ -0,0 +1,225 @@
+/*
+Package exampletest verifies that the committed goakit examples match the code
+generated from their designs.
+
+The example design packages define a test which calls Verify, e.g.:
+
+	func TestExample(t *testing.T) {
+		exampletest.Verify(t, exampletest.Example{
+			DesignPkg: "goa.design/plugins/goakit/examples/calc/design",
+			Dir:       "..",
+			Commands:  []string{"gen", "example"},
+		})
+	}
+
+Running the tests with the -update flag rewrites the committed files instead:
+
+	go test ./examples/... -update
+*/
+package exampletest
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"goa.design/goa/codegen"
+	"goa.design/goa/codegen/generator"
+	"goa.design/goa/eval"
+)
+
+var update = flag.Bool("update", false, "update the committed example files")
+
+// Example describes a goakit example generated from a design package.
+type Example struct {
+	// DesignPkg is the import path of the example design package.
+	DesignPkg string
+	// Dir is the path to the example directory, the directory passed to the
+	// goa tool -o flag.
+	Dir string
+	// Commands lists the goa commands ("gen" or "example") whose output is
+	// committed.
+	Commands []string
+}
+
+// generatedDirs maps the goa commands to the top level directories that hold
+// the files they generate. The example command also generates the service
+// implementations in the example directory, these are not verified as they
+// are edited once generated.
+var generatedDirs = map[string]string{
+	"gen":     codegen.Gendir,
+	"example": "cmd",
+}
+
+// commandRe matches the command line section of the generated file headers
+// which depends on how the generator is invoked.
+var commandRe = regexp.MustCompile(`(?m)^// Command:\n(//.*\n)*`)
+
+// Verify runs the goa generators and plugins of the example commands on the
+// design evaluated by the test package and checks that the generated files
+// match the committed ones. Committed files that are not generated anymore
+// are reported as well. The committed files are updated instead if the test
+// runs with the -update flag.
+func Verify(t *testing.T, ex Example) {
+	t.Helper()
+	if err := eval.RunDSL(); err != nil {
+		t.Fatalf("failed to evaluate design: %s", err)
+	}
+	roots, err := eval.Context.Roots()
+	if err != nil {
+		t.Fatalf("failed to retrieve design roots: %s", err)
+	}
+	genpkg := path.Join(path.Dir(ex.DesignPkg), codegen.Gendir)
+	tmp, err := ioutil.TempDir("", "exampletest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	generated := make(map[string]bool)
+	for _, cmd := range ex.Commands {
+		dir, ok := generatedDirs[cmd]
+		if !ok {
+			t.Fatalf("unknown goa command %q", cmd)
+		}
+		for _, p := range generate(t, cmd, genpkg, roots, tmp) {
+			if strings.HasPrefix(p, dir+"/") {
+				generated[p] = true
+			}
+		}
+		for _, p := range committed(t, ex.Dir, dir) {
+			if generated[p] {
+				continue
+			}
+			if *update {
+				if err := os.Remove(filepath.Join(ex.Dir, filepath.FromSlash(p))); err != nil {
+					t.Fatal(err)
+				}
+				continue
+			}
+			t.Errorf("%s: committed file is not generated anymore", p)
+		}
+	}
+
+	paths := make([]string, 0, len(generated))
+	for p := range generated {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		compare(t, filepath.Join(tmp, filepath.FromSlash(p)), filepath.Join(ex.Dir, filepath.FromSlash(p)), p)
+	}
+}
+
+// generate runs the generators and plugins of the given goa command and
+// renders the files in dir. It returns the slash separated paths of the
+// rendered files relative to dir.
+func generate(t *testing.T, cmd, genpkg string, roots []eval.Root, dir string) []string {
+	t.Helper()
+	gens, err := generator.Generators(cmd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var files []*codegen.File
+	for _, gen := range gens {
+		fs, err := gen(genpkg, roots)
+		if err != nil {
+			t.Fatalf("goa %s: %s", cmd, err)
+		}
+		files = append(files, fs...)
+	}
+	files, err = codegen.RunPlugins(cmd, genpkg, roots, files)
+	if err != nil {
+		t.Fatalf("goa %s: %s", cmd, err)
+	}
+	var paths []string
+	for _, f := range files {
+		filename, err := f.Render(dir)
+		if err != nil {
+			t.Fatalf("goa %s: %s", cmd, err)
+		}
+		if filename == "" {
+			continue
+		}
+		rel, err := filepath.Rel(dir, filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, filepath.ToSlash(rel))
+	}
+	return paths
+}
+
+// committed returns the slash separated paths, relative to root, of the files
+// under the sub directory of root.
+func committed(t *testing.T, root, sub string) []string {
+	t.Helper()
+	var paths []string
+	err := filepath.Walk(filepath.Join(root, sub), func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		paths = append(paths, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return paths
+}
+
+// compare checks that the generated file content matches the committed file
+// content, ignoring the command line section of the headers. The committed
+// file is overwritten with the generated content if the test runs with the
+// -update flag, the committed command line section is kept.
+func compare(t *testing.T, generated, committed, name string) {
+	t.Helper()
+	gen, err := ioutil.ReadFile(generated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	com, err := ioutil.ReadFile(committed)
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+	got := commandRe.ReplaceAllString(string(gen), "")
+	expected := commandRe.ReplaceAllString(string(com), "")
+	if got == expected {
+		return
+	}
+	if *update {
+		content := string(gen)
+		if cmdl := commandRe.FindString(string(com)); cmdl != "" {
+			content = commandRe.ReplaceAllLiteralString(content, cmdl)
+		}
+		if err := os.MkdirAll(filepath.Dir(committed), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(committed, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	if com == nil {
+		t.Errorf("%s: generated file is not committed", name)
+		return
+	}
+	t.Errorf("%s: committed file does not match the generated code, run the tests with -update to update it:\n%s",
+		name, codegen.Diff(t, got, expected))
+}
@@ -0,0 +1,93 @@
+package expr
+
+import (
+	goadsl "goa.design/goa/dsl"
+	"goa.design/goa/eval"
+	"goa.design/goa/expr"
+)
+
+// prepareHealth adds the "HealthStatus" type and the health service to the goa
+// design. The goa root DSL and Prepare steps have already run when the goakit
+// root is prepared so prepareHealth runs them on the added expressions. The
+// Validate and Finalize steps run on the whole design afterwards.
+func prepareHealth() {
+	if expr.Root.Service(HealthService) != nil {
+		eval.ReportError("Health: service %q is already defined", HealthService)
+		return
+	}
+	status := &expr.UserTypeExpr{
+		TypeName: "HealthStatus",
+		AttributeExpr: &expr.AttributeExpr{
+			Type: &expr.Object{},
+			DSLFunc: func() {
+				goadsl.Description("HealthStatus describes the health of the server.")
+				goadsl.Attribute("status", expr.String, "Status of the server", func() {
+					goadsl.Example("ok")
+				})
+				goadsl.Attribute("checks", goadsl.MapOf(expr.String, expr.String), "Status of the dependencies indexed by checker name, either \"ok\" or the error returned by the checker")
+				goadsl.Required("status")
+			},
+		},
+	}
+	svc := &expr.ServiceExpr{
+		Name: HealthService,
+		DSLFunc: func() {
+			goadsl.Description("The health service reports the liveness and readiness of the server.")
+			goadsl.Method("liveness", func() {
+				goadsl.Description("Liveness reports whether the server is running.")
+				goadsl.Result(status)
+				goadsl.HTTP(func() {
+					goadsl.GET("/livez")
+					goadsl.Response(goadsl.StatusOK)
+				})
+			})
+			goadsl.Method("readiness", func() {
+				goadsl.Description("Readiness reports whether the server dependencies are ready to serve requests.")
+				goadsl.Result(status)
+				goadsl.Error("not_ready", goadsl.ErrorResult, "One or more dependency checks failed", func() {
+					goadsl.Temporary()
+				})
+				goadsl.HTTP(func() {
+					goadsl.GET("/readyz")
+					goadsl.Response(goadsl.StatusOK)
+					goadsl.Response("not_ready", goadsl.StatusServiceUnavailable)
+				})
+			})
+		},
+	}
+	expr.Root.Types = append(expr.Root.Types, status)
+	expr.Root.Services = append(expr.Root.Services, svc)
+	ServiceSettings(svc).Health = true
+
+	// Run the DSLs in the order used by the goa root: the methods and the
+	// HTTP endpoints are created by the service DSL.
+	exps := []eval.Expression{status, svc}
+	runDSL(status)
+	runDSL(svc)
+	for _, m := range svc.Methods {
+		exps = append(exps, m)
+		runDSL(m)
+	}
+	if hsvc := expr.Root.API.HTTP.Service(HealthService); hsvc != nil {
+		exps = append(exps, hsvc)
+		runDSL(hsvc)
+		for _, e := range hsvc.HTTPEndpoints {
+			exps = append(exps, e)
+			runDSL(e)
+		}
+	}
+	for _, e := range exps {
+		if p, ok := e.(eval.Preparer); ok {
+			p.Prepare()
+		}
+	}
+}
+
+// runDSL executes the DSL of the given expression if it has one.
+func runDSL(e eval.Expression) {
+	if s, ok := e.(eval.Source); ok {
+		if dsl := s.DSL(); dsl != nil {
+			eval.Execute(dsl, e)
+		}
+	}
+}
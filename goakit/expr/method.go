@@ -0,0 +1,74 @@
+package expr
+
+import (
+	"time"
+
+	"goa.design/goa/eval"
+	"goa.design/goa/expr"
+)
+
+type (
+	// MethodExpr describes the goakit settings of a service method.
+	MethodExpr struct {
+		// Method is the goa method expression the settings apply to.
+		Method *expr.MethodExpr
+		// Service is the goakit settings of the method service.
+		Service *ServiceExpr
+		// NATSSubject is the NATS subject the method is served on. It
+		// overrides the subject computed from the service subject prefix.
+		NATSSubject string
+		// AMQPExchange is the AMQP exchange the method requests are
+		// published to.
+		AMQPExchange string
+		// AMQPQueue is the AMQP queue the method requests are consumed
+		// from, empty if the method is not served over AMQP. The queue
+		// name is also the routing key of the published requests.
+		AMQPQueue string
+		// Timeout is the maximum duration of the method requests, zero if
+		// the requests have no deadline.
+		Timeout time.Duration
+		// RetryMax is the maximum number of times the clients retry failed
+		// requests, zero if the requests are not retried.
+		RetryMax int
+		// RetryBackoff is the duration the clients wait before retrying a
+		// failed request.
+		RetryBackoff time.Duration
+	}
+)
+
+// MethodSettings returns the goakit settings of the given method creating them
+// if needed.
+func MethodSettings(m *expr.MethodExpr) *MethodExpr {
+	return ServiceSettings(m.Service).Method(m)
+}
+
+// EvalName returns the generic expression name used in error messages.
+func (m *MethodExpr) EvalName() string {
+	return "goakit settings of " + m.Method.EvalName()
+}
+
+// RetryErrors returns the names of the method errors marked as temporary or
+// timeout errors in the design. These are the errors the clients retry.
+func (m *MethodExpr) RetryErrors() []string {
+	var names []string
+	for _, er := range m.Method.Errors {
+		if er.Type != expr.ErrorResult {
+			continue
+		}
+		_, temporary := er.Meta["goa:error:temporary"]
+		_, timeout := er.Meta["goa:error:timeout"]
+		if temporary || timeout {
+			names = append(names, er.Name)
+		}
+	}
+	return names
+}
+
+// Validate makes sure the retried methods define errors that can be retried.
+func (m *MethodExpr) Validate() *eval.ValidationErrors {
+	verr := new(eval.ValidationErrors)
+	if m.RetryMax > 0 && len(m.RetryErrors()) == 0 {
+		verr.Add(m, "Retry requires the method to define at least one error marked as Temporary or Timeout")
+	}
+	return verr
+}
@@ -0,0 +1,54 @@
+package expr
+
+import (
+	"testing"
+
+	"goa.design/goa/expr"
+)
+
+func TestMethodValidate(t *testing.T) {
+	var (
+		temporary = &expr.ErrorExpr{
+			Name:          "unavailable",
+			AttributeExpr: &expr.AttributeExpr{Type: expr.ErrorResult, Meta: expr.MetaExpr{"goa:error:temporary": nil}},
+		}
+		timeout = &expr.ErrorExpr{
+			Name:          "deadline",
+			AttributeExpr: &expr.AttributeExpr{Type: expr.ErrorResult, Meta: expr.MetaExpr{"goa:error:timeout": nil}},
+		}
+		permanent = &expr.ErrorExpr{
+			Name:          "bad_request",
+			AttributeExpr: &expr.AttributeExpr{Type: expr.ErrorResult},
+		}
+	)
+	cases := map[string]struct {
+		RetryMax int
+		Errors   []*expr.ErrorExpr
+		Invalid  bool
+	}{
+		"no-retry":         {0, nil, false},
+		"temporary":        {3, []*expr.ErrorExpr{permanent, temporary}, false},
+		"timeout":          {3, []*expr.ErrorExpr{timeout}, false},
+		"no-error":         {3, nil, true},
+		"no-retried-error": {3, []*expr.ErrorExpr{permanent}, true},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := &MethodExpr{
+				Method: &expr.MethodExpr{
+					Name:    "Method",
+					Service: &expr.ServiceExpr{Name: "Service"},
+					Errors:  c.Errors,
+				},
+				RetryMax: c.RetryMax,
+			}
+			verr := m.Validate()
+			if verr == nil {
+				t.Fatal("got nil validation errors")
+			}
+			if invalid := len(verr.Errors) > 0; invalid != c.Invalid {
+				t.Errorf("got invalid %v, expected %v: %v", invalid, c.Invalid, verr)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package expr
+
+import (
+	"goa.design/goa/eval"
+	"goa.design/goa/expr"
+)
+
+// Root is the design root expression.
+var Root = &RootExpr{
+	Services: map[string]*ServiceExpr{},
+}
+
+type (
+	// RootExpr keeps track of the goakit settings defined in the design.
+	RootExpr struct {
+		// Tracing is true if the endpoints of all the services must be
+		// traced.
+		Tracing bool
+		// Health is true if the health service must be added to the
+		// design, see the Health DSL.
+		Health bool
+		// Services lists the service level goakit settings indexed by
+		// service name.
+		Services map[string]*ServiceExpr
+	}
+)
+
+// Register design root with eval engine.
+func init() {
+	eval.Register(Root)
+}
+
+// Reset clears the goakit settings recorded by a previous evaluation of the
+// DSL. It must be called before evaluating a new design in the same process,
+// e.g. in tests. Root keeps pointing to the same expression so that it stays
+// registered with the eval engine.
+func Reset() {
+	*Root = RootExpr{Services: map[string]*ServiceExpr{}}
+}
+
+// EvalName returns the name used in error messages.
+func (r *RootExpr) EvalName() string {
+	return "goakit plugin"
+}
+
+// WalkSets iterates over the root and the service and method level goakit
+// settings. The root comes first so that the health service settings added
+// by Prepare are walked.
+func (r *RootExpr) WalkSets(walk eval.SetWalker) {
+	walk(eval.ExpressionSet{r})
+	sexps := make(eval.ExpressionSet, 0, len(r.Services))
+	for _, s := range r.Services {
+		sexps = append(sexps, s)
+	}
+	walk(sexps)
+	var mexps eval.ExpressionSet
+	for _, s := range r.Services {
+		for _, m := range s.Methods {
+			mexps = append(mexps, m)
+		}
+	}
+	walk(mexps)
+}
+
+// Prepare adds the health service to the design if the Health DSL was used.
+func (r *RootExpr) Prepare() {
+	if r.Health {
+		prepareHealth()
+	}
+}
+
+// DependsOn tells the eval engine to run the goa DSL first.
+func (r *RootExpr) DependsOn() []eval.Root {
+	return []eval.Root{expr.Root}
+}
+
+// Packages returns the import path to the Go packages that make
+// up the DSL. This is used to skip frames that point to files
+// in these packages when computing the location of errors.
+func (r *RootExpr) Packages() []string {
+	return []string{"goa.design/plugins/goakit/dsl"}
+}
+
+// Service returns the goakit settings of the service with the given name, nil
+// if there isn't one.
+func (r *RootExpr) Service(name string) *ServiceExpr {
+	return r.Services[name]
+}
@@ -0,0 +1,116 @@
+package expr
+
+import (
+	"fmt"
+
+	"goa.design/goa/expr"
+)
+
+// HealthService is the name of the service added to the design by the Health
+// DSL.
+const HealthService = "health"
+
+type (
+	// ServiceExpr describes the goakit settings of a service.
+	ServiceExpr struct {
+		// Service is the goa service expression the settings apply to.
+		Service *expr.ServiceExpr
+		// Tracing is true if the service endpoints must be traced.
+		Tracing bool
+		// NATSSubjectPrefix is the prefix of the NATS subjects the service
+		// methods are served on, empty if the service is not served over
+		// NATS.
+		NATSSubjectPrefix string
+		// JSONRPC is true if the service is served over JSON-RPC.
+		JSONRPC bool
+		// Health is true if the service is the health service added to
+		// the design by the Health DSL.
+		Health bool
+		// Methods lists the method level goakit settings indexed by method
+		// name.
+		Methods map[string]*MethodExpr
+	}
+)
+
+// ServiceSettings returns the goakit settings of the given service creating
+// them if needed.
+func ServiceSettings(svc *expr.ServiceExpr) *ServiceExpr {
+	if s, ok := Root.Services[svc.Name]; ok {
+		return s
+	}
+	s := &ServiceExpr{Service: svc, Methods: map[string]*MethodExpr{}}
+	Root.Services[svc.Name] = s
+	return s
+}
+
+// Traced returns true if the endpoints of the service with the given name must
+// be traced.
+func Traced(svc string) bool {
+	if Root.Tracing {
+		return true
+	}
+	s := Root.Service(svc)
+	return s != nil && s.Tracing
+}
+
+// NATSSubject returns the NATS subject the given service method is served on,
+// empty if the method is not served over NATS. The subject defaults to the
+// service subject prefix followed by a dot and the method name.
+func NATSSubject(svc, method string) string {
+	s := Root.Service(svc)
+	if s == nil {
+		return ""
+	}
+	if m, ok := s.Methods[method]; ok && m.NATSSubject != "" {
+		return m.NATSSubject
+	}
+	if s.NATSSubjectPrefix == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s.%s", s.NATSSubjectPrefix, method)
+}
+
+// JSONRPC returns true if the service with the given name is served over
+// JSON-RPC.
+func JSONRPC(svc string) bool {
+	s := Root.Service(svc)
+	return s != nil && s.JSONRPC
+}
+
+// Health returns true if the service with the given name is the health service
+// added to the design by the Health DSL.
+func Health(svc string) bool {
+	s := Root.Service(svc)
+	return s != nil && s.Health
+}
+
+// Method returns the goakit settings of the given service method, nil if there
+// isn't any.
+func Method(svc, method string) *MethodExpr {
+	s := Root.Service(svc)
+	if s == nil {
+		return nil
+	}
+	return s.Methods[method]
+}
+
+// SpanName returns the name of the spans created for the given service method.
+func SpanName(svc, method string) string {
+	return fmt.Sprintf("%s.%s", svc, method)
+}
+
+// EvalName returns the generic expression name used in error messages.
+func (s *ServiceExpr) EvalName() string {
+	return "goakit settings of " + s.Service.EvalName()
+}
+
+// Method returns the goakit settings of the given service method creating them
+// if needed.
+func (s *ServiceExpr) Method(m *expr.MethodExpr) *MethodExpr {
+	if me, ok := s.Methods[m.Name]; ok {
+		return me
+	}
+	me := &MethodExpr{Method: m, Service: s}
+	s.Methods[m.Name] = me
+	return me
+}
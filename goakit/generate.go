@@ -1,14 +1,16 @@
 package goakit
 
 import (
+	"fmt"
 	"path"
-	"regexp"
+	"path/filepath"
 	"strings"
 
 	"goa.design/goa/codegen"
 	"goa.design/goa/eval"
 	"goa.design/goa/expr"
 	httpcodegen "goa.design/goa/http/codegen"
+	goakitexpr "goa.design/plugins/goakit/expr"
 )
 
 // Register the plugin Generator functions.
@@ -23,7 +25,20 @@ func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codeg
 	for _, root := range roots {
 		if r, ok := root.(*expr.RootExpr); ok {
 			files = append(files, EncodeDecodeFiles(genpkg, r)...)
+			files = append(files, EndpointFiles(genpkg, r)...)
+			files = append(files, ValidationFiles(genpkg, r)...)
+			files = append(files, HealthFiles(genpkg, r)...)
 			files = append(files, MountFiles(r)...)
+			files = append(files, OptionsFiles(r)...)
+			files = append(files, LoggingFiles(r)...)
+			files = append(files, PolicyFiles(genpkg, r)...)
+			files = append(files, TracingFiles(genpkg, r)...)
+			files = append(files, SDFiles(genpkg, r)...)
+			files = append(files, ClientFiles(genpkg, r)...)
+			files = append(files, TransportTestFiles(genpkg, r)...)
+			files = append(files, NATSFiles(genpkg, r)...)
+			files = append(files, AMQPFiles(genpkg, r)...)
+			files = append(files, JSONRPCFiles(genpkg, r)...)
 		}
 	}
 	return files, nil
@@ -33,36 +48,73 @@ func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codeg
 // imports and replacing the following instances
 // * "goa.Endpoint" with "github.com/go-kit/kit/endpoint".Endpoint
 // * "log.Logger" with "github.com/go-kit/kit/log".Logger
-// and adding the corresponding imports.
+// and adding the corresponding imports. It also modifies the CLI so that it
+// makes requests using the go-kit HTTP clients. It returns an error if the
+// endpoints file of a service does not make use of goa.Endpoint or if the CLI
+// does not contain the sections modified by the plugin.
 func Goakitify(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	expected := map[string]bool{}
+	hooks := map[string][]*sectionHook{}
+	for _, root := range roots {
+		if r, ok := root.(*expr.RootExpr); ok {
+			for _, svc := range r.Services {
+				if len(svc.Methods) > 0 {
+					expected[filepath.Join(codegen.Gendir, codegen.SnakeCase(svc.Name), "endpoints.go")] = true
+				}
+			}
+			if h := cliHooks(genpkg, r); h != nil {
+				for _, svr := range r.API.Servers {
+					pkg := codegen.SnakeCase(codegen.Goify(svr.Name, true))
+					hooks[filepath.Join(codegen.Gendir, "http", "cli", pkg, "cli.go")] = h
+				}
+			}
+		}
+	}
 	for _, f := range files {
-		goakitify(f)
+		if !goakitify(f) && expected[f.Path] {
+			return nil, fmt.Errorf("goakit: %s: goa.Endpoint not found, the goa templates may have changed", f.Path)
+		}
+		if err := applyHooks(f, hooks[f.Path]); err != nil {
+			return nil, err
+		}
 	}
 	return files, nil
 }
 
-// GoakitifyExample  modifies all the previously generated example files by
-// adding go-kit imports.
+// GoakitifyExample modifies all the previously generated example files by
+// adding go-kit imports and replacing the stdlib logger with the go-kit
+// logger. It returns an error if the example server files do not contain the
+// sections modified by the plugin.
 func GoakitifyExample(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	hooks := map[string][]*sectionHook{}
+	for _, root := range roots {
+		if r, ok := root.(*expr.RootExpr); ok {
+			for _, svr := range r.API.Servers {
+				pkg := codegen.SnakeCase(codegen.Goify(svr.Name, true))
+				hooks[filepath.Join("cmd", pkg, "main.go")] = mainHooks()
+				hooks[filepath.Join("cmd", pkg, "http.go")] = httpHooks(genpkg)
+			}
+		}
+	}
 	for _, f := range files {
-		gokitifyExampleServer(genpkg, f)
+		if err := gokitifyExampleServer(f, hooks[f.Path]); err != nil {
+			return nil, err
+		}
 	}
 	return files, nil
 }
 
-// goaEndpointRegexp matches occurrences of the "goa.Endpoint" type in Go code.
-var goaEndpointRegexp = regexp.MustCompile(`([^\p{L}_])goa\.Endpoint([^\p{L}_])`)
-
 // goakitify replaces all occurrences of goa.Endpoint with
 // "github.com/go-kit/kit/endpoint".Endpoint in the file section template
-// sources.
-func goakitify(f *codegen.File) {
+// sources. It returns true if the file made use of goa.Endpoint.
+func goakitify(f *codegen.File) bool {
 	var hasEndpoint bool
 	for _, s := range f.SectionTemplates {
-		if !hasEndpoint {
-			hasEndpoint = goaEndpointRegexp.MatchString(s.Source)
+		var n int
+		s.Source, n = replaceQualifiedIdent(s.Source, "goa.Endpoint", "endpoint.Endpoint")
+		if n > 0 {
+			hasEndpoint = true
 		}
-		s.Source = goaEndpointRegexp.ReplaceAllString(s.Source, "${1}endpoint.Endpoint${2}")
 	}
 	if hasEndpoint {
 		codegen.AddImport(
@@ -70,48 +122,36 @@ func goakitify(f *codegen.File) {
 			&codegen.ImportSpec{Path: "github.com/go-kit/kit/endpoint"},
 		)
 	}
+	return hasEndpoint
 }
 
-// goaLoggerRegexp matches occurrences of "logger.<function>" in Go code.
-var goaLoggerRegexp = regexp.MustCompile(`logger\.\w+\((.*)\)`)
-
 // gokitifyExampleServer imports gokit endpoint, logger, and transport
 // packages in the example server implementation. It also replaces every stdlib
-// logger with gokit logger.
-func gokitifyExampleServer(genpkg string, file *codegen.File) {
+// logger with gokit logger and runs the given hooks.
+func gokitifyExampleServer(file *codegen.File, hooks []*sectionHook) error {
 	goakitify(file)
-	var hasLogger bool
+	var hasLogger, hasLoggerCall bool
 	for _, s := range file.SectionTemplates {
 		if !hasLogger {
 			hasLogger = strings.Contains(s.Source, "*log.Logger")
 		}
 		s.Source = strings.Replace(s.Source, "*log.Logger", "log.Logger", -1)
-		codegen.AddImport(file.SectionTemplates[0], &codegen.ImportSpec{Path: "fmt"})
-		s.Source = goaLoggerRegexp.ReplaceAllString(s.Source, "logger.Log(\"info\", fmt.Sprintf(${1}))")
-
-		switch s.Name {
-		case "server-main-logger":
-			codegen.AddImport(file.SectionTemplates[0], &codegen.ImportSpec{Path: "github.com/go-kit/kit/log"})
-			s.Source = gokitLoggerT
-		case "server-http-logger":
-			s.Source = ""
-		case "server-http-middleware":
-			s.Source = strings.Replace(s.Source, "adapter", "logger", -1)
-		case "server-http-init":
-			codegen.AddImport(file.SectionTemplates[0], &codegen.ImportSpec{Path: "github.com/go-kit/kit/transport/http", Name: "kithttp"})
-			codegen.AddImport(file.SectionTemplates[0], &codegen.ImportSpec{Path: "github.com/go-kit/kit/endpoint"})
-			data := s.Data.(map[string]interface{})
-			svcs := data["Services"].([]*httpcodegen.ServiceData)
-			for _, svc := range svcs {
-				pkgName := httpcodegen.HTTPServices.Get(svc.Service.Name).Service.PkgName
-				codegen.AddImport(file.SectionTemplates[0], &codegen.ImportSpec{
-					Path: path.Join(genpkg, "http", svc.Service.Name, "kitserver"),
-					Name: pkgName + "kitsvr",
-				})
-			}
-			s.Source = gokitServerInitT
+		src, n, err := rewriteLoggerCalls(s.Source)
+		if err != nil {
+			return fmt.Errorf("goakit: %s: section %q: %s", file.Path, s.Name, err)
+		}
+		s.Source = src
+		if n > 0 {
+			hasLoggerCall = true
 		}
 	}
+	if hasLoggerCall {
+		codegen.AddImport(file.SectionTemplates[0], &codegen.ImportSpec{Path: "fmt"})
+		codegen.AddImport(file.SectionTemplates[0], &codegen.ImportSpec{Path: "github.com/go-kit/kit/log/level"})
+	}
+	if err := applyHooks(file, hooks); err != nil {
+		return err
+	}
 	if hasLogger {
 		// Replace existing stdlib logger with gokit logger in imports
 		if data, ok := file.SectionTemplates[0].Data.(map[string]interface{}); ok {
@@ -125,15 +165,163 @@ func gokitifyExampleServer(genpkg string, file *codegen.File) {
 			}
 		}
 	}
+	return nil
 }
 
+// cliHooks returns the hooks applied to the CLI support file. The hooks make
+// the CLI use the go-kit HTTP clients of the services that have one. cliHooks
+// returns nil if no service has a go-kit HTTP client.
+func cliHooks(genpkg string, root *expr.RootExpr) []*sectionHook {
+	// kitPkgs maps the goa HTTP client package names used by the CLI to the
+	// corresponding go-kit HTTP client package names.
+	kitPkgs := map[string]string{}
+	var specs []*codegen.ImportSpec
+	for _, svc := range root.API.HTTP.Services {
+		data := httpcodegen.HTTPServices.Get(svc.Name())
+		if !hasKitClient(data) {
+			continue
+		}
+		kitPkgs[data.Service.PkgName+"c"] = data.Service.PkgName + "kc"
+		specs = append(specs, &codegen.ImportSpec{
+			Path: path.Join(genpkg, "http", data.Service.Name, "kitclient"),
+			Name: data.Service.PkgName + "kc",
+		})
+	}
+	if len(specs) == 0 {
+		return nil
+	}
+	return []*sectionHook{{
+		Snippet: ".NewClient(",
+		Rewrite: func(header, s *codegen.SectionTemplate) error {
+			for _, spec := range specs {
+				codegen.AddImport(header, spec)
+			}
+			if s.FuncMap == nil {
+				s.FuncMap = codegen.TemplateFuncs()
+			}
+			s.FuncMap["kitClientPkg"] = func(pkg string) string {
+				if kpkg, ok := kitPkgs[pkg]; ok {
+					return kpkg
+				}
+				return pkg
+			}
+			return replaceSnippet(s, "{{ .PkgName }}.NewClient(", "{{ kitClientPkg .PkgName }}.NewClient(")
+		},
+	}}
+}
+
+// mainHooks returns the hooks applied to the example server main file.
+func mainHooks() []*sectionHook {
+	return []*sectionHook{{
+		// Define the log format flag used to setup the go-kit logger.
+		Snippet: "flag.Parse()",
+		Rewrite: func(_, s *codegen.SectionTemplate) error {
+			return replaceSnippet(s, "flag.Parse()", logFormatFlagT+"flag.Parse()")
+		},
+	}, {
+		Section: "server-main-logger",
+		Rewrite: func(header, s *codegen.SectionTemplate) error {
+			if !strings.Contains(s.Source, "logger") {
+				return fmt.Errorf("section %q does not define logger, the goa templates may have changed", s.Name)
+			}
+			codegen.AddImport(header, &codegen.ImportSpec{Path: "github.com/go-kit/kit/log"})
+			s.Source = gokitLoggerT
+			return nil
+		},
+	}}
+}
+
+// httpHooks returns the hooks applied to the example server HTTP file.
+func httpHooks(genpkg string) []*sectionHook {
+	return []*sectionHook{
+		{
+			// The go-kit logger implements the goa middleware logger
+			// interface, the adapter is not needed.
+			Section: "server-http-logger",
+			Rewrite: func(_, s *codegen.SectionTemplate) error {
+				if !strings.Contains(s.Source, "adapter") {
+					return fmt.Errorf("section %q does not define adapter, the goa templates may have changed", s.Name)
+				}
+				s.Source = ""
+				return nil
+			},
+		},
+		{
+			Section: "server-http-middleware",
+			Rewrite: func(_, s *codegen.SectionTemplate) error {
+				return replaceSnippet(s, "adapter", "logger")
+			},
+		},
+		{
+			Section: "server-http-init",
+			Rewrite: func(header, s *codegen.SectionTemplate) error {
+				return gokitifyServerInit(genpkg, header, s)
+			},
+		},
+	}
+}
+
+// gokitifyServerInit replaces the example HTTP server initialization code with
+// code that wraps the endpoints in go-kit HTTP servers.
+func gokitifyServerInit(genpkg string, header, s *codegen.SectionTemplate) error {
+	data, ok := s.Data.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("section %q: unexpected data type %T, the goa templates may have changed", s.Name, s.Data)
+	}
+	svcs, ok := data["Services"].([]*httpcodegen.ServiceData)
+	if !ok {
+		return fmt.Errorf("section %q: no HTTP services data, the goa templates may have changed", s.Name)
+	}
+	codegen.AddImport(header, &codegen.ImportSpec{Path: "github.com/go-kit/kit/transport/http", Name: "kithttp"})
+	codegen.AddImport(header, &codegen.ImportSpec{Path: "github.com/go-kit/kit/endpoint"})
+	for _, svc := range svcs {
+		pkgName := httpcodegen.HTTPServices.Get(svc.Service.Name).Service.PkgName
+		codegen.AddImport(header, &codegen.ImportSpec{
+			Path: path.Join(genpkg, "http", svc.Service.Name, "kitserver"),
+			Name: pkgName + "kitsvr",
+		})
+	}
+	if needTracing(svcs) {
+		codegen.AddImport(header, &codegen.ImportSpec{Path: "github.com/opentracing/opentracing-go", Name: "opentracing"})
+	}
+	if s.FuncMap == nil {
+		s.FuncMap = codegen.TemplateFuncs()
+	}
+	s.FuncMap["isTraced"] = goakitexpr.Traced
+	s.FuncMap["needTracing"] = needTracing
+	s.FuncMap["isStreaming"] = isStreaming
+	s.FuncMap["hasTimeout"] = hasTimeout
+	s.Source = gokitServerInitT
+	return nil
+}
+
+// needTracing returns true if at least one of the given services has tracing
+// enabled.
+func needTracing(svcs []*httpcodegen.ServiceData) bool {
+	for _, svc := range svcs {
+		if goakitexpr.Traced(svc.Service.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+const logFormatFlagT = `logFormatF := flag.String("log-format", "logfmt", "Log format (valid values: logfmt, json)")
+	`
+
 const gokitLoggerT = `
   // Setup gokit logger.
   var (
     logger log.Logger
   )
   {
-    logger = log.NewLogfmtLogger(os.Stderr)
+    w := log.NewSyncWriter(os.Stderr)
+    switch *logFormatF {
+    case "json":
+      logger = log.NewJSONLogger(w)
+    default:
+      logger = log.NewLogfmtLogger(w)
+    }
     logger = log.With(logger, "ts", log.DefaultTimestampUTC)
     logger = log.With(logger, "caller", log.DefaultCaller)
   }
@@ -147,7 +335,9 @@ const gokitServerInitT = `
   var (
   {{- range .Services }}
     {{- range .Endpoints }}
+      {{- if not (isStreaming .) }}
       {{ .ServiceVarName }}{{ .Method.VarName }}Handler *kithttp.Server
+      {{- end }}
     {{- end }}
     {{ .Service.VarName }}Server *{{.Service.PkgName}}svr.Server
   {{- end }}
@@ -157,19 +347,35 @@ const gokitServerInitT = `
     {{- if needStream .Services }}
       upgrader := &websocket.Upgrader{}
     {{- end }}
+    {{- if needTracing .Services }}
+      tracer := opentracing.GlobalTracer()
+    {{- end }}
   {{- range .Services }}
     {{- if .Endpoints }}
       {{- range .Endpoints }}
+      {{- if not (isStreaming .) }}
         {{ .ServiceVarName }}{{ .Method.VarName }}Handler = kithttp.NewServer(
-          endpoint.Endpoint({{ .ServiceVarName }}Endpoints.{{ .Method.VarName }}),
-          {{- if .Payload.Ref }}
+        {{- if isTraced .ServiceName }}
+          {{ .ServicePkgName }}kitsvr.Trace{{ .Method.VarName }}Endpoint(tracer)({{ .ServicePkgName }}kitsvr.Log{{ .Method.VarName }}Endpoint(logger)({{ if hasTimeout .ServiceName .Method.Name }}{{ .ServicePkgName }}kitsvr.Timeout{{ .Method.VarName }}Endpoint()({{ end }}endpoint.Endpoint({{ .ServiceVarName }}Endpoints.{{ .Method.VarName }}){{ if hasTimeout .ServiceName .Method.Name }}){{ end }})),
+        {{- else }}
+          {{ .ServicePkgName }}kitsvr.Log{{ .Method.VarName }}Endpoint(logger)({{ if hasTimeout .ServiceName .Method.Name }}{{ .ServicePkgName }}kitsvr.Timeout{{ .Method.VarName }}Endpoint()({{ end }}endpoint.Endpoint({{ .ServiceVarName }}Endpoints.{{ .Method.VarName }}){{ if hasTimeout .ServiceName .Method.Name }}){{ end }}),
+        {{- end }}
+          {{- if .MultipartRequestDecoder }}
+            {{ .ServicePkgName}}kitsvr.{{ .RequestDecoder }}(mux, {{ $.APIPkg }}.{{ .MultipartRequestDecoder.FuncName }}),
+          {{- else if .Payload.Ref }}
             {{ .ServicePkgName}}kitsvr.{{ .RequestDecoder }}(mux, dec),
           {{- else }}
             func(context.Context, *http.Request) (request interface{}, err error) { return nil, nil },
           {{- end }}
           {{ .ServicePkgName}}kitsvr.{{ .ResponseEncoder }}(enc),
+        {{- if isTraced .ServiceName }}
+          {{ .ServicePkgName }}kitsvr.ServerOptions({{ .ServicePkgName }}kitsvr.{{ .Method.VarName }}TraceServerOptions(tracer, logger)...)...,
+        {{- else }}
+          {{ .ServicePkgName }}kitsvr.ServerOptions()...,
+        {{- end }}
         )
       {{- end }}
+      {{- end }}
       {{ .Service.VarName }}Server = {{ .Service.PkgName }}svr.New({{ .Service.VarName }}Endpoints, mux, dec, enc, eh{{ if needStream $.Services }}, upgrader, nil{{ end }}{{ range .Endpoints }}{{ if .MultipartRequestDecoder }}, {{ $.APIPkg }}.{{ .MultipartRequestDecoder.FuncName }}{{ end }}{{ end }})
     {{-  else }}
       {{ .Service.VarName }}Server = {{ .Service.PkgName }}svr.New(nil, mux, dec, enc, eh)
@@ -180,7 +386,11 @@ const gokitServerInitT = `
   // Configure the mux.
   {{- range .Services }}{{ $service := . }}
     {{- range .Endpoints }}
+      {{- if isStreaming . }}
+  {{ $service.Service.PkgName }}svr.{{ .MountHandler }}(mux, {{ $service.Service.VarName }}Server.{{ .Method.VarName }})
+      {{- else }}
   {{ .ServicePkgName}}kitsvr.{{ .MountHandler }}(mux, {{ .ServiceVarName }}{{ .Method.VarName }}Handler)
+      {{- end }}
     {{- end }}
     {{- range .FileServers }}
   {{ $service.Service.PkgName}}kitsvr.{{ .MountHandler }}(mux)
@@ -2,6 +2,7 @@ package goakit
 
 import (
 	"bytes"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -10,6 +11,7 @@ import (
 	"goa.design/goa/eval"
 	"goa.design/goa/expr"
 	httpcodegen "goa.design/goa/http/codegen"
+	goakitexpr "goa.design/plugins/goakit/expr"
 	"goa.design/plugins/goakit/testdata"
 )
 
@@ -18,12 +20,12 @@ func TestGenerate(t *testing.T) {
 		DSL      func()
 		ExpFiles int
 	}{
-		"multi-endpoints": {testdata.MultiEndpointDSL, 3},
-		"multi-services":  {testdata.MultiServiceDSL, 6},
+		"multi-endpoints": {testdata.MultiEndpointDSL, 10},
+		"multi-services":  {testdata.MultiServiceDSL, 20},
 	}
 	for name, c := range cases {
 		t.Run(name, func(t *testing.T) {
-			httpcodegen.RunHTTPDSL(t, c.DSL)
+			runDSL(t, c.DSL)
 			roots := []eval.Root{expr.Root}
 			files := generateFiles(t, roots)
 			newFiles, err := Generate("", roots, files)
@@ -39,27 +41,26 @@ func TestGenerate(t *testing.T) {
 }
 
 func TestGoakitify(t *testing.T) {
-	// Map all files containing goaEndpoint indexed by file path
-	goaEndpointFiles := map[string]bool{}
 	cases := map[string]func(){
 		"multi-endpoints": testdata.MultiEndpointDSL,
 		"multi-services":  testdata.MultiServiceDSL,
 	}
 	for name, dsl := range cases {
 		t.Run(name, func(t *testing.T) {
-			httpcodegen.RunHTTPDSL(t, dsl)
+			runDSL(t, dsl)
 			roots := []eval.Root{expr.Root}
 			files := generateFiles(t, roots)
+			// Before state: Collect all files with goa endpoint.
+			goaEndpointFiles := map[string]bool{}
+			for _, f := range files {
+				goaEndpointFiles[f.Path] = containsGoaEndpoint(f)
+			}
 			newFiles, err := Goakitify("", roots, files)
 			if err != nil {
 				t.Fatalf("generate error: %v", err)
 			}
-			// Before state: Collect all files with goa endpoint.
-			for _, f := range newFiles {
-				goaEndpointFiles[f.Path] = containsGoaEndpoint(f)
-			}
 			// After state: files with goa endpoint should be replaced by gokit endpoint
-			for _, f := range files {
+			for _, f := range newFiles {
 				if goaEndpointFiles[f.Path] {
 					if containsGoaEndpoint(f) {
 						t.Errorf("file %s still has goa endpoints", f.Path)
@@ -78,6 +79,50 @@ func TestGoakitify(t *testing.T) {
 	}
 }
 
+func TestGoakitifyCLI(t *testing.T) {
+	cases := map[string]struct {
+		DSL     func()
+		Clients []string
+	}{
+		"multi-services": {testdata.MultiServiceDSL, []string{"service1kc.NewClient(", "service2kc.NewClient("}},
+		"mixed":          {testdata.MixedDSL, []string{"mixedservicekc.NewClient("}},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			runDSL(t, c.DSL)
+			roots := []eval.Root{expr.Root}
+			files, err := Goakitify("", roots, generateFiles(t, roots))
+			if err != nil {
+				t.Fatalf("generate error: %v", err)
+			}
+			var code string
+			for _, f := range files {
+				if !strings.HasPrefix(f.Path, filepath.Join(codegen.Gendir, "http", "cli")) {
+					continue
+				}
+				buf := new(bytes.Buffer)
+				for _, s := range f.SectionTemplates {
+					if err := s.Write(buf); err != nil {
+						t.Fatalf("error writing section %s in file %s: %v", s.Name, f.Path, err)
+					}
+				}
+				code = buf.String()
+			}
+			if code == "" {
+				t.Fatal("CLI file not found")
+			}
+			for _, cl := range c.Clients {
+				if !strings.Contains(code, cl) {
+					t.Errorf("CLI does not use go-kit client %q:\n%s", cl, code)
+				}
+			}
+			if !strings.Contains(code, "/kitclient\"") {
+				t.Errorf("go-kit client not imported in CLI:\n%s", code)
+			}
+		})
+	}
+}
+
 func TestGoakitifyExample(t *testing.T) {
 	cases := map[string]struct {
 		DSL  func()
@@ -86,33 +131,53 @@ func TestGoakitifyExample(t *testing.T) {
 		"mixed": {
 			DSL: testdata.MixedDSL,
 			Code: map[string]string{
-				"service-main-logger":      testdata.MixedMainLoggerCode,
-				"service-main-middleware":  testdata.MixedMainMiddlewareCode,
-				"service-main-server-init": testdata.MixedMainServerInitCode,
+				"server-main-logger":     testdata.MixedMainLoggerCode,
+				"server-http-middleware": testdata.MixedMainMiddlewareCode,
+				"server-http-init":       testdata.MixedMainServerInitCode,
 			},
 		},
 		"multi-services": {
 			DSL: testdata.MultiServiceDSL,
 			Code: map[string]string{
-				"service-main-server-init": testdata.MultiServicesServerInitCode,
+				"server-http-init": testdata.MultiServicesServerInitCode,
+			},
+		},
+		"tracing": {
+			DSL: testdata.TracingDSL,
+			Code: map[string]string{
+				"server-http-init": testdata.TracingServerInitCode,
+			},
+		},
+		"multipart": {
+			DSL: testdata.MultipartDSL,
+			Code: map[string]string{
+				"server-http-init": testdata.MultipartServerInitCode,
+			},
+		},
+		"streaming": {
+			DSL: testdata.StreamingDSL,
+			Code: map[string]string{
+				"server-http-init": testdata.StreamingServerInitCode,
 			},
 		},
 	}
 	for name, c := range cases {
 		t.Run(name, func(t *testing.T) {
-			httpcodegen.RunHTTPDSL(t, c.DSL)
+			runDSL(t, c.DSL)
 			roots := []eval.Root{expr.Root}
 			files := generateExamples(t, roots)
 			files, err := GoakitifyExample("", roots, files)
 			if err != nil {
 				t.Fatalf("examples generate error: %v", err)
 			}
+			found := map[string]bool{}
 			for _, f := range files {
 				if containsStdlibLogger(f) {
 					t.Errorf("file %s still has stdlib logger instances", f.Path)
 				}
 				for _, s := range f.SectionTemplates {
 					if expCode, ok := c.Code[s.Name]; ok {
+						found[s.Name] = true
 						buf := new(bytes.Buffer)
 						if err := s.Write(buf); err != nil {
 							t.Fatalf("error writing section in file %s", f.Path)
@@ -125,10 +190,62 @@ func TestGoakitifyExample(t *testing.T) {
 					}
 				}
 			}
+			for name := range c.Code {
+				if !found[name] {
+					t.Errorf("section %s not found", name)
+				}
+			}
+		})
+	}
+}
+
+// TestGoaTemplates makes sure that the sections modified by the plugin can be
+// found in the code generated by the current goa templates for all the test
+// designs.
+func TestGoaTemplates(t *testing.T) {
+	cases := map[string]func(){
+		"multi-endpoints": testdata.MultiEndpointDSL,
+		"multi-services":  testdata.MultiServiceDSL,
+		"mixed":           testdata.MixedDSL,
+		"tracing":         testdata.TracingDSL,
+		"multipart":       testdata.MultipartDSL,
+		"streaming":       testdata.StreamingDSL,
+		"nats":            testdata.NATSDSL,
+		"jsonrpc":         testdata.JSONRPCDSL,
+	}
+	for name, dsl := range cases {
+		t.Run(name, func(t *testing.T) {
+			runDSL(t, dsl)
+			roots := []eval.Root{expr.Root}
+			if _, err := Goakitify("", roots, generateFiles(t, roots)); err != nil {
+				t.Errorf("goakitify error: %v", err)
+			}
+			files, err := GoakitifyExample("", roots, generateExamples(t, roots))
+			if err != nil {
+				t.Fatalf("goakitify example error: %v", err)
+			}
+			for _, f := range files {
+				for _, s := range f.SectionTemplates {
+					if _, n, _ := rewriteLoggerCalls(s.Source); n > 0 {
+						t.Errorf("file %s section %s still has stdlib logger calls", f.Path, s.Name)
+					}
+				}
+			}
 		})
 	}
 }
 
+// runDSL resets the goakit settings recorded by the previous tests and runs
+// the given design. RunHTTPDSL resets the eval context so the goakit root is
+// registered again by the design to be prepared and validated.
+func runDSL(t *testing.T, dsl func()) *expr.RootExpr {
+	goakitexpr.Reset()
+	return httpcodegen.RunHTTPDSL(t, func() {
+		eval.Register(goakitexpr.Root)
+		dsl()
+	})
+}
+
 func generateFiles(t *testing.T, roots []eval.Root) []*codegen.File {
 	files, err := generator.Service("", roots)
 	if err != nil {
@@ -152,7 +269,7 @@ func generateExamples(t *testing.T, roots []eval.Root) []*codegen.File {
 
 func containsGoaEndpoint(f *codegen.File) bool {
 	for _, s := range f.SectionTemplates {
-		if goaEndpointRegexp.MatchString(s.Source) {
+		if _, n := replaceQualifiedIdent(s.Source, "goa.Endpoint", ""); n > 0 {
 			return true
 		}
 	}
@@ -0,0 +1,161 @@
+package goakit
+
+import (
+	"path/filepath"
+
+	"goa.design/goa/codegen"
+	"goa.design/goa/expr"
+	httpcodegen "goa.design/goa/http/codegen"
+	goakitexpr "goa.design/plugins/goakit/expr"
+)
+
+// HealthFiles produces the file implementing the health service added to the
+// design by the Health DSL.
+func HealthFiles(genpkg string, root *expr.RootExpr) []*codegen.File {
+	for _, svc := range root.API.HTTP.Services {
+		if goakitexpr.Health(svc.Name()) {
+			return []*codegen.File{health(genpkg, svc)}
+		}
+	}
+	return nil
+}
+
+// health returns the file implementing the given health service.
+func health(genpkg string, svc *expr.HTTPServiceExpr) *codegen.File {
+	data := httpcodegen.HTTPServices.Get(svc.Name())
+	path := filepath.Join(codegen.Gendir, codegen.SnakeCase(svc.Name()), "kithealth", "health.go")
+	sections := []*codegen.SectionTemplate{
+		codegen.Header("health service implementation", "kithealth", []*codegen.ImportSpec{
+			{Path: "context"},
+			{Path: "errors"},
+			{Path: "fmt"},
+			{Path: "net/http"},
+			{Path: "strings"},
+			{Path: "sync"},
+			{Path: "goa.design/goa/http", Name: "goahttp"},
+			{Path: genpkg + "/" + codegen.SnakeCase(svc.Name()), Name: data.Service.PkgName},
+		}),
+		{
+			Name:   "goakit-health-checker",
+			Source: healthCheckerT,
+		},
+		{
+			Name:   "goakit-health-service",
+			Source: healthServiceT,
+			Data:   data.Service,
+		},
+	}
+
+	return &codegen.File{Path: path, SectionTemplates: sections}
+}
+
+// input: none
+const healthCheckerT = `// Checker checks that a dependency of the server is ready to serve requests.
+type Checker interface {
+	// Check returns an error if the dependency is not ready.
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc is an adapter that allows the use of ordinary functions as
+// checkers.
+type CheckerFunc func(ctx context.Context) error
+
+// Check calls f(ctx).
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// HTTPChecker returns a checker that makes a GET request to the given URL
+// using doer and that fails if the request fails or if the response status
+// code is not 2xx.
+func HTTPChecker(doer goahttp.Doer, url string) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		req, err := http.NewRequest("GET", url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := doer.Do(req.WithContext(ctx))
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("GET %s: %s", url, resp.Status)
+		}
+		return nil
+	})
+}
+`
+
+// input: service.Data
+const healthServiceT = `// Service implements the {{ .Name }} service. Liveness always succeeds while
+// readiness succeeds only if all the registered checkers do.
+type Service struct {
+	mu       sync.RWMutex
+	names    []string
+	checkers map[string]Checker
+}
+
+// Service implements the service interface.
+var _ {{ .PkgName }}.Service = (*Service)(nil)
+
+// New returns a {{ .Name }} service with no checker.
+func New() *Service {
+	return &Service{checkers: make(map[string]Checker)}
+}
+
+// Register registers a readiness checker under the given name. It replaces the
+// checker previously registered under the same name if any.
+func (s *Service) Register(name string, c Checker) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.checkers[name]; !ok {
+		s.names = append(s.names, name)
+	}
+	s.checkers[name] = c
+}
+
+// Liveness reports that the server is running.
+func (s *Service) Liveness(ctx context.Context) (*{{ .PkgName }}.HealthStatus, error) {
+	return &{{ .PkgName }}.HealthStatus{Status: "ok"}, nil
+}
+
+// Readiness runs the registered checkers concurrently. It returns a not_ready
+// error listing the checkers that failed if any.
+func (s *Service) Readiness(ctx context.Context) (*{{ .PkgName }}.HealthStatus, error) {
+	s.mu.RLock()
+	names := make([]string, len(s.names))
+	checkers := make([]Checker, len(s.names))
+	for i, name := range s.names {
+		names[i] = name
+		checkers[i] = s.checkers[name]
+	}
+	s.mu.RUnlock()
+
+	errs := make([]error, len(checkers))
+	var wg sync.WaitGroup
+	for i, c := range checkers {
+		wg.Add(1)
+		go func(i int, c Checker) {
+			defer wg.Done()
+			errs[i] = c.Check(ctx)
+		}(i, c)
+	}
+	wg.Wait()
+
+	res := &{{ .PkgName }}.HealthStatus{Status: "ok", Checks: make(map[string]string, len(names))}
+	var failed []string
+	for i, name := range names {
+		if errs[i] != nil {
+			res.Checks[name] = errs[i].Error()
+			failed = append(failed, fmt.Sprintf("%s: %s", name, errs[i]))
+			continue
+		}
+		res.Checks[name] = "ok"
+	}
+	if len(failed) > 0 {
+		return nil, {{ .PkgName }}.MakeNotReady(errors.New(strings.Join(failed, "; ")))
+	}
+	return res, nil
+}
+`
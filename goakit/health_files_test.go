@@ -0,0 +1,41 @@
+package goakit
+
+import (
+	"testing"
+
+	"goa.design/goa/expr"
+	"goa.design/plugins/goakit/testdata"
+)
+
+func TestHealthFiles(t *testing.T) {
+	runDSL(t, testdata.HealthDSL)
+	if len(expr.Root.API.HTTP.Services) != 2 {
+		t.Fatalf("got %d HTTP services, expected 2", len(expr.Root.API.HTTP.Services))
+	}
+	h := expr.Root.API.HTTP.Service("health")
+	if h == nil {
+		t.Fatal("health service not found")
+	}
+	paths := map[string]string{"liveness": "/livez", "readiness": "/readyz"}
+	for m, p := range paths {
+		e := h.Endpoint(m)
+		if e == nil {
+			t.Fatalf("health %s endpoint not found", m)
+		}
+		if len(e.Routes) != 1 || e.Routes[0].Path != p || e.Routes[0].Method != "GET" {
+			t.Errorf("health %s: invalid routes, expected GET %s", m, p)
+		}
+	}
+	fs := HealthFiles("", expr.Root)
+	if len(fs) != 1 {
+		t.Fatalf("got %d files, expected 1", len(fs))
+	}
+	testCode(t, fs[0], "goakit-health-service", []string{testdata.HealthServiceCode})
+}
+
+func TestHealthFilesDisabled(t *testing.T) {
+	runDSL(t, testdata.SimpleServiceDSL)
+	if fs := HealthFiles("", expr.Root); len(fs) != 0 {
+		t.Errorf("got %d files, expected none", len(fs))
+	}
+}
@@ -0,0 +1,203 @@
+package goakit
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+
+	"goa.design/goa/codegen"
+	goaexpr "goa.design/goa/expr"
+	httpcodegen "goa.design/goa/http/codegen"
+	"goa.design/plugins/goakit/expr"
+)
+
+// jsonrpcData contains the data needed to render the JSON-RPC templates of an
+// endpoint.
+type jsonrpcData struct {
+	*httpcodegen.EndpointData
+	// ValidatePayload is true if the decoded payload must be validated.
+	ValidatePayload bool
+	// PayloadPointer is true if the payload type is a pointer.
+	PayloadPointer bool
+}
+
+// JSONRPCFiles produces the files defining the go-kit JSON-RPC server and
+// clients for the services served over JSON-RPC.
+func JSONRPCFiles(genpkg string, root *goaexpr.RootExpr) []*codegen.File {
+	var fw []*codegen.File
+	for _, svc := range root.API.HTTP.Services {
+		if !expr.JSONRPC(svc.Name()) {
+			continue
+		}
+		fw = append(fw, serverJSONRPC(genpkg, svc), clientJSONRPC(genpkg, svc))
+	}
+	return fw
+}
+
+// jsonrpcEndpoints returns the data of the given service endpoints that can be
+// served over JSON-RPC, that is all the non-streaming endpoints.
+func jsonrpcEndpoints(svc *goaexpr.HTTPServiceExpr) []*jsonrpcData {
+	data := httpcodegen.HTTPServices.Get(svc.Name())
+	var eps []*jsonrpcData
+	for _, e := range data.Endpoints {
+		if isStreaming(e) {
+			continue
+		}
+		jd := &jsonrpcData{EndpointData: e}
+		jd.ValidatePayload, jd.PayloadPointer = payloadValidation(svc, e)
+		eps = append(eps, jd)
+	}
+	return eps
+}
+
+// serverJSONRPC returns the file defining the go-kit JSON-RPC server.
+func serverJSONRPC(genpkg string, svc *goaexpr.HTTPServiceExpr) *codegen.File {
+	fpath := filepath.Join(codegen.Gendir, "jsonrpc", codegen.SnakeCase(svc.Name()), "kitserver", "server.go")
+	data := httpcodegen.HTTPServices.Get(svc.Name())
+	eps := jsonrpcEndpoints(svc)
+	title := fmt.Sprintf("%s go-kit JSON-RPC server", svc.Name())
+	sections := []*codegen.SectionTemplate{
+		codegen.Header(title, "server", []*codegen.ImportSpec{
+			{Path: "context"},
+			{Path: "encoding/json"},
+			{Path: "github.com/go-kit/kit/transport/http/jsonrpc"},
+			{Path: path.Join(genpkg, codegen.SnakeCase(svc.Name())), Name: data.Service.PkgName},
+			{Path: path.Join(genpkg, codegen.SnakeCase(svc.Name()), "kitendpoint"), Name: "kitendpoint"},
+		}),
+		{
+			Name:   "goakit-jsonrpc-server",
+			Source: jsonrpcServerT,
+			Data: map[string]interface{}{
+				"Service":   data.Service,
+				"Endpoints": eps,
+			},
+		},
+	}
+	for _, e := range eps {
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "goakit-jsonrpc-server-codec",
+			Source: jsonrpcServerCodecT,
+			Data:   e,
+		})
+	}
+
+	return &codegen.File{Path: fpath, SectionTemplates: sections}
+}
+
+// clientJSONRPC returns the file defining the go-kit JSON-RPC clients.
+func clientJSONRPC(genpkg string, svc *goaexpr.HTTPServiceExpr) *codegen.File {
+	fpath := filepath.Join(codegen.Gendir, "jsonrpc", codegen.SnakeCase(svc.Name()), "kitclient", "client.go")
+	data := httpcodegen.HTTPServices.Get(svc.Name())
+	eps := jsonrpcEndpoints(svc)
+	title := fmt.Sprintf("%s go-kit JSON-RPC client", svc.Name())
+	specs := []*codegen.ImportSpec{
+		{Path: "context"},
+		{Path: "encoding/json"},
+		{Path: "net/url"},
+		{Path: "github.com/go-kit/kit/endpoint"},
+		{Path: "github.com/go-kit/kit/transport/http/jsonrpc"},
+		{Path: path.Join(genpkg, codegen.SnakeCase(svc.Name())), Name: data.Service.PkgName},
+	}
+	for _, e := range eps {
+		if e.Method.ViewedResult != nil {
+			specs = append(specs, &codegen.ImportSpec{
+				Path: path.Join(genpkg, codegen.SnakeCase(svc.Name()), "views"),
+				Name: data.Service.ViewsPkg,
+			})
+			break
+		}
+	}
+	sections := []*codegen.SectionTemplate{codegen.Header(title, "client", specs)}
+	for _, e := range eps {
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "goakit-jsonrpc-client",
+			Source: jsonrpcClientT,
+			Data:   e,
+		})
+	}
+
+	return &codegen.File{Path: fpath, SectionTemplates: sections}
+}
+
+// input: map[string]interface{}{"Service": ServiceData, "Endpoints": []jsonrpcData}
+const jsonrpcServerT = `{{ printf "NewServer returns a go-kit JSON-RPC server that serves the %s service methods using the given endpoints." .Service.Name | comment }}
+func NewServer(endpoints *{{ .Service.PkgName }}.Endpoints, options ...jsonrpc.ServerOption) *jsonrpc.Server {
+	return jsonrpc.NewServer(EndpointCodecMap(endpoints), options...)
+}
+
+{{ printf "EndpointCodecMap returns the go-kit JSON-RPC endpoint codecs of the %s service methods indexed by JSON-RPC method name." .Service.Name | comment }}
+func EndpointCodecMap(endpoints *{{ .Service.PkgName }}.Endpoints) jsonrpc.EndpointCodecMap {
+	return jsonrpc.EndpointCodecMap{
+	{{- range .Endpoints }}
+		{{ printf "%q" .Method.Name }}: jsonrpc.EndpointCodec{
+			Endpoint: endpoints.{{ .Method.VarName }},
+			Decode:   Decode{{ .Method.VarName }}Request,
+			Encode:   Encode{{ .Method.VarName }}Response,
+		},
+	{{- end }}
+	}
+}
+`
+
+// input: jsonrpcData
+const jsonrpcServerCodecT = `{{ printf "Decode%sRequest is a go-kit JSON-RPC DecodeRequestFunc that decodes %s %s payloads." .Method.VarName .ServiceName .Method.Name | comment }}
+func Decode{{ .Method.VarName }}Request(_ context.Context, msg json.RawMessage) (interface{}, error) {
+{{- if .Payload.Ref }}
+	var payload {{ .Payload.Ref }}
+	if err := json.Unmarshal(msg, &payload); err != nil {
+		return nil, err
+	}
+{{- if .ValidatePayload }}
+	{{- if .PayloadPointer }}
+	if payload != nil {
+		if err := kitendpoint.Validate{{ .Method.VarName }}Payload(payload); err != nil {
+			return nil, err
+		}
+	}
+	{{- else }}
+	if err := kitendpoint.Validate{{ .Method.VarName }}Payload(payload); err != nil {
+		return nil, err
+	}
+	{{- end }}
+{{- end }}
+	return payload, nil
+{{- else }}
+	return nil, nil
+{{- end }}
+}
+
+{{ printf "Encode%sResponse is a go-kit JSON-RPC EncodeResponseFunc that encodes %s %s results." .Method.VarName .ServiceName .Method.Name | comment }}
+func Encode{{ .Method.VarName }}Response(_ context.Context, res interface{}) (json.RawMessage, error) {
+	return json.Marshal(res)
+}
+`
+
+// input: jsonrpcData
+const jsonrpcClientT = `{{ printf "Decode%sResponse is a go-kit JSON-RPC DecodeResponseFunc that decodes %s %s results." .Method.VarName .ServiceName .Method.Name | comment }}
+func Decode{{ .Method.VarName }}Response(_ context.Context, resp jsonrpc.Response) (interface{}, error) {
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+{{- if .Method.ViewedResult }}
+	var vres {{ .Method.ViewedResult.FullRef }}
+	if err := json.Unmarshal(resp.Result, &vres); err != nil {
+		return nil, err
+	}
+	return {{ .ServicePkgName }}.{{ .Method.ViewedResult.ResultInit.Name }}(vres), nil
+{{- else if .Method.Result }}
+	var res {{ .Result.Ref }}
+	if err := json.Unmarshal(resp.Result, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+{{- else }}
+	return nil, nil
+{{- end }}
+}
+
+{{ printf "New%sEndpoint returns an endpoint that makes JSON-RPC requests to the %s %s method served at the given URL." .Method.VarName .ServiceName .Method.Name | comment }}
+func New{{ .Method.VarName }}Endpoint(tgt *url.URL, options ...jsonrpc.ClientOption) endpoint.Endpoint {
+	options = append([]jsonrpc.ClientOption{jsonrpc.ClientResponseDecoder(Decode{{ .Method.VarName }}Response)}, options...)
+	return jsonrpc.NewClient(tgt, {{ printf "%q" .Method.Name }}, options...).Endpoint()
+}
+`
@@ -0,0 +1,52 @@
+package goakit
+
+import (
+	"strings"
+	"testing"
+
+	"goa.design/goa/expr"
+	"goa.design/plugins/goakit/testdata"
+)
+
+func TestJSONRPCFiles(t *testing.T) {
+	cases := map[string]struct {
+		DSL        func()
+		ServerCode map[string][]string
+		ClientCode map[string][]string
+	}{
+		"jsonrpc": {
+			DSL: testdata.JSONRPCDSL,
+			ServerCode: map[string][]string{
+				"goakit-jsonrpc-server":       []string{testdata.JSONRPCServerCode},
+				"goakit-jsonrpc-server-codec": []string{testdata.JSONRPCMethodServerCodecCode},
+			},
+			ClientCode: map[string][]string{
+				"goakit-jsonrpc-client": []string{testdata.JSONRPCMethodClientCode},
+			},
+		},
+		"validation": {
+			DSL: testdata.JSONRPCValidationDSL,
+			ServerCode: map[string][]string{
+				"goakit-jsonrpc-server-codec": []string{testdata.JSONRPCValidationMethodServerCodecCode},
+			},
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			runDSL(t, c.DSL)
+			fs := JSONRPCFiles("", expr.Root)
+			if len(fs) != 2 {
+				t.Fatalf("got %d files, expected 2", len(fs))
+			}
+			for _, f := range fs {
+				code := c.ClientCode
+				if strings.Contains(f.Path, "kitserver") {
+					code = c.ServerCode
+				}
+				for sec, secCode := range code {
+					testCode(t, f, sec, secCode)
+				}
+			}
+		})
+	}
+}
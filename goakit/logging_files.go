@@ -0,0 +1,76 @@
+package goakit
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"goa.design/goa/codegen"
+	"goa.design/goa/expr"
+	httpcodegen "goa.design/goa/http/codegen"
+)
+
+// LoggingFiles produces the files defining the go-kit endpoint middlewares that
+// log the requests made to the service endpoints.
+func LoggingFiles(root *expr.RootExpr) []*codegen.File {
+	var fw []*codegen.File
+	for _, svc := range root.API.HTTP.Services {
+		if f := serverLogging(svc); f != nil {
+			fw = append(fw, f)
+		}
+	}
+	return fw
+}
+
+// serverLogging returns the file defining the go-kit endpoint logging
+// middlewares of the given service. It returns nil if the service has no
+// endpoint served by a go-kit HTTP server.
+func serverLogging(svc *expr.HTTPServiceExpr) *codegen.File {
+	data := httpcodegen.HTTPServices.Get(svc.Name())
+	var endpoints []*httpcodegen.EndpointData
+	for _, e := range data.Endpoints {
+		if !isStreaming(e) {
+			endpoints = append(endpoints, e)
+		}
+	}
+	if len(endpoints) == 0 {
+		return nil
+	}
+	path := filepath.Join(codegen.Gendir, "http", codegen.SnakeCase(svc.Name()), "kitserver", "logging.go")
+	title := fmt.Sprintf("%s go-kit endpoint logging", svc.Name())
+	sections := []*codegen.SectionTemplate{
+		codegen.Header(title, "server", []*codegen.ImportSpec{
+			{Path: "context"},
+			{Path: "time"},
+			{Path: "github.com/go-kit/kit/endpoint"},
+			{Path: "github.com/go-kit/kit/log"},
+			{Path: "github.com/go-kit/kit/log/level"},
+		}),
+	}
+	for _, e := range endpoints {
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "goakit-endpoint-logging",
+			Source: endpointLoggingT,
+			Data:   e,
+		})
+	}
+
+	return &codegen.File{Path: path, SectionTemplates: sections}
+}
+
+// input: EndpointData
+const endpointLoggingT = `{{ printf "Log%sEndpoint returns an endpoint middleware that logs the %s %s requests. The log entries contain the method name, the time it took to process the request and the error if any. Failed requests are logged at the error level, successful requests at the info level." .Method.VarName .ServiceName .Method.Name | comment }}
+func Log{{ .Method.VarName }}Endpoint(logger log.Logger) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+			defer func(begin time.Time) {
+				l := level.Info(logger)
+				if err != nil {
+					l = level.Error(logger)
+				}
+				l.Log("method", {{ printf "%s.%s" .ServiceName .Method.Name | printf "%q" }}, "took", time.Since(begin), "err", err)
+			}(time.Now())
+			return next(ctx, request)
+		}
+	}
+}
+`
@@ -0,0 +1,35 @@
+package goakit
+
+import (
+	"testing"
+
+	"goa.design/goa/expr"
+	"goa.design/plugins/goakit/testdata"
+)
+
+func TestLoggingFiles(t *testing.T) {
+	cases := map[string]struct {
+		DSL  func()
+		Code []string
+	}{
+		"simple":    {testdata.SimpleServiceDSL, []string{testdata.SimpleMethodEndpointLoggingCode}},
+		"streaming": {testdata.StreamingDSL, []string{testdata.NonStreamingMethodEndpointLoggingCode}},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			runDSL(t, c.DSL)
+			fs := LoggingFiles(expr.Root)
+			if len(fs) != 1 {
+				t.Fatalf("got %d files, expected 1", len(fs))
+			}
+			testCode(t, fs[0], "goakit-endpoint-logging", c.Code)
+		})
+	}
+}
+
+func TestLoggingFilesNoEndpoint(t *testing.T) {
+	runDSL(t, testdata.FileServerDSL)
+	if fs := LoggingFiles(expr.Root); len(fs) != 0 {
+		t.Errorf("got %d files, expected none", len(fs))
+	}
+}
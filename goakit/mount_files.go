@@ -33,6 +33,10 @@ func mountFile(svc *expr.HTTPServiceExpr) *codegen.File {
 		}),
 	}
 	for _, e := range data.Endpoints {
+		if isStreaming(e) {
+			// Streaming endpoints are mounted by the goa server package.
+			continue
+		}
 		sections = append(sections, &codegen.SectionTemplate{
 			Name:   "goakit-mount-handler",
 			Source: mountHandlerT,
@@ -4,7 +4,6 @@ import (
 	"testing"
 
 	"goa.design/goa/expr"
-	httpcodegen "goa.design/goa/http/codegen"
 	"goa.design/plugins/goakit/testdata"
 )
 
@@ -34,10 +33,17 @@ func TestMountFiles(t *testing.T) {
 				"goakit-mount-file-server": []string{testdata.MixedFileGoakitMountCode},
 			},
 		},
+		"streaming": {
+			DSL: testdata.StreamingDSL,
+			Code: map[string][]string{
+				"goakit-mount-handler":     []string{testdata.NonStreamingMethodGoakitMountCode},
+				"goakit-mount-file-server": []string{},
+			},
+		},
 	}
 	for name, c := range cases {
 		t.Run(name, func(t *testing.T) {
-			httpcodegen.RunHTTPDSL(t, c.DSL)
+			runDSL(t, c.DSL)
 			fs := MountFiles(expr.Root)
 			if len(fs) != 1 {
 				t.Fatalf("got %d files, expected 1", len(fs))
@@ -0,0 +1,379 @@
+package goakit
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+
+	"goa.design/goa/codegen"
+	goaexpr "goa.design/goa/expr"
+	httpcodegen "goa.design/goa/http/codegen"
+	"goa.design/plugins/goakit/expr"
+)
+
+// natsData contains the data needed to render the NATS templates of an
+// endpoint.
+type natsData struct {
+	*httpcodegen.EndpointData
+	// Subject is the NATS subject the endpoint is served on.
+	Subject string
+	// Queue is the name of the NATS queue group the service subscribers
+	// join.
+	Queue string
+	// ValidatePayload is true if the decoded payload must be validated.
+	ValidatePayload bool
+	// PayloadPointer is true if the payload type is a pointer.
+	PayloadPointer bool
+	// Request is the body used to encode the payloads, nil if the
+	// payloads are encoded as is.
+	Request *bodyData
+	// Response is the body used to encode the results, nil if the results
+	// are encoded as is.
+	Response *bodyData
+}
+
+// NATSFiles produces the files defining the go-kit NATS subscribers and
+// publishers for the services served over NATS.
+func NATSFiles(genpkg string, root *goaexpr.RootExpr) []*codegen.File {
+	var fw []*codegen.File
+	for _, svc := range root.API.HTTP.Services {
+		eps := natsEndpoints(svc)
+		if len(eps) == 0 {
+			continue
+		}
+		fw = append(fw, serverNATS(genpkg, svc, eps), clientNATS(genpkg, svc, eps))
+	}
+	return fw
+}
+
+// natsEndpoints returns the data of the given service endpoints served over
+// NATS. Streaming endpoints are not supported.
+func natsEndpoints(svc *goaexpr.HTTPServiceExpr) []*natsData {
+	data := httpcodegen.HTTPServices.Get(svc.Name())
+	var eps []*natsData
+	for _, e := range data.Endpoints {
+		if isStreaming(e) {
+			continue
+		}
+		subject := expr.NATSSubject(svc.Name(), e.Method.Name)
+		if subject == "" {
+			continue
+		}
+		nd := &natsData{EndpointData: e, Subject: subject, Queue: svc.Name()}
+		nd.ValidatePayload, nd.PayloadPointer = payloadValidation(svc, e)
+		eps = append(eps, nd)
+	}
+	return eps
+}
+
+// serverNATS returns the file defining the go-kit NATS subscribers.
+func serverNATS(genpkg string, svc *goaexpr.HTTPServiceExpr, eps []*natsData) *codegen.File {
+	fpath := filepath.Join(codegen.Gendir, "nats", codegen.SnakeCase(svc.Name()), "kitserver", "subscribers.go")
+	data := httpcodegen.HTTPServices.Get(svc.Name())
+	title := fmt.Sprintf("%s go-kit NATS subscribers", svc.Name())
+	specs := []*codegen.ImportSpec{
+		{Path: "context"},
+		{Path: "encoding/json"},
+		{Path: "unicode/utf8"},
+		{Path: "github.com/go-kit/kit/endpoint"},
+		{Path: "github.com/go-kit/kit/transport/nats", Name: "kitnats"},
+		{Path: "github.com/nats-io/nats.go", Name: "nats"},
+		{Path: "goa.design/goa", Name: "goa"},
+		{Path: path.Join(genpkg, codegen.SnakeCase(svc.Name())), Name: data.Service.PkgName},
+		{Path: path.Join(genpkg, codegen.SnakeCase(svc.Name()), "kitendpoint"), Name: "kitendpoint"},
+	}
+	if hasViewedResult(eps) {
+		specs = append(specs, &codegen.ImportSpec{
+			Path: path.Join(genpkg, codegen.SnakeCase(svc.Name()), "views"),
+			Name: data.Service.ViewsPkg,
+		})
+	}
+	bb := newBodyBuilder(data.Service.PkgName, data.Service.Scope)
+	var subs []*codegen.SectionTemplate
+	for _, e := range eps {
+		ed := *e
+		if m := svc.ServiceExpr.Method(e.Method.Name); m != nil {
+			ed.Request = bb.Decoder(m.Payload, e.Method.VarName+"RequestBody", natsDesc(e, "payloads"), "New"+e.Method.VarName+"Payload", e.Payload.Ref)
+			ed.Response = bb.Encoder(m.Result, e.Method.VarName+"ResponseBody", natsDesc(e, "results"), "New"+e.Method.VarName+"ResponseBody", e.Result.Ref)
+		}
+		subs = append(subs, &codegen.SectionTemplate{
+			Name:   "goakit-nats-subscriber",
+			Source: natsSubscriberT,
+			Data:   &ed,
+		})
+	}
+	sections := []*codegen.SectionTemplate{
+		codegen.Header(title, "server", specs),
+		{Name: "goakit-nats-error-encoder", Source: natsErrorEncoderT},
+	}
+	if len(bb.Types) > 0 {
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "goakit-nats-body-types",
+			Source: bodyTypesT,
+			Data:   bb,
+		})
+	}
+	sections = append(sections, subs...)
+	sections = append(sections, &codegen.SectionTemplate{
+		Name:   "goakit-nats-subscribe",
+		Source: natsSubscribeT,
+		Data: map[string]interface{}{
+			"Service":   data.Service,
+			"Endpoints": eps,
+		},
+	})
+
+	return &codegen.File{Path: fpath, SectionTemplates: sections}
+}
+
+// clientNATS returns the file defining the go-kit NATS publishers.
+func clientNATS(genpkg string, svc *goaexpr.HTTPServiceExpr, eps []*natsData) *codegen.File {
+	fpath := filepath.Join(codegen.Gendir, "nats", codegen.SnakeCase(svc.Name()), "kitclient", "publishers.go")
+	data := httpcodegen.HTTPServices.Get(svc.Name())
+	title := fmt.Sprintf("%s go-kit NATS publishers", svc.Name())
+	specs := []*codegen.ImportSpec{
+		{Path: "context"},
+		{Path: "encoding/json"},
+		{Path: "errors"},
+		{Path: "unicode/utf8"},
+		{Path: "github.com/go-kit/kit/transport/nats", Name: "kitnats"},
+		{Path: "github.com/nats-io/nats.go", Name: "nats"},
+		{Path: "goa.design/goa", Name: "goa"},
+		{Path: path.Join(genpkg, codegen.SnakeCase(svc.Name())), Name: data.Service.PkgName},
+	}
+	bb := newBodyBuilder(data.Service.PkgName, data.Service.Scope)
+	var pubs []*codegen.SectionTemplate
+	for _, e := range eps {
+		ed := *e
+		if m := svc.ServiceExpr.Method(e.Method.Name); m != nil {
+			ed.Request = bb.Encoder(m.Payload, e.Method.VarName+"RequestBody", natsDesc(e, "payloads"), "New"+e.Method.VarName+"RequestBody", e.Payload.Ref)
+			ed.Response = bb.Decoder(m.Result, e.Method.VarName+"ResponseBody", natsDesc(e, "results"), "New"+e.Method.VarName+"Result", e.Result.Ref)
+		}
+		pubs = append(pubs, &codegen.SectionTemplate{
+			Name:   "goakit-nats-publisher",
+			Source: natsPublisherT,
+			Data:   &ed,
+		})
+	}
+	sections := []*codegen.SectionTemplate{
+		codegen.Header(title, "client", specs),
+		{Name: "goakit-nats-error-decoder", Source: natsErrorDecoderT},
+	}
+	if len(bb.Types) > 0 {
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "goakit-nats-body-types",
+			Source: bodyTypesT,
+			Data:   bb,
+		})
+	}
+	sections = append(sections, pubs...)
+
+	return &codegen.File{Path: fpath, SectionTemplates: sections}
+}
+
+// hasViewedResult returns true if one of the given endpoints returns a viewed
+// result.
+func hasViewedResult(eps []*natsData) bool {
+	for _, e := range eps {
+		if e.Method.ViewedResult != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// natsDesc describes the values of the given kind (e.g. "payloads") of the
+// given endpoint.
+func natsDesc(e *natsData, kind string) string {
+	return fmt.Sprintf("%s %s %s", e.ServiceName, e.Method.Name, kind)
+}
+
+// input: none
+const natsErrorEncoderT = `// errorReply is the JSON representation of the errors returned by the
+// subscribers. The "goakit_error" key distinguishes the errors from the
+// results, the "err" key is the one used by the go-kit default error encoder
+// and the other keys describe the goa service errors.
+type errorReply struct {
+	IsError   bool   ` + "`" + `json:"goakit_error"` + "`" + `
+	Error     string ` + "`" + `json:"err"` + "`" + `
+	Name      string ` + "`" + `json:"name,omitempty"` + "`" + `
+	ID        string ` + "`" + `json:"id,omitempty"` + "`" + `
+	Temporary bool   ` + "`" + `json:"temporary,omitempty"` + "`" + `
+	Timeout   bool   ` + "`" + `json:"timeout,omitempty"` + "`" + `
+	Fault     bool   ` + "`" + `json:"fault,omitempty"` + "`" + `
+}
+
+// EncodeError is a go-kit NATS ErrorEncoder that publishes the errors returned
+// by the endpoints to the reply subject using JSON. It keeps the name and
+// flags of the goa service errors so that the publishers can rebuild them.
+func EncodeError(_ context.Context, err error, reply string, nc *nats.Conn) {
+	e := errorReply{IsError: true, Error: err.Error()}
+	if serr, ok := err.(*goa.ServiceError); ok {
+		e.Error = serr.Message
+		e.Name = serr.Name
+		e.ID = serr.ID
+		e.Temporary = serr.Temporary
+		e.Timeout = serr.Timeout
+		e.Fault = serr.Fault
+	}
+	b, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	nc.Publish(reply, b)
+}
+`
+
+// input: none
+const natsErrorDecoderT = `// errorReply is the JSON representation of the errors published by the
+// service subscribers, see the server EncodeError function.
+type errorReply struct {
+	IsError   bool   ` + "`" + `json:"goakit_error"` + "`" + `
+	Error     string ` + "`" + `json:"err"` + "`" + `
+	Name      string ` + "`" + `json:"name"` + "`" + `
+	ID        string ` + "`" + `json:"id"` + "`" + `
+	Temporary bool   ` + "`" + `json:"temporary"` + "`" + `
+	Timeout   bool   ` + "`" + `json:"timeout"` + "`" + `
+	Fault     bool   ` + "`" + `json:"fault"` + "`" + `
+}
+
+// decodeError returns the error held by the given reply, nil if the reply is
+// not an error. The replies are errors if they set the "goakit_error" key so
+// that errors with an empty message are not mistaken for results. The errors
+// that carry a name are decoded into goa service errors.
+func decodeError(data []byte) error {
+	var e errorReply
+	if err := json.Unmarshal(data, &e); err != nil || !e.IsError {
+		return nil
+	}
+	if e.Name == "" {
+		return errors.New(e.Error)
+	}
+	return &goa.ServiceError{
+		Name:      e.Name,
+		ID:        e.ID,
+		Message:   e.Error,
+		Temporary: e.Temporary,
+		Timeout:   e.Timeout,
+		Fault:     e.Fault,
+	}
+}
+`
+
+// input: natsData
+const natsSubscriberT = `{{ printf "Decode%sRequest is a go-kit NATS DecodeRequestFunc that decodes JSON encoded %s %s payloads." .Method.VarName .ServiceName .Method.Name | comment }}
+func Decode{{ .Method.VarName }}Request(_ context.Context, msg *nats.Msg) (interface{}, error) {
+{{- if .Request }}
+	var body {{ .Request.Name }}
+	if err := json.Unmarshal(msg.Data, &body); err != nil {
+		return nil, err
+	}
+	{{- if .Request.Validate }}
+	if err := Validate{{ .Request.Name }}({{ if .Request.Pointer }}&{{ end }}body); err != nil {
+		return nil, err
+	}
+	{{- end }}
+	return {{ .Request.Init }}({{ if .Request.Pointer }}&{{ end }}body), nil
+{{- else if .Payload.Ref }}
+	var payload {{ .Payload.Ref }}
+	if err := json.Unmarshal(msg.Data, &payload); err != nil {
+		return nil, err
+	}
+{{- if .ValidatePayload }}
+	{{- if .PayloadPointer }}
+	if payload != nil {
+		if err := kitendpoint.Validate{{ .Method.VarName }}Payload(payload); err != nil {
+			return nil, err
+		}
+	}
+	{{- else }}
+	if err := kitendpoint.Validate{{ .Method.VarName }}Payload(payload); err != nil {
+		return nil, err
+	}
+	{{- end }}
+{{- end }}
+	return payload, nil
+{{- else }}
+	return nil, nil
+{{- end }}
+}
+{{- if .Response }}
+
+{{ printf "Encode%sResponse is a go-kit NATS EncodeResponseFunc that encodes %s %s results using JSON." .Method.VarName .ServiceName .Method.Name | comment }}
+func Encode{{ .Method.VarName }}Response(ctx context.Context, reply string, nc *nats.Conn, response interface{}) error {
+{{- if .Method.ViewedResult }}
+	vres := response.({{ .Method.ViewedResult.FullRef }})
+	body := {{ .Response.Init }}({{ .ServicePkgName }}.{{ .Method.ViewedResult.ResultInit.Name }}(vres))
+{{- else }}
+	body := {{ .Response.Init }}(response.({{ .Result.Ref }}))
+{{- end }}
+	return kitnats.EncodeJSONResponse(ctx, reply, nc, body)
+}
+{{- end }}
+
+{{ printf "New%sSubscriber returns a go-kit NATS subscriber that serves %s %s requests using the given endpoint. The endpoint results are encoded using JSON." .Method.VarName .ServiceName .Method.Name | comment }}
+func New{{ .Method.VarName }}Subscriber(e endpoint.Endpoint, options ...kitnats.SubscriberOption) *kitnats.Subscriber {
+	options = append([]kitnats.SubscriberOption{kitnats.SubscriberErrorEncoder(EncodeError)}, options...)
+	return kitnats.NewSubscriber(e, Decode{{ .Method.VarName }}Request, {{ if .Response }}Encode{{ .Method.VarName }}Response{{ else }}kitnats.EncodeJSONResponse{{ end }}, options...)
+}
+`
+
+// input: map[string]interface{}{"Service": ServiceData, "Endpoints": []natsData}
+const natsSubscribeT = `{{ printf "Subscribe subscribes the %s service subscribers to their NATS subjects on the given connection. The subscribers join the %q queue group so that requests are load balanced across the service instances." .Service.Name .Service.Name | comment }}
+func Subscribe(nc *nats.Conn, endpoints *{{ .Service.PkgName }}.Endpoints, options ...kitnats.SubscriberOption) ([]*nats.Subscription, error) {
+	var (
+		subs []*nats.Subscription
+		sub  *nats.Subscription
+		err  error
+	)
+{{- range .Endpoints }}
+	sub, err = nc.QueueSubscribe({{ printf "%q" .Subject }}, {{ printf "%q" .Queue }}, New{{ .Method.VarName }}Subscriber(endpoints.{{ .Method.VarName }}, options...).ServeMsg(nc))
+	if err != nil {
+		return subs, err
+	}
+	subs = append(subs, sub)
+{{- end }}
+	return subs, nil
+}
+`
+
+// input: natsData
+const natsPublisherT = `{{- if .Request -}}
+{{ printf "Encode%sRequest is a go-kit NATS EncodeRequestFunc that encodes %s %s payloads using JSON." .Method.VarName .ServiceName .Method.Name | comment }}
+func Encode{{ .Method.VarName }}Request(ctx context.Context, msg *nats.Msg, request interface{}) error {
+	return kitnats.EncodeJSONRequest(ctx, msg, {{ .Request.Init }}(request.({{ .Payload.Ref }})))
+}
+
+{{ end -}}
+{{ printf "Decode%sResponse is a go-kit NATS DecodeResponseFunc that decodes JSON encoded %s %s results." .Method.VarName .ServiceName .Method.Name | comment }}
+func Decode{{ .Method.VarName }}Response(_ context.Context, msg *nats.Msg) (interface{}, error) {
+	if err := decodeError(msg.Data); err != nil {
+		return nil, err
+	}
+{{- if .Response }}
+	var body {{ .Response.Name }}
+	if err := json.Unmarshal(msg.Data, &body); err != nil {
+		return nil, err
+	}
+	{{- if .Response.Validate }}
+	if err := Validate{{ .Response.Name }}({{ if .Response.Pointer }}&{{ end }}body); err != nil {
+		return nil, err
+	}
+	{{- end }}
+	return {{ .Response.Init }}({{ if .Response.Pointer }}&{{ end }}body), nil
+{{- else if .Method.Result }}
+	var res {{ .Result.Ref }}
+	if err := json.Unmarshal(msg.Data, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+{{- else }}
+	return nil, nil
+{{- end }}
+}
+
+{{ printf "New%sPublisher returns a go-kit NATS publisher that makes %s %s requests on the given connection. The publisher encodes the payloads using JSON." .Method.VarName .ServiceName .Method.Name | comment }}
+func New{{ .Method.VarName }}Publisher(nc *nats.Conn, options ...kitnats.PublisherOption) *kitnats.Publisher {
+	return kitnats.NewPublisher(nc, {{ printf "%q" .Subject }}, {{ if .Request }}Encode{{ .Method.VarName }}Request{{ else }}kitnats.EncodeJSONRequest{{ end }}, Decode{{ .Method.VarName }}Response, options...)
+}
+`
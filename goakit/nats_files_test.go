@@ -0,0 +1,64 @@
+package goakit
+
+import (
+	"strings"
+	"testing"
+
+	"goa.design/goa/expr"
+	"goa.design/plugins/goakit/testdata"
+)
+
+func TestNATSFiles(t *testing.T) {
+	cases := map[string]struct {
+		DSL        func()
+		ServerCode map[string][]string
+		ClientCode map[string][]string
+	}{
+		"nats": {
+			DSL: testdata.NATSDSL,
+			ServerCode: map[string][]string{
+				"goakit-nats-error-encoder": []string{testdata.NATSErrorEncoderCode},
+				"goakit-nats-body-types":    []string{testdata.NATSServerBodyTypesCode},
+				"goakit-nats-subscriber":    []string{testdata.NATSMethodSubscriberCode, testdata.NATSOverrideMethodSubscriberCode},
+				"goakit-nats-subscribe":     []string{testdata.NATSSubscribeCode},
+			},
+			ClientCode: map[string][]string{
+				"goakit-nats-error-decoder": []string{testdata.NATSErrorDecoderCode},
+				"goakit-nats-body-types":    []string{testdata.NATSClientBodyTypesCode},
+				"goakit-nats-publisher":     []string{testdata.NATSMethodPublisherCode, testdata.NATSOverrideMethodPublisherCode},
+			},
+		},
+		"validation": {
+			DSL: testdata.NATSValidationDSL,
+			ServerCode: map[string][]string{
+				"goakit-nats-body-types": []string{testdata.NATSValidationServerBodyTypesCode},
+				"goakit-nats-subscriber": []string{testdata.NATSValidationMethodSubscriberCode},
+			},
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			runDSL(t, c.DSL)
+			fs := NATSFiles("", expr.Root)
+			if len(fs) != 2 {
+				t.Fatalf("got %d files, expected 2", len(fs))
+			}
+			for _, f := range fs {
+				code := c.ClientCode
+				if strings.Contains(f.Path, "kitserver") {
+					code = c.ServerCode
+				}
+				for sec, secCode := range code {
+					testCode(t, f, sec, secCode)
+				}
+			}
+		})
+	}
+}
+
+func TestNATSFilesDisabled(t *testing.T) {
+	runDSL(t, testdata.SimpleServiceDSL)
+	if fs := NATSFiles("", expr.Root); len(fs) != 0 {
+		t.Errorf("got %d files, expected none", len(fs))
+	}
+}
@@ -0,0 +1,109 @@
+package goakit
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"goa.design/goa/codegen"
+	"goa.design/goa/expr"
+	httpcodegen "goa.design/goa/http/codegen"
+)
+
+// OptionsFiles produces the files defining the default go-kit HTTP server and
+// client options of each service.
+func OptionsFiles(root *expr.RootExpr) []*codegen.File {
+	fw := make([]*codegen.File, 2*len(root.API.HTTP.Services))
+	for i, svc := range root.API.HTTP.Services {
+		fw[i] = serverOptions(svc)
+	}
+	for i, svc := range root.API.HTTP.Services {
+		fw[i+len(root.API.HTTP.Services)] = clientOptions(svc)
+	}
+	return fw
+}
+
+// serverOptions returns the file defining the go-kit HTTP server options.
+func serverOptions(svc *expr.HTTPServiceExpr) *codegen.File {
+	path := filepath.Join(codegen.Gendir, "http", codegen.SnakeCase(svc.Name()), "kitserver", "options.go")
+	data := httpcodegen.HTTPServices.Get(svc.Name())
+	title := fmt.Sprintf("%s go-kit HTTP server options", svc.Name())
+	sections := []*codegen.SectionTemplate{
+		codegen.Header(title, "server", []*codegen.ImportSpec{
+			{Path: "context"},
+			{Path: "net/http"},
+			{Path: "github.com/go-kit/kit/transport/http", Name: "kithttp"},
+			{Path: "goa.design/goa/middleware"},
+		}),
+		{
+			Name:   "goakit-server-options",
+			Source: serverOptionsT,
+			Data:   data,
+		},
+	}
+
+	return &codegen.File{Path: path, SectionTemplates: sections}
+}
+
+// clientOptions returns the file defining the go-kit HTTP client options.
+func clientOptions(svc *expr.HTTPServiceExpr) *codegen.File {
+	path := filepath.Join(codegen.Gendir, "http", codegen.SnakeCase(svc.Name()), "kitclient", "options.go")
+	data := httpcodegen.HTTPServices.Get(svc.Name())
+	title := fmt.Sprintf("%s go-kit HTTP client options", svc.Name())
+	sections := []*codegen.SectionTemplate{
+		codegen.Header(title, "client", []*codegen.ImportSpec{
+			{Path: "context"},
+			{Path: "net/http"},
+			{Path: "github.com/go-kit/kit/transport/http", Name: "kithttp"},
+			{Path: "goa.design/goa/middleware"},
+		}),
+		{
+			Name:   "goakit-client-options",
+			Source: clientOptionsT,
+			Data:   data,
+		},
+	}
+
+	return &codegen.File{Path: path, SectionTemplates: sections}
+}
+
+// input: ServiceData
+const serverOptionsT = `{{ printf "ServerOptions returns the go-kit HTTP server options used to create the %s service handlers. The default options populate the request context with the HTTP request details (see kithttp.PopulateRequestContext) and the request ID. extra is appended to the default options." .Service.Name | comment }}
+func ServerOptions(extra ...kithttp.ServerOption) []kithttp.ServerOption {
+	return append([]kithttp.ServerOption{
+		kithttp.ServerBefore(kithttp.PopulateRequestContext),
+		kithttp.ServerBefore(RequestIDToContext),
+	}, extra...)
+}
+
+// RequestIDToContext is a go-kit RequestFunc that stores the ID read from the
+// X-Request-Id request header in the context under the goa request ID key
+// unless the context already contains one.
+func RequestIDToContext(ctx context.Context, r *http.Request) context.Context {
+	if _, ok := ctx.Value(middleware.RequestIDKey).(string); ok {
+		return ctx
+	}
+	if id := r.Header.Get("X-Request-Id"); id != "" {
+		return context.WithValue(ctx, middleware.RequestIDKey, id)
+	}
+	return ctx
+}
+`
+
+// input: ServiceData
+const clientOptionsT = `{{ printf "ClientOptions returns the go-kit HTTP client options used to make requests to the %s service. The default options propagate the request ID stored in the context. extra is appended to the default options." .Service.Name | comment }}
+func ClientOptions(extra ...kithttp.ClientOption) []kithttp.ClientOption {
+	return append([]kithttp.ClientOption{
+		kithttp.ClientBefore(RequestIDToHTTP),
+	}, extra...)
+}
+
+// RequestIDToHTTP is a go-kit RequestFunc that sets the X-Request-Id request
+// header with the request ID stored in the context under the goa request ID
+// key if any.
+func RequestIDToHTTP(ctx context.Context, r *http.Request) context.Context {
+	if id, ok := ctx.Value(middleware.RequestIDKey).(string); ok {
+		r.Header.Set("X-Request-Id", id)
+	}
+	return ctx
+}
+`
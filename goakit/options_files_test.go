@@ -0,0 +1,39 @@
+package goakit
+
+import (
+	"strings"
+	"testing"
+
+	"goa.design/goa/expr"
+	"goa.design/plugins/goakit/testdata"
+)
+
+func TestOptionsFiles(t *testing.T) {
+	cases := map[string]struct {
+		DSL        func()
+		ServerCode []string
+		ClientCode []string
+	}{
+		"simple-service": {
+			DSL:        testdata.SimpleServiceDSL,
+			ServerCode: []string{testdata.SimpleServiceServerOptionsCode},
+			ClientCode: []string{testdata.SimpleServiceClientOptionsCode},
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			runDSL(t, c.DSL)
+			fs := OptionsFiles(expr.Root)
+			if len(fs) != 2 {
+				t.Fatalf("got %d files, expected 2", len(fs))
+			}
+			for _, f := range fs {
+				if strings.Contains(f.Path, "kitserver") {
+					testCode(t, f, "goakit-server-options", c.ServerCode)
+				} else {
+					testCode(t, f, "goakit-client-options", c.ClientCode)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,266 @@
+package goakit
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"goa.design/goa/codegen"
+	"goa.design/goa/expr"
+	httpcodegen "goa.design/goa/http/codegen"
+	goakitexpr "goa.design/plugins/goakit/expr"
+)
+
+// policyData contains the data needed to render the timeout and retry
+// templates of an endpoint.
+type policyData struct {
+	*httpcodegen.EndpointData
+	// Timeout is the request timeout, zero if the requests have no
+	// deadline.
+	Timeout time.Duration
+	// RetryMax is the maximum number of retries, zero if the requests are
+	// not retried.
+	RetryMax int
+	// RetryBackoff is the duration to wait before each retry.
+	RetryBackoff time.Duration
+	// RetryErrors lists the names of the errors that are retried.
+	RetryErrors []string
+}
+
+// PolicyFiles produces the files defining the go-kit endpoint middlewares that
+// implement the timeout and retry policies defined in the design.
+func PolicyFiles(genpkg string, root *expr.RootExpr) []*codegen.File {
+	var fw []*codegen.File
+	for _, svc := range root.API.HTTP.Services {
+		if f := serverPolicy(svc); f != nil {
+			fw = append(fw, f)
+		}
+		if f := clientPolicy(svc); f != nil {
+			fw = append(fw, f)
+		}
+	}
+	return fw
+}
+
+// serverPolicy returns the file defining the server timeout endpoint
+// middlewares of the given service. It returns nil if no method served by a
+// go-kit HTTP server has a timeout.
+func serverPolicy(svc *expr.HTTPServiceExpr) *codegen.File {
+	pds := policies(svc)
+	var timeouts []*policyData
+	for _, pd := range pds {
+		if pd.Timeout > 0 {
+			timeouts = append(timeouts, pd)
+		}
+	}
+	if len(timeouts) == 0 {
+		return nil
+	}
+	path := filepath.Join(codegen.Gendir, "http", codegen.SnakeCase(svc.Name()), "kitserver", "policy.go")
+	title := fmt.Sprintf("%s go-kit server policies", svc.Name())
+	sections := []*codegen.SectionTemplate{
+		codegen.Header(title, "server", []*codegen.ImportSpec{
+			{Path: "context"},
+			{Path: "time"},
+			{Path: "github.com/go-kit/kit/endpoint"},
+		}),
+	}
+	fm := codegen.TemplateFuncs()
+	fm["durationLiteral"] = durationLiteral
+	for _, pd := range timeouts {
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:    "goakit-server-timeout",
+			Source:  serverTimeoutT,
+			Data:    pd,
+			FuncMap: fm,
+		})
+	}
+
+	return &codegen.File{Path: path, SectionTemplates: sections}
+}
+
+// clientPolicy returns the file defining the client timeout and retry
+// endpoint middlewares of the given service. It returns nil if no method has
+// a timeout or retry policy.
+func clientPolicy(svc *expr.HTTPServiceExpr) *codegen.File {
+	pds := policies(svc)
+	if len(pds) == 0 {
+		return nil
+	}
+	path := filepath.Join(codegen.Gendir, "http", codegen.SnakeCase(svc.Name()), "kitclient", "policy.go")
+	title := fmt.Sprintf("%s go-kit client policies", svc.Name())
+	sections := []*codegen.SectionTemplate{
+		codegen.Header(title, "client", []*codegen.ImportSpec{
+			{Path: "context"},
+			{Path: "time"},
+			{Path: "github.com/go-kit/kit/endpoint"},
+			{Path: "goa.design/goa", Name: "goa"},
+		}),
+	}
+	fm := codegen.TemplateFuncs()
+	fm["durationLiteral"] = durationLiteral
+	for _, pd := range pds {
+		if pd.Timeout > 0 {
+			sections = append(sections, &codegen.SectionTemplate{
+				Name:    "goakit-client-timeout",
+				Source:  clientTimeoutT,
+				Data:    pd,
+				FuncMap: fm,
+			})
+		}
+		if pd.RetryMax > 0 {
+			sections = append(sections, &codegen.SectionTemplate{
+				Name:    "goakit-client-retry",
+				Source:  clientRetryT,
+				Data:    pd,
+				FuncMap: fm,
+			})
+		}
+	}
+
+	return &codegen.File{Path: path, SectionTemplates: sections}
+}
+
+// policies returns the policy data of the endpoints of the given service that
+// define a timeout or retry policy. Streaming endpoints are not served by
+// go-kit and are skipped.
+func policies(svc *expr.HTTPServiceExpr) []*policyData {
+	data := httpcodegen.HTTPServices.Get(svc.Name())
+	var pds []*policyData
+	for _, e := range data.Endpoints {
+		if isStreaming(e) {
+			continue
+		}
+		if pd := policy(e); pd.Timeout > 0 || pd.RetryMax > 0 {
+			pds = append(pds, pd)
+		}
+	}
+	return pds
+}
+
+// policy returns the policy data of the given endpoint. The timeout and retry
+// fields are zero if the design defines no policy for the endpoint method.
+func policy(e *httpcodegen.EndpointData) *policyData {
+	pd := &policyData{EndpointData: e}
+	m := goakitexpr.Method(e.ServiceName, e.Method.Name)
+	if m == nil {
+		return pd
+	}
+	pd.Timeout = m.Timeout
+	pd.RetryMax = m.RetryMax
+	pd.RetryBackoff = m.RetryBackoff
+	if m.RetryMax > 0 {
+		pd.RetryErrors = m.RetryErrors()
+	}
+	return pd
+}
+
+// policyFuncs returns the template functions used to apply the client
+// policies to the go-kit client endpoints.
+func policyFuncs() map[string]interface{} {
+	fm := codegen.TemplateFuncs()
+	fm["hasTimeout"] = hasTimeout
+	fm["hasRetry"] = hasRetry
+	return fm
+}
+
+// hasTimeout returns true if the design defines a timeout for the given
+// service method.
+func hasTimeout(svc, method string) bool {
+	m := goakitexpr.Method(svc, method)
+	return m != nil && m.Timeout > 0
+}
+
+// hasRetry returns true if the design defines a retry policy for the given
+// service method.
+func hasRetry(svc, method string) bool {
+	m := goakitexpr.Method(svc, method)
+	return m != nil && m.RetryMax > 0
+}
+
+// durationLiteral returns the Go expression of the given duration using the
+// largest time unit that divides it, e.g. "5*time.Second".
+func durationLiteral(d time.Duration) string {
+	if d == 0 {
+		return "0"
+	}
+	units := []struct {
+		Unit time.Duration
+		Name string
+	}{
+		{time.Hour, "time.Hour"},
+		{time.Minute, "time.Minute"},
+		{time.Second, "time.Second"},
+		{time.Millisecond, "time.Millisecond"},
+		{time.Microsecond, "time.Microsecond"},
+	}
+	for _, u := range units {
+		if d%u.Unit == 0 {
+			if d == u.Unit {
+				return u.Name
+			}
+			return fmt.Sprintf("%d*%s", d/u.Unit, u.Name)
+		}
+	}
+	return fmt.Sprintf("%d*time.Nanosecond", d)
+}
+
+// input: policyData
+const serverTimeoutT = `{{ printf "Timeout%sEndpoint returns an endpoint middleware that cancels the context of the %s %s requests after %s." .Method.VarName .ServiceName .Method.Name .Timeout | comment }}
+func Timeout{{ .Method.VarName }}Endpoint() endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (interface{}, error) {
+			ctx, cancel := context.WithTimeout(ctx, {{ durationLiteral .Timeout }})
+			defer cancel()
+			return next(ctx, request)
+		}
+	}
+}
+`
+
+// input: policyData
+const clientTimeoutT = `{{ printf "Timeout%sEndpoint returns an endpoint middleware that cancels the requests made to the %s %s server after %s." .Method.VarName .ServiceName .Method.Name .Timeout | comment }}
+func Timeout{{ .Method.VarName }}Endpoint() endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (interface{}, error) {
+			ctx, cancel := context.WithTimeout(ctx, {{ durationLiteral .Timeout }})
+			defer cancel()
+			return next(ctx, request)
+		}
+	}
+}
+`
+
+// input: policyData
+const clientRetryT = `{{ printf "Retry%sEndpoint returns an endpoint middleware that retries the requests made to the %s %s server up to %d times, waiting for %s before each retry. Only the errors marked as temporary or timeout in the design are retried, see Retryable%sError." .Method.VarName .ServiceName .Method.Name .RetryMax .RetryBackoff .Method.VarName | comment }}
+func Retry{{ .Method.VarName }}Endpoint() endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+			for i := 0; ; i++ {
+				response, err = next(ctx, request)
+				if err == nil || i == {{ .RetryMax }} || !Retryable{{ .Method.VarName }}Error(err) {
+					return
+				}
+				select {
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				case <-time.After({{ durationLiteral .RetryBackoff }}):
+				}
+			}
+		}
+	}
+}
+
+{{ printf "Retryable%sError returns true if err is one of the %s %s errors marked as temporary or timeout in the design." .Method.VarName .ServiceName .Method.Name | comment }}
+func Retryable{{ .Method.VarName }}Error(err error) bool {
+	se, ok := err.(*goa.ServiceError)
+	if !ok {
+		return false
+	}
+	switch se.Name {
+	case {{ range $i, $name := .RetryErrors }}{{ if $i }}, {{ end }}{{ printf "%q" $name }}{{ end }}:
+		return true
+	}
+	return false
+}
+`
@@ -0,0 +1,48 @@
+package goakit
+
+import (
+	"testing"
+	"time"
+
+	"goa.design/goa/expr"
+	"goa.design/plugins/goakit/testdata"
+)
+
+func TestPolicyFiles(t *testing.T) {
+	runDSL(t, testdata.PolicyDSL)
+	fs := PolicyFiles("", expr.Root)
+	if len(fs) != 2 {
+		t.Fatalf("got %d files, expected 2", len(fs))
+	}
+	testCode(t, fs[0], "goakit-server-timeout", []string{testdata.PolicyMethodServerTimeoutCode})
+	testCode(t, fs[1], "goakit-client-timeout", []string{testdata.PolicyMethodClientTimeoutCode})
+	testCode(t, fs[1], "goakit-client-retry", []string{testdata.PolicyMethodClientRetryCode})
+}
+
+func TestPolicyFilesDisabled(t *testing.T) {
+	runDSL(t, testdata.SimpleServiceDSL)
+	if fs := PolicyFiles("", expr.Root); len(fs) != 0 {
+		t.Errorf("got %d files, expected none", len(fs))
+	}
+}
+
+func TestDurationLiteral(t *testing.T) {
+	cases := map[string]struct {
+		Duration time.Duration
+		Expected string
+	}{
+		"zero":         {0, "0"},
+		"unit":         {time.Second, "time.Second"},
+		"seconds":      {5 * time.Second, "5*time.Second"},
+		"minutes":      {90 * time.Minute, "90*time.Minute"},
+		"milliseconds": {1500 * time.Millisecond, "1500*time.Millisecond"},
+		"nanoseconds":  {1001, "1001*time.Nanosecond"},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := durationLiteral(c.Duration); got != c.Expected {
+				t.Errorf("got %q, expected %q", got, c.Expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,323 @@
+package goakit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"goa.design/goa/codegen"
+)
+
+type (
+	// sectionHook describes the changes made by the plugin to a section
+	// generated by goa. Hooks are looked up by section name so that changes
+	// made to the goa templates are detected at generation time instead of
+	// silently producing incorrect code.
+	sectionHook struct {
+		// Section is the name of the section the hook applies to.
+		Section string
+		// Snippet identifies the section the hook applies to by content
+		// when Section is empty: the hook applies to the first section
+		// whose source contains Snippet.
+		Snippet string
+		// Rewrite modifies the section in place. header is the file header
+		// section used to add imports.
+		Rewrite func(header, s *codegen.SectionTemplate) error
+	}
+
+	// loggerCallSite describes the go-kit log entry a stdlib logger call is
+	// rewritten into.
+	loggerCallSite struct {
+		// Level is the go-kit log level function, e.g. "Info".
+		Level string
+		// Msg is the log entry message.
+		Msg string
+		// Keys are the keys of the call arguments following the format.
+		Keys []string
+	}
+)
+
+// stdlibLoggerFuncs maps the stdlib logger functions that the plugin rewrites
+// into go-kit logger calls to the fmt function used to format the log message.
+var stdlibLoggerFuncs = map[string]string{
+	"Print":   "fmt.Sprint",
+	"Printf":  "fmt.Sprintf",
+	"Println": "fmt.Sprint",
+}
+
+// loggerCallSites maps the format of the stdlib logger calls made by the goa
+// example templates to the go-kit log entries they are rewritten into.
+var loggerCallSites = map[string]*loggerCallSite{
+	`"HTTP %q mounted on %s %s"`:        {Level: "Info", Msg: "HTTP endpoint mounted", Keys: []string{"method", "verb", "pattern"}},
+	`"HTTP server listening on %q"`:     {Level: "Info", Msg: "HTTP server listening", Keys: []string{"addr"}},
+	`"shutting down HTTP server at %q"`: {Level: "Info", Msg: "shutting down HTTP server", Keys: []string{"addr"}},
+	`"[%s] ERROR: %s"`:                  {Level: "Error", Msg: "request failed", Keys: []string{"request_id", "err"}},
+	`"exiting (%v)"`:                    {Level: "Info", Msg: "exiting", Keys: []string{"reason"}},
+}
+
+// unsupportedLoggerFuncs lists the stdlib logger functions that have no go-kit
+// logger equivalent.
+var unsupportedLoggerFuncs = map[string]bool{
+	"Fatal":     true,
+	"Fatalf":    true,
+	"Fatalln":   true,
+	"Flags":     true,
+	"Output":    true,
+	"Panic":     true,
+	"Panicf":    true,
+	"Panicln":   true,
+	"Prefix":    true,
+	"SetFlags":  true,
+	"SetOutput": true,
+	"SetPrefix": true,
+	"Writer":    true,
+}
+
+// applyHooks runs the hooks on the sections of the given file. It returns an
+// error if the file does not contain a section matching one of the hooks.
+func applyHooks(f *codegen.File, hooks []*sectionHook) error {
+	for _, h := range hooks {
+		var found bool
+		for _, s := range f.SectionTemplates {
+			if h.Section != "" && s.Name != h.Section {
+				continue
+			}
+			if h.Section == "" && (found || !strings.Contains(s.Source, h.Snippet)) {
+				continue
+			}
+			found = true
+			if err := h.Rewrite(f.SectionTemplates[0], s); err != nil {
+				return fmt.Errorf("goakit: %s: %s", f.Path, err)
+			}
+		}
+		if !found {
+			if h.Section == "" {
+				return fmt.Errorf("goakit: %s: no section contains %q, the goa templates may have changed", f.Path, h.Snippet)
+			}
+			return fmt.Errorf("goakit: %s: section %q not found, the goa templates may have changed", f.Path, h.Section)
+		}
+	}
+	return nil
+}
+
+// replaceSnippet replaces all the occurrences of old with new in the section
+// source. It returns an error if the source does not contain old.
+func replaceSnippet(s *codegen.SectionTemplate, old, new string) error {
+	if !strings.Contains(s.Source, old) {
+		return fmt.Errorf("section %q does not contain %q, the goa templates may have changed", s.Name, old)
+	}
+	s.Source = strings.Replace(s.Source, old, new, -1)
+	return nil
+}
+
+// replaceQualifiedIdent replaces all the occurrences of the qualified
+// identifier old (e.g. "goa.Endpoint") with new in the Go source src. Only
+// occurrences that make up a complete identifier are replaced. It returns the
+// resulting source and the number of replacements.
+func replaceQualifiedIdent(src, old, new string) (string, int) {
+	var (
+		b     strings.Builder
+		count int
+	)
+	for {
+		idx := strings.Index(src, old)
+		if idx < 0 {
+			b.WriteString(src)
+			return b.String(), count
+		}
+		end := idx + len(old)
+		before, _ := utf8.DecodeLastRuneInString(src[:idx])
+		after, _ := utf8.DecodeRuneInString(src[end:])
+		b.WriteString(src[:idx])
+		if (idx > 0 && (isIdentRune(before) || before == '.')) || (end < len(src) && isIdentRune(after)) {
+			b.WriteString(old)
+		} else {
+			b.WriteString(new)
+			count++
+		}
+		src = src[end:]
+	}
+}
+
+// rewriteLoggerCalls replaces the stdlib logger calls made in the Go source
+// src with the equivalent leveled go-kit logger calls. The calls made by the
+// goa example templates are rewritten into structured log entries whose level
+// and keys are given by loggerCallSites, e.g.
+//
+//	logger.Printf("HTTP server listening on %q", u.Host)
+//
+// becomes
+//
+//	level.Info(logger).Log("msg", "HTTP server listening", "addr", u.Host)
+//
+// Calls logging a single string literal log it as the message, the other calls
+// log the formatted message at the info level. Calls may span multiple lines.
+// rewriteLoggerCalls returns an error if a call cannot be parsed or if it makes
+// use of a stdlib logger function that has no go-kit equivalent. It also
+// returns the number of rewritten calls.
+func rewriteLoggerCalls(src string) (string, int, error) {
+	const recv = "logger."
+	var (
+		b     strings.Builder
+		count int
+	)
+	for {
+		idx := strings.Index(src, recv)
+		if idx < 0 {
+			b.WriteString(src)
+			return b.String(), count, nil
+		}
+		before, _ := utf8.DecodeLastRuneInString(src[:idx])
+		start := idx + len(recv)
+		fn := src[start:]
+		if i := strings.IndexFunc(fn, func(r rune) bool { return !isIdentRune(r) }); i >= 0 {
+			fn = fn[:i]
+		}
+		open := start + len(fn)
+		if (idx > 0 && isIdentRune(before)) || open >= len(src) || src[open] != '(' {
+			b.WriteString(src[:start])
+			src = src[start:]
+			continue
+		}
+		if unsupportedLoggerFuncs[fn] {
+			return "", 0, fmt.Errorf("unsupported stdlib logger call logger.%s", fn)
+		}
+		format, ok := stdlibLoggerFuncs[fn]
+		if !ok {
+			b.WriteString(src[:start])
+			src = src[start:]
+			continue
+		}
+		closing, err := matchParen(src, open)
+		if err != nil {
+			return "", 0, fmt.Errorf("logger.%s: %s", fn, err)
+		}
+		// The receiver may be a selector, e.g. s.logger.
+		rstart := strings.LastIndexFunc(src[:idx], func(r rune) bool { return !isIdentRune(r) && r != '.' }) + 1
+		lvl, keyvals := loggerKeyvals(format, src[open:closing+1])
+		b.WriteString(src[:rstart])
+		fmt.Fprintf(&b, "level.%s(%s).Log(%s)", lvl, src[rstart:start-1], keyvals)
+		count++
+		src = src[closing+1:]
+	}
+}
+
+// loggerKeyvals returns the level and the key/value pairs of the go-kit log
+// entry equivalent to the stdlib logger call made with the given arguments.
+// format is the fmt function used to format the stdlib log message and args
+// the source of the call arguments including the enclosing parentheses.
+func loggerKeyvals(format, args string) (string, string) {
+	vals := splitArgs(args)
+	if len(vals) > 0 {
+		if site, ok := loggerCallSites[vals[0]]; ok && len(site.Keys) == len(vals)-1 {
+			kvs := []string{`"msg"`, strconv.Quote(site.Msg)}
+			for i, k := range site.Keys {
+				kvs = append(kvs, strconv.Quote(k), vals[i+1])
+			}
+			return site.Level, strings.Join(kvs, ", ")
+		}
+		if len(vals) == 1 && isStringLit(vals[0]) {
+			return "Info", `"msg", ` + vals[0]
+		}
+	}
+	return "Info", `"msg", ` + format + args
+}
+
+// splitArgs returns the source of the arguments of the call arguments args
+// including the enclosing parentheses. The arguments are trimmed.
+func splitArgs(args string) []string {
+	var (
+		vals  []string
+		depth int
+		from  = 1
+	)
+	add := func(to int) {
+		if v := strings.TrimSpace(args[from:to]); v != "" {
+			vals = append(vals, v)
+		}
+		from = to + 1
+	}
+	for i := 1; i < len(args); i++ {
+		switch args[i] {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			if depth == 0 {
+				add(i)
+				return vals
+			}
+			depth--
+		case ',':
+			if depth == 0 {
+				add(i)
+			}
+		case '"', '\'', '`':
+			if end := skipLiteral(args, i); end > 0 {
+				i = end
+			}
+		}
+	}
+	add(len(args))
+	return vals
+}
+
+// isStringLit returns true if src is a single Go string literal.
+func isStringLit(src string) bool {
+	if len(src) < 2 || (src[0] != '"' && src[0] != '`') {
+		return false
+	}
+	return skipLiteral(src, 0) == len(src)-1
+}
+
+// matchParen returns the index of the parenthesis closing the one at index
+// open in src. Parentheses appearing in string and rune literals are ignored.
+func matchParen(src string, open int) (int, error) {
+	depth := 0
+	for i := open; i < len(src); i++ {
+		switch c := src[i]; c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i, nil
+			}
+		case '"', '\'', '`':
+			end := skipLiteral(src, i)
+			if end < 0 {
+				return 0, fmt.Errorf("unterminated literal at offset %d", i)
+			}
+			i = end
+		}
+	}
+	return 0, fmt.Errorf("unbalanced parenthesis at offset %d", open)
+}
+
+// skipLiteral returns the index of the character terminating the string or
+// rune literal starting at index start in src, -1 if the literal is not
+// terminated.
+func skipLiteral(src string, start int) int {
+	delim := src[start]
+	for i := start + 1; i < len(src); i++ {
+		switch src[i] {
+		case '\\':
+			if delim != '`' {
+				i++
+			}
+		case '\n':
+			if delim != '`' {
+				return -1
+			}
+		case delim:
+			return i
+		}
+	}
+	return -1
+}
+
+// isIdentRune returns true if r may appear in a Go identifier.
+func isIdentRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,199 @@
+package goakit
+
+import (
+	"strings"
+	"testing"
+
+	"goa.design/goa/codegen"
+)
+
+func TestRewriteLoggerCalls(t *testing.T) {
+	cases := map[string]struct {
+		Source   string
+		Expected string
+		Count    int
+		Error    string
+	}{
+		"printf": {
+			Source:   `logger.Printf("HTTP %q mounted on %s %s", m.Method, m.Verb, m.Pattern)`,
+			Expected: `level.Info(logger).Log("msg", "HTTP endpoint mounted", "method", m.Method, "verb", m.Verb, "pattern", m.Pattern)`,
+			Count:    1,
+		},
+		"print": {
+			Source:   `s.logger.Print("calc.add")`,
+			Expected: `level.Info(s.logger).Log("msg", "calc.add")`,
+			Count:    1,
+		},
+		"println": {
+			Source:   `logger.Println("exited")`,
+			Expected: `level.Info(logger).Log("msg", "exited")`,
+			Count:    1,
+		},
+		"multi-line": {
+			Source:   "logger.Printf(\"[%s] ERROR: %s\",\n\tid,\n\terr.Error(),\n)",
+			Expected: "level.Error(logger).Log(\"msg\", \"request failed\", \"request_id\", id, \"err\", err.Error())",
+			Count:    1,
+		},
+		"nested-parens": {
+			Source:   `logger.Printf("exiting (%v)", <-errc); logger.Print(")")`,
+			Expected: `level.Info(logger).Log("msg", "exiting", "reason", <-errc); level.Info(logger).Log("msg", ")")`,
+			Count:    2,
+		},
+		"raw-string": {
+			Source:   "logger.Print(`(`, ')')",
+			Expected: "level.Info(logger).Log(\"msg\", fmt.Sprint(`(`, ')'))",
+			Count:    1,
+		},
+		"error": {
+			Source:   `logger.Printf("[%s] ERROR: %s", id, err.Error())`,
+			Expected: `level.Error(logger).Log("msg", "request failed", "request_id", id, "err", err.Error())`,
+			Count:    1,
+		},
+		"listening": {
+			Source:   `logger.Printf("HTTP server listening on %q", u.Host)`,
+			Expected: `level.Info(logger).Log("msg", "HTTP server listening", "addr", u.Host)`,
+			Count:    1,
+		},
+		"unknown-call-site": {
+			Source:   `logger.Printf("request %s", err.Error())`,
+			Expected: `level.Info(logger).Log("msg", fmt.Sprintf("request %s", err.Error()))`,
+			Count:    1,
+		},
+		"call-site-arguments": {
+			Source:   `logger.Printf("HTTP server listening on %q", u.Host, u.Path)`,
+			Expected: `level.Info(logger).Log("msg", fmt.Sprintf("HTTP server listening on %q", u.Host, u.Path))`,
+			Count:    1,
+		},
+		"composite-argument": {
+			Source:   `logger.Printf("exiting (%v)", f(a, []int{1, 2}))`,
+			Expected: `level.Info(logger).Log("msg", "exiting", "reason", f(a, []int{1, 2}))`,
+			Count:    1,
+		},
+		"print-arguments": {
+			Source:   `logger.Print("a", b)`,
+			Expected: `level.Info(logger).Log("msg", fmt.Sprint("a", b))`,
+			Count:    1,
+		},
+		"other-logger": {
+			Source:   `mylogger.Printf("%d", 1); logger.Log("msg", "ok"); logger = log.New(os.Stderr, "", 0)`,
+			Expected: `mylogger.Printf("%d", 1); logger.Log("msg", "ok"); logger = log.New(os.Stderr, "", 0)`,
+		},
+		"unbalanced": {
+			Source: `logger.Printf("%d", f(1)`,
+			Error:  "unbalanced parenthesis",
+		},
+		"unterminated": {
+			Source: "logger.Printf(\"%d, 1)\n",
+			Error:  "unterminated literal",
+		},
+		"unsupported": {
+			Source: `logger.Fatalf("%s", err)`,
+			Error:  "unsupported stdlib logger call logger.Fatalf",
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			code, n, err := rewriteLoggerCalls(c.Source)
+			if c.Error != "" {
+				if err == nil || !strings.Contains(err.Error(), c.Error) {
+					t.Fatalf("got error %v, expected %q", err, c.Error)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if code != c.Expected {
+				t.Errorf("invalid code, got:\n%s\nexpected:\n%s", code, c.Expected)
+			}
+			if n != c.Count {
+				t.Errorf("invalid count, got %d, expected %d", n, c.Count)
+			}
+		})
+	}
+}
+
+func TestReplaceQualifiedIdent(t *testing.T) {
+	cases := map[string]struct {
+		Source   string
+		Expected string
+		Count    int
+	}{
+		"field":      {"Add goa.Endpoint\n", "Add endpoint.Endpoint\n", 1},
+		"boundaries": {"goa.Endpoint", "endpoint.Endpoint", 1},
+		"multiple":   {"func(goa.Endpoint) goa.Endpoint", "func(endpoint.Endpoint) endpoint.Endpoint", 2},
+		"prefix":     {"mygoa.Endpoint x.goa.Endpoint", "mygoa.Endpoint x.goa.Endpoint", 0},
+		"suffix":     {"goa.Endpoints goa.Endpoint_", "goa.Endpoints goa.Endpoint_", 0},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			code, n := replaceQualifiedIdent(c.Source, "goa.Endpoint", "endpoint.Endpoint")
+			if code != c.Expected {
+				t.Errorf("invalid code, got %q, expected %q", code, c.Expected)
+			}
+			if n != c.Count {
+				t.Errorf("invalid count, got %d, expected %d", n, c.Count)
+			}
+		})
+	}
+}
+
+func TestApplyHooks(t *testing.T) {
+	replace := func(_, s *codegen.SectionTemplate) error {
+		return replaceSnippet(s, "adapter", "logger")
+	}
+	var (
+		byName    = &sectionHook{Section: "middleware", Rewrite: replace}
+		bySnippet = &sectionHook{Snippet: "Log(", Rewrite: replace}
+	)
+	cases := map[string]struct {
+		Hook     *sectionHook
+		Sections []*codegen.SectionTemplate
+		Expected string
+		Error    string
+	}{
+		"found": {
+			Hook:     byName,
+			Sections: []*codegen.SectionTemplate{{Name: "header"}, {Name: "middleware", Source: "Log(adapter)"}},
+			Expected: "Log(logger)",
+		},
+		"missing-section": {
+			Hook:     byName,
+			Sections: []*codegen.SectionTemplate{{Name: "header"}, {Name: "other", Source: "Log(adapter)"}},
+			Error:    `section "middleware" not found`,
+		},
+		"missing-snippet": {
+			Hook:     byName,
+			Sections: []*codegen.SectionTemplate{{Name: "header"}, {Name: "middleware", Source: "Log(mdlwrLogger)"}},
+			Error:    `section "middleware" does not contain "adapter"`,
+		},
+		"found-by-snippet": {
+			Hook:     bySnippet,
+			Sections: []*codegen.SectionTemplate{{Name: "header"}, {Name: "other", Source: "Log(adapter)"}},
+			Expected: "Log(logger)",
+		},
+		"missing-section-by-snippet": {
+			Hook:     bySnippet,
+			Sections: []*codegen.SectionTemplate{{Name: "header"}, {Name: "other", Source: "Print(adapter)"}},
+			Error:    `no section contains "Log("`,
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			f := &codegen.File{Path: "cmd/svc/http.go", SectionTemplates: c.Sections}
+			err := applyHooks(f, []*sectionHook{c.Hook})
+			if c.Error != "" {
+				if err == nil || !strings.Contains(err.Error(), c.Error) {
+					t.Fatalf("got error %v, expected %q", err, c.Error)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if src := c.Sections[1].Source; src != c.Expected {
+				t.Errorf("invalid code, got %q, expected %q", src, c.Expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package goakit
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"goa.design/goa/codegen"
+	"goa.design/goa/expr"
+	httpcodegen "goa.design/goa/http/codegen"
+)
+
+// SDFiles produces the files defining the go-kit service discovery factories
+// and load balanced endpoints for the service HTTP clients. The load balanced
+// endpoints retry the requests according to the policies defined in the
+// design.
+func SDFiles(genpkg string, root *expr.RootExpr) []*codegen.File {
+	var fw []*codegen.File
+	for _, svc := range root.API.HTTP.Services {
+		if f := clientSD(genpkg, svc); f != nil {
+			fw = append(fw, f)
+		}
+	}
+	return fw
+}
+
+// clientSD returns the file defining the go-kit sd.Factory functions and load
+// balanced endpoints for each endpoint of the given service. It returns nil if
+// the service has no endpoint or if the service HTTP client requires more than
+// the default arguments to be built, that is if any of the endpoints uses
+// streaming or multipart requests.
+func clientSD(genpkg string, svc *expr.HTTPServiceExpr) *codegen.File {
+	data := httpcodegen.HTTPServices.Get(svc.Name())
+	if !hasKitClient(data) {
+		return nil
+	}
+	path := filepath.Join(codegen.Gendir, "http", codegen.SnakeCase(svc.Name()), "kitclient", "sd.go")
+	title := fmt.Sprintf("%s go-kit HTTP client service discovery", svc.Name())
+	sections := []*codegen.SectionTemplate{
+		codegen.Header(title, "client", []*codegen.ImportSpec{
+			{Path: "context"},
+			{Path: "io"},
+			{Path: "net/http"},
+			{Path: "time"},
+			{Path: "github.com/go-kit/kit/endpoint"},
+			{Path: "github.com/go-kit/kit/log"},
+			{Path: "github.com/go-kit/kit/sd"},
+			{Path: "github.com/go-kit/kit/sd/lb"},
+			{Path: "github.com/go-kit/kit/transport/http", Name: "kithttp"},
+			{Path: "github.com/opentracing/opentracing-go", Name: "opentracing"},
+			{Path: "goa.design/goa/http", Name: "goahttp"},
+			{Path: genpkg + "/http/" + data.Service.Name + "/client"},
+		}),
+	}
+	fm := clientFuncs()
+	for _, e := range data.Endpoints {
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:    "goakit-sd-factory",
+			Source:  sdFactoryT,
+			Data:    e,
+			FuncMap: fm,
+		})
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:    "goakit-balanced-endpoint",
+			Source:  balancedEndpointT,
+			Data:    policy(e),
+			FuncMap: fm,
+		})
+	}
+
+	return &codegen.File{Path: path, SectionTemplates: sections}
+}
+
+// input: EndpointData
+const sdFactoryT = `{{ printf "%sFactory returns a go-kit sd.Factory that creates endpoints making requests to the %s %s server running on the given instance. The instance must be the server host optionally followed by the port, e.g. \"localhost:8080\"." .Method.VarName .ServiceName .Method.Name | comment }}
+{{- if hasTimeout .ServiceName .Method.Name }}
+{{ printf "The endpoints cancel the requests after the timeout defined in the design. They do not retry failed requests, see Balanced%sEndpoint." .Method.VarName | comment }}
+{{- end }}
+{{- if isTraced .ServiceName }}
+{{ printf "The endpoints record a %q span for each request using tracer, logger logs the errors that occur when injecting the span context into the requests." (spanName .ServiceName .Method.Name) | comment }}
+{{- end }}
+{{- $timeout := hasTimeout .ServiceName .Method.Name }}
+{{- $traced := isTraced .ServiceName }}
+func {{ .Method.VarName }}Factory(scheme string, enc func(*http.Request) goahttp.Encoder, dec func(*http.Response) goahttp.Decoder, {{ if $traced }}tracer opentracing.Tracer, logger log.Logger, {{ end }}options ...kithttp.ClientOption) sd.Factory {
+	return func(instance string) (endpoint.Endpoint, io.Closer, error) {
+		c := client.NewClient(scheme, instance, http.DefaultClient, enc, dec, false)
+		e := kithttp.NewExplicitClient(
+			func(ctx context.Context, v interface{}) (*http.Request, error) {
+				req, err := c.{{ .RequestInit.Name }}(ctx, v)
+				if err != nil {
+					return nil, err
+				}
+			{{- if .RequestEncoder }}
+				if err := {{ .RequestEncoder }}(enc)(ctx, req, v); err != nil {
+					return nil, err
+				}
+			{{- end }}
+				return req, nil
+			},
+			{{ .ResponseDecoder }}(dec),
+		{{- if $traced }}
+			append({{ .Method.VarName }}TraceClientOptions(tracer, logger), options...)...,
+		{{- else }}
+			options...,
+		{{- end }}
+		)
+		return {{ if $traced }}Trace{{ .Method.VarName }}Endpoint(tracer)({{ end }}{{ if $timeout }}Timeout{{ .Method.VarName }}Endpoint()({{ end }}e.Endpoint(){{ if $timeout }}){{ end }}{{ if $traced }}){{ end }}, nil, nil
+	}
+}
+`
+
+// input: policyData
+const balancedEndpointT = `{{ printf "Balanced%sEndpoint returns an endpoint that load balances the requests made to the %s %s server across the instances published by instancer using a round robin strategy. factory creates the endpoint used to make requests to each instance, see %sFactory." .Method.VarName .ServiceName .Method.Name .Method.VarName | comment }}
+{{- if .RetryMax }}
+{{ printf "Failed requests are retried on the next instance up to %d times in addition to the first request if the error is marked as temporary or timeout in the design, see Retryable%sError." .RetryMax .Method.VarName | comment }}
+{{- else }}
+{{ comment "Failed requests are retried on the next instance up to retryMax times in addition to the first request whatever the error as the design defines no retry policy." }}
+{{- end }}
+{{ comment "The requests fail if they do not complete within timeout. The endpoint returns the error of the last request, not a lb.RetryError." }}
+func Balanced{{ .Method.VarName }}Endpoint(instancer sd.Instancer, factory sd.Factory, logger log.Logger, {{ if not .RetryMax }}retryMax int, {{ end }}timeout time.Duration) endpoint.Endpoint {
+	endpointer := sd.NewEndpointer(instancer, factory, logger)
+	balancer := lb.NewRoundRobin(endpointer)
+	retry := lb.RetryWithCallback(timeout, balancer, func(n int, err error) (bool, error) {
+		// n counts the requests made so far, including the first one.
+	{{- if .RetryMax }}
+		return n <= {{ .RetryMax }} && Retryable{{ .Method.VarName }}Error(err), nil
+	{{- else }}
+		return n <= retryMax, nil
+	{{- end }}
+	})
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		res, err := retry(ctx, request)
+		if rerr, ok := err.(lb.RetryError); ok {
+			return nil, rerr.Final
+		}
+		return res, err
+	}
+}
+`
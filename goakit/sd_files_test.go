@@ -0,0 +1,54 @@
+package goakit
+
+import (
+	"testing"
+
+	"goa.design/goa/expr"
+	"goa.design/plugins/goakit/testdata"
+)
+
+func TestSDFiles(t *testing.T) {
+	cases := map[string]struct {
+		DSL  func()
+		Code map[string][]string
+	}{
+		"simple-service": {
+			DSL: testdata.SimpleServiceDSL,
+			Code: map[string][]string{
+				"goakit-sd-factory":        []string{testdata.SimpleMethodSDFactoryCode},
+				"goakit-balanced-endpoint": []string{testdata.SimpleMethodBalancedEndpointCode},
+			},
+		},
+		"policy": {
+			DSL: testdata.PolicyDSL,
+			Code: map[string][]string{
+				"goakit-sd-factory":        []string{testdata.PolicyMethodSDFactoryCode, testdata.NoPolicyMethodSDFactoryCode},
+				"goakit-balanced-endpoint": []string{testdata.PolicyMethodBalancedEndpointCode, testdata.NoPolicyMethodBalancedEndpointCode},
+			},
+		},
+		"tracing": {
+			DSL: testdata.TracingDSL,
+			Code: map[string][]string{
+				"goakit-sd-factory": []string{testdata.TracingMethodSDFactoryCode},
+			},
+		},
+		"with-payload": {
+			DSL: testdata.WithPayloadDSL,
+			Code: map[string][]string{
+				"goakit-sd-factory": []string{testdata.WithPayloadMethodSDFactoryCode},
+			},
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			runDSL(t, c.DSL)
+			fs := SDFiles("", expr.Root)
+			if len(fs) != 1 {
+				t.Fatalf("got %d files, expected 1", len(fs))
+			}
+			for sec, secCode := range c.Code {
+				testCode(t, fs[0], sec, secCode)
+			}
+		})
+	}
+}
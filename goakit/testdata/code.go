@@ -226,7 +226,13 @@ var MixedMainLoggerCode = `func example() {
 		logger log.Logger
 	)
 	{
-		logger = log.NewLogfmtLogger(os.Stderr)
+		w := log.NewSyncWriter(os.Stderr)
+		switch *logFormatF {
+		case "json":
+			logger = log.NewJSONLogger(w)
+		default:
+			logger = log.NewLogfmtLogger(w)
+		}
 		logger = log.With(logger, "ts", log.DefaultTimestampUTC)
 		logger = log.With(logger, "caller", log.DefaultCaller)
 	}
@@ -243,11 +249,12 @@ var MixedMainServerInitCode = `func example() {
 		mixedServiceServer             *mixedservicesvr.Server
 	)
 	{
-		eh := ErrorHandler(logger)
+		eh := errorHandler(logger)
 		mixedServiceMixedMethodHandler = kithttp.NewServer(
-			endpoint.Endpoint(mixedServiceEndpoints.MixedMethod),
+			mixedservicekitsvr.LogMixedMethodEndpoint(logger)(endpoint.Endpoint(mixedServiceEndpoints.MixedMethod)),
 			func(context.Context, *http.Request) (request interface{}, err error) { return nil, nil },
 			mixedservicekitsvr.EncodeMixedMethodResponse(enc),
+			mixedservicekitsvr.ServerOptions()...,
 		)
 		mixedServiceServer = mixedservicesvr.New(mixedServiceEndpoints, mux, dec, enc, eh)
 	}
@@ -263,11 +270,11 @@ var MixedMainMiddlewareCode = `func example() {
 	// here apply to all the service endpoints.
 	var handler http.Handler = mux
 	{
-		if *dbg {
-			handler = middleware.Debug(mux, os.Stdout)(handler)
+		if debug {
+			handler = httpmdlwr.Debug(mux, os.Stdout)(handler)
 		}
-		handler = middleware.Log(logger)(handler)
-		handler = middleware.RequestID()(handler)
+		handler = httpmdlwr.Log(logger)(handler)
+		handler = httpmdlwr.RequestID()(handler)
 	}
 }
 `
@@ -284,17 +291,19 @@ var MultiServicesServerInitCode = `func example() {
 		service2Server        *service2svr.Server
 	)
 	{
-		eh := ErrorHandler(logger)
+		eh := errorHandler(logger)
 		service1MethodHandler = kithttp.NewServer(
-			endpoint.Endpoint(service1Endpoints.Method),
+			service1kitsvr.LogMethodEndpoint(logger)(endpoint.Endpoint(service1Endpoints.Method)),
 			func(context.Context, *http.Request) (request interface{}, err error) { return nil, nil },
 			service1kitsvr.EncodeMethodResponse(enc),
+			service1kitsvr.ServerOptions()...,
 		)
 		service1Server = service1svr.New(service1Endpoints, mux, dec, enc, eh)
 		service2MethodHandler = kithttp.NewServer(
-			endpoint.Endpoint(service2Endpoints.Method),
+			service2kitsvr.LogMethodEndpoint(logger)(endpoint.Endpoint(service2Endpoints.Method)),
 			func(context.Context, *http.Request) (request interface{}, err error) { return nil, nil },
 			service2kitsvr.EncodeMethodResponse(enc),
+			service2kitsvr.ServerOptions()...,
 		)
 		service2Server = service2svr.New(service2Endpoints, mux, dec, enc, eh)
 	}
@@ -304,3 +313,1720 @@ var MultiServicesServerInitCode = `func example() {
 	service2kitsvr.MountMethodHandler(mux, service2MethodHandler)
 }
 `
+
+var TracingMethodServerTracingCode = `// TracingMethodTraceServerOptions returns the go-kit HTTP server options that
+// extract the span context from incoming TracingService TracingMethod requests.
+func TracingMethodTraceServerOptions(tracer opentracing.Tracer, logger log.Logger) []kithttp.ServerOption {
+	return []kithttp.ServerOption{
+		kithttp.ServerBefore(kitot.HTTPToContext(tracer, "TracingService.TracingMethod", logger)),
+	}
+}
+
+// TraceTracingMethodEndpoint returns an endpoint middleware that records a
+// "TracingService.TracingMethod" span for each TracingService TracingMethod
+// request.
+func TraceTracingMethodEndpoint(tracer opentracing.Tracer) endpoint.Middleware {
+	return kitot.TraceServer(tracer, "TracingService.TracingMethod")
+}
+`
+
+var TracingMethodClientTracingCode = `// TracingMethodTraceClientOptions returns the go-kit HTTP client options that
+// inject the span context into outgoing TracingService TracingMethod requests.
+func TracingMethodTraceClientOptions(tracer opentracing.Tracer, logger log.Logger) []kithttp.ClientOption {
+	return []kithttp.ClientOption{
+		kithttp.ClientBefore(kitot.ContextToHTTP(tracer, logger)),
+	}
+}
+
+// TraceTracingMethodEndpoint returns an endpoint middleware that records a
+// "TracingService.TracingMethod" span for each TracingService TracingMethod
+// request.
+func TraceTracingMethodEndpoint(tracer opentracing.Tracer) endpoint.Middleware {
+	return kitot.TraceClient(tracer, "TracingService.TracingMethod")
+}
+`
+
+var TracingClientStructCode = `// Client lists the TracingService service endpoints that make HTTP requests
+// using go-kit clients.
+type Client struct {
+	c       *client.Client
+	enc     func(*http.Request) goahttp.Encoder
+	dec     func(*http.Response) goahttp.Decoder
+	options []kithttp.ClientOption
+	tracer  opentracing.Tracer
+	logger  log.Logger
+}
+
+// NewClient instantiates go-kit HTTP clients for all the TracingService
+// service servers. The arguments are the same as the goa client ones, doer is
+// used to make the HTTP requests and options are appended to the default
+// options returned by ClientOptions.
+// The endpoints record the request spans using the global tracer, see
+// WithTracer.
+func NewClient(
+	scheme string,
+	host string,
+	doer goahttp.Doer,
+	enc func(*http.Request) goahttp.Encoder,
+	dec func(*http.Response) goahttp.Decoder,
+	restoreBody bool,
+	options ...kithttp.ClientOption,
+) *Client {
+	return &Client{
+		c:       client.NewClient(scheme, host, doer, enc, dec, restoreBody),
+		enc:     enc,
+		dec:     dec,
+		options: ClientOptions(append([]kithttp.ClientOption{kithttp.SetClient(doer)}, options...)...),
+		tracer:  opentracing.GlobalTracer(),
+		logger:  log.NewNopLogger(),
+	}
+}
+
+// WithTracer sets the tracer used by the client endpoints to record the
+// request spans and the logger used to log the errors that occur when
+// injecting the span context into the requests. It returns c.
+func (c *Client) WithTracer(tracer opentracing.Tracer, logger log.Logger) *Client {
+	c.tracer = tracer
+	c.logger = logger
+	return c
+}
+`
+
+var TracingMethodClientMethodCode = `// TracingMethod returns an endpoint that makes HTTP requests to the
+// TracingService service TracingMethod server using a go-kit client.
+// The endpoint records a "TracingService.TracingMethod" span for each request
+// using the client tracer.
+func (c *Client) TracingMethod() endpoint.Endpoint {
+	e := kithttp.NewExplicitClient(
+		func(ctx context.Context, v interface{}) (*http.Request, error) {
+			req, err := c.c.BuildTracingMethodRequest(ctx, v)
+			if err != nil {
+				return nil, err
+			}
+			if err := EncodeTracingMethodRequest(c.enc)(ctx, req, v); err != nil {
+				return nil, err
+			}
+			return req, nil
+		},
+		DecodeTracingMethodResponse(c.dec),
+		append(TracingMethodTraceClientOptions(c.tracer, c.logger), c.options...)...,
+	).Endpoint()
+	e = TraceTracingMethodEndpoint(c.tracer)(e)
+	return e
+}
+`
+
+var TracingMethodSDFactoryCode = `// TracingMethodFactory returns a go-kit sd.Factory that creates endpoints
+// making requests to the TracingService TracingMethod server running on the
+// given instance. The instance must be the server host optionally followed by
+// the port, e.g. "localhost:8080".
+// The endpoints record a "TracingService.TracingMethod" span for each request
+// using tracer, logger logs the errors that occur when injecting the span
+// context into the requests.
+func TracingMethodFactory(scheme string, enc func(*http.Request) goahttp.Encoder, dec func(*http.Response) goahttp.Decoder, tracer opentracing.Tracer, logger log.Logger, options ...kithttp.ClientOption) sd.Factory {
+	return func(instance string) (endpoint.Endpoint, io.Closer, error) {
+		c := client.NewClient(scheme, instance, http.DefaultClient, enc, dec, false)
+		e := kithttp.NewExplicitClient(
+			func(ctx context.Context, v interface{}) (*http.Request, error) {
+				req, err := c.BuildTracingMethodRequest(ctx, v)
+				if err != nil {
+					return nil, err
+				}
+				if err := EncodeTracingMethodRequest(enc)(ctx, req, v); err != nil {
+					return nil, err
+				}
+				return req, nil
+			},
+			DecodeTracingMethodResponse(dec),
+			append(TracingMethodTraceClientOptions(tracer, logger), options...)...,
+		)
+		return TraceTracingMethodEndpoint(tracer)(e.Endpoint()), nil, nil
+	}
+}
+`
+
+var TracingServerInitCode = `func example() {
+	// Wrap the endpoints with the transport specific layers. The generated
+	// server packages contains code generated from the design which maps
+	// the service input and output data structures to HTTP requests and
+	// responses.
+	var (
+		tracingServiceTracingMethodHandler *kithttp.Server
+		tracingServiceServer               *tracingservicesvr.Server
+	)
+	{
+		eh := errorHandler(logger)
+		tracer := opentracing.GlobalTracer()
+		tracingServiceTracingMethodHandler = kithttp.NewServer(
+			tracingservicekitsvr.TraceTracingMethodEndpoint(tracer)(tracingservicekitsvr.LogTracingMethodEndpoint(logger)(endpoint.Endpoint(tracingServiceEndpoints.TracingMethod))),
+			tracingservicekitsvr.DecodeTracingMethodRequest(mux, dec),
+			tracingservicekitsvr.EncodeTracingMethodResponse(enc),
+			tracingservicekitsvr.ServerOptions(tracingservicekitsvr.TracingMethodTraceServerOptions(tracer, logger)...)...,
+		)
+		tracingServiceServer = tracingservicesvr.New(tracingServiceEndpoints, mux, dec, enc, eh)
+	}
+
+	// Configure the mux.
+	tracingservicekitsvr.MountTracingMethodHandler(mux, tracingServiceTracingMethodHandler)
+}
+`
+
+var SimpleMethodSDFactoryCode = `// SimpleMethodFactory returns a go-kit sd.Factory that creates endpoints
+// making requests to the SimpleService SimpleMethod server running on the
+// given instance. The instance must be the server host optionally followed by
+// the port, e.g. "localhost:8080".
+func SimpleMethodFactory(scheme string, enc func(*http.Request) goahttp.Encoder, dec func(*http.Response) goahttp.Decoder, options ...kithttp.ClientOption) sd.Factory {
+	return func(instance string) (endpoint.Endpoint, io.Closer, error) {
+		c := client.NewClient(scheme, instance, http.DefaultClient, enc, dec, false)
+		e := kithttp.NewExplicitClient(
+			func(ctx context.Context, v interface{}) (*http.Request, error) {
+				req, err := c.BuildSimpleMethodRequest(ctx, v)
+				if err != nil {
+					return nil, err
+				}
+				return req, nil
+			},
+			DecodeSimpleMethodResponse(dec),
+			options...,
+		)
+		return e.Endpoint(), nil, nil
+	}
+}
+`
+
+var WithPayloadMethodSDFactoryCode = `// WithPayloadMethodFactory returns a go-kit sd.Factory that creates endpoints
+// making requests to the WithPayloadService WithPayloadMethod server running
+// on the given instance. The instance must be the server host optionally
+// followed by the port, e.g. "localhost:8080".
+func WithPayloadMethodFactory(scheme string, enc func(*http.Request) goahttp.Encoder, dec func(*http.Response) goahttp.Decoder, options ...kithttp.ClientOption) sd.Factory {
+	return func(instance string) (endpoint.Endpoint, io.Closer, error) {
+		c := client.NewClient(scheme, instance, http.DefaultClient, enc, dec, false)
+		e := kithttp.NewExplicitClient(
+			func(ctx context.Context, v interface{}) (*http.Request, error) {
+				req, err := c.BuildWithPayloadMethodRequest(ctx, v)
+				if err != nil {
+					return nil, err
+				}
+				if err := EncodeWithPayloadMethodRequest(enc)(ctx, req, v); err != nil {
+					return nil, err
+				}
+				return req, nil
+			},
+			DecodeWithPayloadMethodResponse(dec),
+			options...,
+		)
+		return e.Endpoint(), nil, nil
+	}
+}
+`
+
+var SimpleMethodBalancedEndpointCode = `// BalancedSimpleMethodEndpoint returns an endpoint that load balances the
+// requests made to the SimpleService SimpleMethod server across the instances
+// published by instancer using a round robin strategy. factory creates the
+// endpoint used to make requests to each instance, see SimpleMethodFactory.
+// Failed requests are retried on the next instance up to retryMax times in
+// addition to the first request whatever the error as the design defines no
+// retry policy.
+// The requests fail if they do not complete within timeout. The endpoint
+// returns the error of the last request, not a lb.RetryError.
+func BalancedSimpleMethodEndpoint(instancer sd.Instancer, factory sd.Factory, logger log.Logger, retryMax int, timeout time.Duration) endpoint.Endpoint {
+	endpointer := sd.NewEndpointer(instancer, factory, logger)
+	balancer := lb.NewRoundRobin(endpointer)
+	retry := lb.RetryWithCallback(timeout, balancer, func(n int, err error) (bool, error) {
+		// n counts the requests made so far, including the first one.
+		return n <= retryMax, nil
+	})
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		res, err := retry(ctx, request)
+		if rerr, ok := err.(lb.RetryError); ok {
+			return nil, rerr.Final
+		}
+		return res, err
+	}
+}
+`
+
+var PolicyMethodSDFactoryCode = `// PolicyMethodFactory returns a go-kit sd.Factory that creates endpoints
+// making requests to the PolicyService PolicyMethod server running on the
+// given instance. The instance must be the server host optionally followed by
+// the port, e.g. "localhost:8080".
+// The endpoints cancel the requests after the timeout defined in the design.
+// They do not retry failed requests, see BalancedPolicyMethodEndpoint.
+func PolicyMethodFactory(scheme string, enc func(*http.Request) goahttp.Encoder, dec func(*http.Response) goahttp.Decoder, options ...kithttp.ClientOption) sd.Factory {
+	return func(instance string) (endpoint.Endpoint, io.Closer, error) {
+		c := client.NewClient(scheme, instance, http.DefaultClient, enc, dec, false)
+		e := kithttp.NewExplicitClient(
+			func(ctx context.Context, v interface{}) (*http.Request, error) {
+				req, err := c.BuildPolicyMethodRequest(ctx, v)
+				if err != nil {
+					return nil, err
+				}
+				return req, nil
+			},
+			DecodePolicyMethodResponse(dec),
+			options...,
+		)
+		return TimeoutPolicyMethodEndpoint()(e.Endpoint()), nil, nil
+	}
+}
+`
+
+var PolicyMethodBalancedEndpointCode = `// BalancedPolicyMethodEndpoint returns an endpoint that load balances the
+// requests made to the PolicyService PolicyMethod server across the instances
+// published by instancer using a round robin strategy. factory creates the
+// endpoint used to make requests to each instance, see PolicyMethodFactory.
+// Failed requests are retried on the next instance up to 3 times in addition
+// to the first request if the error is marked as temporary or timeout in the
+// design, see RetryablePolicyMethodError.
+// The requests fail if they do not complete within timeout. The endpoint
+// returns the error of the last request, not a lb.RetryError.
+func BalancedPolicyMethodEndpoint(instancer sd.Instancer, factory sd.Factory, logger log.Logger, timeout time.Duration) endpoint.Endpoint {
+	endpointer := sd.NewEndpointer(instancer, factory, logger)
+	balancer := lb.NewRoundRobin(endpointer)
+	retry := lb.RetryWithCallback(timeout, balancer, func(n int, err error) (bool, error) {
+		// n counts the requests made so far, including the first one.
+		return n <= 3 && RetryablePolicyMethodError(err), nil
+	})
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		res, err := retry(ctx, request)
+		if rerr, ok := err.(lb.RetryError); ok {
+			return nil, rerr.Final
+		}
+		return res, err
+	}
+}
+`
+
+var NoPolicyMethodSDFactoryCode = `// NoPolicyMethodFactory returns a go-kit sd.Factory that creates endpoints
+// making requests to the PolicyService NoPolicyMethod server running on the
+// given instance. The instance must be the server host optionally followed by
+// the port, e.g. "localhost:8080".
+func NoPolicyMethodFactory(scheme string, enc func(*http.Request) goahttp.Encoder, dec func(*http.Response) goahttp.Decoder, options ...kithttp.ClientOption) sd.Factory {
+	return func(instance string) (endpoint.Endpoint, io.Closer, error) {
+		c := client.NewClient(scheme, instance, http.DefaultClient, enc, dec, false)
+		e := kithttp.NewExplicitClient(
+			func(ctx context.Context, v interface{}) (*http.Request, error) {
+				req, err := c.BuildNoPolicyMethodRequest(ctx, v)
+				if err != nil {
+					return nil, err
+				}
+				return req, nil
+			},
+			DecodeNoPolicyMethodResponse(dec),
+			options...,
+		)
+		return e.Endpoint(), nil, nil
+	}
+}
+`
+
+var NoPolicyMethodBalancedEndpointCode = `// BalancedNoPolicyMethodEndpoint returns an endpoint that load balances the
+// requests made to the PolicyService NoPolicyMethod server across the
+// instances published by instancer using a round robin strategy. factory
+// creates the endpoint used to make requests to each instance, see
+// NoPolicyMethodFactory.
+// Failed requests are retried on the next instance up to retryMax times in
+// addition to the first request whatever the error as the design defines no
+// retry policy.
+// The requests fail if they do not complete within timeout. The endpoint
+// returns the error of the last request, not a lb.RetryError.
+func BalancedNoPolicyMethodEndpoint(instancer sd.Instancer, factory sd.Factory, logger log.Logger, retryMax int, timeout time.Duration) endpoint.Endpoint {
+	endpointer := sd.NewEndpointer(instancer, factory, logger)
+	balancer := lb.NewRoundRobin(endpointer)
+	retry := lb.RetryWithCallback(timeout, balancer, func(n int, err error) (bool, error) {
+		// n counts the requests made so far, including the first one.
+		return n <= retryMax, nil
+	})
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		res, err := retry(ctx, request)
+		if rerr, ok := err.(lb.RetryError); ok {
+			return nil, rerr.Final
+		}
+		return res, err
+	}
+}
+`
+
+var MultipartMethodGoakitRequestDecoderCode = `// DecodeMultipartMethodRequest returns a go-kit DecodeRequestFunc suitable for
+// decoding MultipartService MultipartMethod requests.
+func DecodeMultipartMethodRequest(mux goahttp.Muxer, multipartServiceMultipartMethodDecoderFn server.MultipartServiceMultipartMethodDecoderFunc) kithttp.DecodeRequestFunc {
+	dec := server.DecodeMultipartMethodRequest(mux, server.NewMultipartServiceMultipartMethodDecoder(mux, multipartServiceMultipartMethodDecoderFn))
+	return func(ctx context.Context, r *http.Request) (interface{}, error) {
+		r = r.WithContext(ctx)
+		return dec(r)
+	}
+}
+`
+
+var MultipartMethodGoakitRequestEncoderCode = `// EncodeMultipartMethodRequest returns a go-kit EncodeRequestFunc suitable for
+// encoding MultipartService MultipartMethod requests.
+func EncodeMultipartMethodRequest(multipartServiceMultipartMethodEncoderFn client.MultipartServiceMultipartMethodEncoderFunc) kithttp.EncodeRequestFunc {
+	enc := client.EncodeMultipartMethodRequest(client.NewMultipartServiceMultipartMethodEncoder(multipartServiceMultipartMethodEncoderFn))
+	return func(_ context.Context, r *http.Request, v interface{}) error {
+		return enc(r, v)
+	}
+}
+`
+
+var NonStreamingMethodGoakitResponseEncoderCode = `// EncodeNonStreamingMethodResponse returns a go-kit EncodeResponseFunc
+// suitable for encoding StreamingService NonStreamingMethod responses.
+func EncodeNonStreamingMethodResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) kithttp.EncodeResponseFunc {
+	return server.EncodeNonStreamingMethodResponse(encoder)
+}
+`
+
+var NonStreamingMethodGoakitMountCode = `// MountNonStreamingMethodHandler configures the mux to serve the
+// "StreamingService" service "NonStreamingMethod" endpoint.
+func MountNonStreamingMethodHandler(mux goahttp.Muxer, h http.Handler) {
+	f, ok := h.(http.HandlerFunc)
+	if !ok {
+		f = func(w http.ResponseWriter, r *http.Request) {
+			h.ServeHTTP(w, r)
+		}
+	}
+	mux.Handle("GET", "/", f)
+}
+`
+
+var MultipartServerInitCode = `func example() {
+	// Wrap the endpoints with the transport specific layers. The generated
+	// server packages contains code generated from the design which maps
+	// the service input and output data structures to HTTP requests and
+	// responses.
+	var (
+		multipartServiceMultipartMethodHandler *kithttp.Server
+		multipartServiceServer                 *multipartservicesvr.Server
+	)
+	{
+		eh := errorHandler(logger)
+		multipartServiceMultipartMethodHandler = kithttp.NewServer(
+			multipartservicekitsvr.LogMultipartMethodEndpoint(logger)(endpoint.Endpoint(multipartServiceEndpoints.MultipartMethod)),
+			multipartservicekitsvr.DecodeMultipartMethodRequest(mux, api.MultipartServiceMultipartMethodDecoderFunc),
+			multipartservicekitsvr.EncodeMultipartMethodResponse(enc),
+			multipartservicekitsvr.ServerOptions()...,
+		)
+		multipartServiceServer = multipartservicesvr.New(multipartServiceEndpoints, mux, dec, enc, eh, api.MultipartServiceMultipartMethodDecoderFunc)
+	}
+
+	// Configure the mux.
+	multipartservicekitsvr.MountMultipartMethodHandler(mux, multipartServiceMultipartMethodHandler)
+}
+`
+
+var StreamingServerInitCode = `func example() {
+	// Wrap the endpoints with the transport specific layers. The generated
+	// server packages contains code generated from the design which maps
+	// the service input and output data structures to HTTP requests and
+	// responses.
+	var (
+		streamingServiceNonStreamingMethodHandler *kithttp.Server
+		streamingServiceServer                    *streamingservicesvr.Server
+	)
+	{
+		eh := errorHandler(logger)
+		upgrader := &websocket.Upgrader{}
+		streamingServiceNonStreamingMethodHandler = kithttp.NewServer(
+			streamingservicekitsvr.LogNonStreamingMethodEndpoint(logger)(endpoint.Endpoint(streamingServiceEndpoints.NonStreamingMethod)),
+			func(context.Context, *http.Request) (request interface{}, err error) { return nil, nil },
+			streamingservicekitsvr.EncodeNonStreamingMethodResponse(enc),
+			streamingservicekitsvr.ServerOptions()...,
+		)
+		streamingServiceServer = streamingservicesvr.New(streamingServiceEndpoints, mux, dec, enc, eh, upgrader, nil)
+	}
+
+	// Configure the mux.
+	streamingservicesvr.MountStreamingMethodHandler(mux, streamingServiceServer.StreamingMethod)
+	streamingservicekitsvr.MountNonStreamingMethodHandler(mux, streamingServiceNonStreamingMethodHandler)
+}
+`
+
+var NATSMethodSubscriberCode = `// DecodeNATSMethodRequest is a go-kit NATS DecodeRequestFunc that decodes JSON
+// encoded NATSService NATSMethod payloads.
+func DecodeNATSMethodRequest(_ context.Context, msg *nats.Msg) (interface{}, error) {
+	var body NATSMethodRequestBody
+	if err := json.Unmarshal(msg.Data, &body); err != nil {
+		return nil, err
+	}
+	return NewNATSMethodPayload(&body), nil
+}
+
+// NewNATSMethodSubscriber returns a go-kit NATS subscriber that serves
+// NATSService NATSMethod requests using the given endpoint. The endpoint
+// results are encoded using JSON.
+func NewNATSMethodSubscriber(e endpoint.Endpoint, options ...kitnats.SubscriberOption) *kitnats.Subscriber {
+	options = append([]kitnats.SubscriberOption{kitnats.SubscriberErrorEncoder(EncodeError)}, options...)
+	return kitnats.NewSubscriber(e, DecodeNATSMethodRequest, kitnats.EncodeJSONResponse, options...)
+}
+`
+
+var NATSSubscribeCode = `// Subscribe subscribes the NATSService service subscribers to their NATS
+// subjects on the given connection. The subscribers join the "NATSService"
+// queue group so that requests are load balanced across the service instances.
+func Subscribe(nc *nats.Conn, endpoints *natsservice.Endpoints, options ...kitnats.SubscriberOption) ([]*nats.Subscription, error) {
+	var (
+		subs []*nats.Subscription
+		sub  *nats.Subscription
+		err  error
+	)
+	sub, err = nc.QueueSubscribe("nats.NATSMethod", "NATSService", NewNATSMethodSubscriber(endpoints.NATSMethod, options...).ServeMsg(nc))
+	if err != nil {
+		return subs, err
+	}
+	subs = append(subs, sub)
+	sub, err = nc.QueueSubscribe("nats.override", "NATSService", NewNATSOverrideMethodSubscriber(endpoints.NATSOverrideMethod, options...).ServeMsg(nc))
+	if err != nil {
+		return subs, err
+	}
+	subs = append(subs, sub)
+	return subs, nil
+}
+`
+
+var NATSMethodPublisherCode = `// EncodeNATSMethodRequest is a go-kit NATS EncodeRequestFunc that encodes
+// NATSService NATSMethod payloads using JSON.
+func EncodeNATSMethodRequest(ctx context.Context, msg *nats.Msg, request interface{}) error {
+	return kitnats.EncodeJSONRequest(ctx, msg, NewNATSMethodRequestBody(request.(*natsservice.NATSMethodPayload)))
+}
+
+// DecodeNATSMethodResponse is a go-kit NATS DecodeResponseFunc that decodes
+// JSON encoded NATSService NATSMethod results.
+func DecodeNATSMethodResponse(_ context.Context, msg *nats.Msg) (interface{}, error) {
+	if err := decodeError(msg.Data); err != nil {
+		return nil, err
+	}
+	var res string
+	if err := json.Unmarshal(msg.Data, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// NewNATSMethodPublisher returns a go-kit NATS publisher that makes
+// NATSService NATSMethod requests on the given connection. The publisher
+// encodes the payloads using JSON.
+func NewNATSMethodPublisher(nc *nats.Conn, options ...kitnats.PublisherOption) *kitnats.Publisher {
+	return kitnats.NewPublisher(nc, "nats.NATSMethod", EncodeNATSMethodRequest, DecodeNATSMethodResponse, options...)
+}
+`
+
+var NATSOverrideMethodPublisherCode = `// DecodeNATSOverrideMethodResponse is a go-kit NATS DecodeResponseFunc that
+// decodes JSON encoded NATSService NATSOverrideMethod results.
+func DecodeNATSOverrideMethodResponse(_ context.Context, msg *nats.Msg) (interface{}, error) {
+	if err := decodeError(msg.Data); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// NewNATSOverrideMethodPublisher returns a go-kit NATS publisher that makes
+// NATSService NATSOverrideMethod requests on the given connection. The
+// publisher encodes the payloads using JSON.
+func NewNATSOverrideMethodPublisher(nc *nats.Conn, options ...kitnats.PublisherOption) *kitnats.Publisher {
+	return kitnats.NewPublisher(nc, "nats.override", kitnats.EncodeJSONRequest, DecodeNATSOverrideMethodResponse, options...)
+}
+`
+
+var JSONRPCServerCode = `// NewServer returns a go-kit JSON-RPC server that serves the JSONRPCService
+// service methods using the given endpoints.
+func NewServer(endpoints *jsonrpcservice.Endpoints, options ...jsonrpc.ServerOption) *jsonrpc.Server {
+	return jsonrpc.NewServer(EndpointCodecMap(endpoints), options...)
+}
+
+// EndpointCodecMap returns the go-kit JSON-RPC endpoint codecs of the
+// JSONRPCService service methods indexed by JSON-RPC method name.
+func EndpointCodecMap(endpoints *jsonrpcservice.Endpoints) jsonrpc.EndpointCodecMap {
+	return jsonrpc.EndpointCodecMap{
+		"JSONRPCMethod": jsonrpc.EndpointCodec{
+			Endpoint: endpoints.JSONRPCMethod,
+			Decode:   DecodeJSONRPCMethodRequest,
+			Encode:   EncodeJSONRPCMethodResponse,
+		},
+	}
+}
+`
+
+var JSONRPCMethodServerCodecCode = `// DecodeJSONRPCMethodRequest is a go-kit JSON-RPC DecodeRequestFunc that
+// decodes JSONRPCService JSONRPCMethod payloads.
+func DecodeJSONRPCMethodRequest(_ context.Context, msg json.RawMessage) (interface{}, error) {
+	var payload *jsonrpcservice.JSONRPCMethodPayload
+	if err := json.Unmarshal(msg, &payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// EncodeJSONRPCMethodResponse is a go-kit JSON-RPC EncodeResponseFunc that
+// encodes JSONRPCService JSONRPCMethod results.
+func EncodeJSONRPCMethodResponse(_ context.Context, res interface{}) (json.RawMessage, error) {
+	return json.Marshal(res)
+}
+`
+
+var JSONRPCMethodClientCode = `// DecodeJSONRPCMethodResponse is a go-kit JSON-RPC DecodeResponseFunc that
+// decodes JSONRPCService JSONRPCMethod results.
+func DecodeJSONRPCMethodResponse(_ context.Context, resp jsonrpc.Response) (interface{}, error) {
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	var res string
+	if err := json.Unmarshal(resp.Result, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// NewJSONRPCMethodEndpoint returns an endpoint that makes JSON-RPC requests to
+// the JSONRPCService JSONRPCMethod method served at the given URL.
+func NewJSONRPCMethodEndpoint(tgt *url.URL, options ...jsonrpc.ClientOption) endpoint.Endpoint {
+	options = append([]jsonrpc.ClientOption{jsonrpc.ClientResponseDecoder(DecodeJSONRPCMethodResponse)}, options...)
+	return jsonrpc.NewClient(tgt, "JSONRPCMethod", options...).Endpoint()
+}
+`
+
+var NATSOverrideMethodSubscriberCode = `// DecodeNATSOverrideMethodRequest is a go-kit NATS DecodeRequestFunc that
+// decodes JSON encoded NATSService NATSOverrideMethod payloads.
+func DecodeNATSOverrideMethodRequest(_ context.Context, msg *nats.Msg) (interface{}, error) {
+	return nil, nil
+}
+
+// NewNATSOverrideMethodSubscriber returns a go-kit NATS subscriber that serves
+// NATSService NATSOverrideMethod requests using the given endpoint. The
+// endpoint results are encoded using JSON.
+func NewNATSOverrideMethodSubscriber(e endpoint.Endpoint, options ...kitnats.SubscriberOption) *kitnats.Subscriber {
+	options = append([]kitnats.SubscriberOption{kitnats.SubscriberErrorEncoder(EncodeError)}, options...)
+	return kitnats.NewSubscriber(e, DecodeNATSOverrideMethodRequest, kitnats.EncodeJSONResponse, options...)
+}
+`
+
+var NATSErrorEncoderCode = `// errorReply is the JSON representation of the errors returned by the
+// subscribers. The "goakit_error" key distinguishes the errors from the
+// results, the "err" key is the one used by the go-kit default error encoder
+// and the other keys describe the goa service errors.
+type errorReply struct {
+	IsError   bool   ` + "`" + `json:"goakit_error"` + "`" + `
+	Error     string ` + "`" + `json:"err"` + "`" + `
+	Name      string ` + "`" + `json:"name,omitempty"` + "`" + `
+	ID        string ` + "`" + `json:"id,omitempty"` + "`" + `
+	Temporary bool   ` + "`" + `json:"temporary,omitempty"` + "`" + `
+	Timeout   bool   ` + "`" + `json:"timeout,omitempty"` + "`" + `
+	Fault     bool   ` + "`" + `json:"fault,omitempty"` + "`" + `
+}
+
+// EncodeError is a go-kit NATS ErrorEncoder that publishes the errors returned
+// by the endpoints to the reply subject using JSON. It keeps the name and
+// flags of the goa service errors so that the publishers can rebuild them.
+func EncodeError(_ context.Context, err error, reply string, nc *nats.Conn) {
+	e := errorReply{IsError: true, Error: err.Error()}
+	if serr, ok := err.(*goa.ServiceError); ok {
+		e.Error = serr.Message
+		e.Name = serr.Name
+		e.ID = serr.ID
+		e.Temporary = serr.Temporary
+		e.Timeout = serr.Timeout
+		e.Fault = serr.Fault
+	}
+	b, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	nc.Publish(reply, b)
+}
+`
+
+var NATSErrorDecoderCode = `// errorReply is the JSON representation of the errors published by the
+// service subscribers, see the server EncodeError function.
+type errorReply struct {
+	IsError   bool   ` + "`" + `json:"goakit_error"` + "`" + `
+	Error     string ` + "`" + `json:"err"` + "`" + `
+	Name      string ` + "`" + `json:"name"` + "`" + `
+	ID        string ` + "`" + `json:"id"` + "`" + `
+	Temporary bool   ` + "`" + `json:"temporary"` + "`" + `
+	Timeout   bool   ` + "`" + `json:"timeout"` + "`" + `
+	Fault     bool   ` + "`" + `json:"fault"` + "`" + `
+}
+
+// decodeError returns the error held by the given reply, nil if the reply is
+// not an error. The replies are errors if they set the "goakit_error" key so
+// that errors with an empty message are not mistaken for results. The errors
+// that carry a name are decoded into goa service errors.
+func decodeError(data []byte) error {
+	var e errorReply
+	if err := json.Unmarshal(data, &e); err != nil || !e.IsError {
+		return nil
+	}
+	if e.Name == "" {
+		return errors.New(e.Error)
+	}
+	return &goa.ServiceError{
+		Name:      e.Name,
+		ID:        e.ID,
+		Message:   e.Error,
+		Temporary: e.Temporary,
+		Timeout:   e.Timeout,
+		Fault:     e.Fault,
+	}
+}
+`
+
+var NATSValidationMethodSubscriberCode = `// DecodeNATSValidationMethodRequest is a go-kit NATS DecodeRequestFunc that
+// decodes JSON encoded NATSValidationService NATSValidationMethod payloads.
+func DecodeNATSValidationMethodRequest(_ context.Context, msg *nats.Msg) (interface{}, error) {
+	var body NATSValidationMethodRequestBody
+	if err := json.Unmarshal(msg.Data, &body); err != nil {
+		return nil, err
+	}
+	if err := ValidateNATSValidationMethodRequestBody(&body); err != nil {
+		return nil, err
+	}
+	return NewNATSValidationMethodPayload(&body), nil
+}
+
+// NewNATSValidationMethodSubscriber returns a go-kit NATS subscriber that
+// serves NATSValidationService NATSValidationMethod requests using the given
+// endpoint. The endpoint results are encoded using JSON.
+func NewNATSValidationMethodSubscriber(e endpoint.Endpoint, options ...kitnats.SubscriberOption) *kitnats.Subscriber {
+	options = append([]kitnats.SubscriberOption{kitnats.SubscriberErrorEncoder(EncodeError)}, options...)
+	return kitnats.NewSubscriber(e, DecodeNATSValidationMethodRequest, kitnats.EncodeJSONResponse, options...)
+}
+`
+
+var JSONRPCValidationMethodServerCodecCode = `// DecodeJSONRPCValidationMethodRequest is a go-kit JSON-RPC DecodeRequestFunc
+// that decodes JSONRPCValidationService JSONRPCValidationMethod payloads.
+func DecodeJSONRPCValidationMethodRequest(_ context.Context, msg json.RawMessage) (interface{}, error) {
+	var payload *jsonrpcvalidationservice.JSONRPCValidationMethodPayload
+	if err := json.Unmarshal(msg, &payload); err != nil {
+		return nil, err
+	}
+	if payload != nil {
+		if err := kitendpoint.ValidateJSONRPCValidationMethodPayload(payload); err != nil {
+			return nil, err
+		}
+	}
+	return payload, nil
+}
+
+// EncodeJSONRPCValidationMethodResponse is a go-kit JSON-RPC
+// EncodeResponseFunc that encodes JSONRPCValidationService
+// JSONRPCValidationMethod results.
+func EncodeJSONRPCValidationMethodResponse(_ context.Context, res interface{}) (json.RawMessage, error) {
+	return json.Marshal(res)
+}
+`
+
+var SimpleServiceServerOptionsCode = `// ServerOptions returns the go-kit HTTP server options used to create the
+// SimpleService service handlers. The default options populate the request
+// context with the HTTP request details (see kithttp.PopulateRequestContext)
+// and the request ID. extra is appended to the default options.
+func ServerOptions(extra ...kithttp.ServerOption) []kithttp.ServerOption {
+	return append([]kithttp.ServerOption{
+		kithttp.ServerBefore(kithttp.PopulateRequestContext),
+		kithttp.ServerBefore(RequestIDToContext),
+	}, extra...)
+}
+
+// RequestIDToContext is a go-kit RequestFunc that stores the ID read from the
+// X-Request-Id request header in the context under the goa request ID key
+// unless the context already contains one.
+func RequestIDToContext(ctx context.Context, r *http.Request) context.Context {
+	if _, ok := ctx.Value(middleware.RequestIDKey).(string); ok {
+		return ctx
+	}
+	if id := r.Header.Get("X-Request-Id"); id != "" {
+		return context.WithValue(ctx, middleware.RequestIDKey, id)
+	}
+	return ctx
+}
+`
+
+var SimpleServiceClientOptionsCode = `// ClientOptions returns the go-kit HTTP client options used to make requests
+// to the SimpleService service. The default options propagate the request ID
+// stored in the context. extra is appended to the default options.
+func ClientOptions(extra ...kithttp.ClientOption) []kithttp.ClientOption {
+	return append([]kithttp.ClientOption{
+		kithttp.ClientBefore(RequestIDToHTTP),
+	}, extra...)
+}
+
+// RequestIDToHTTP is a go-kit RequestFunc that sets the X-Request-Id request
+// header with the request ID stored in the context under the goa request ID
+// key if any.
+func RequestIDToHTTP(ctx context.Context, r *http.Request) context.Context {
+	if id, ok := ctx.Value(middleware.RequestIDKey).(string); ok {
+		r.Header.Set("X-Request-Id", id)
+	}
+	return ctx
+}
+`
+
+var SimpleMethodEndpointLoggingCode = `// LogSimpleMethodEndpoint returns an endpoint middleware that logs the
+// SimpleService SimpleMethod requests. The log entries contain the method
+// name, the time it took to process the request and the error if any. Failed
+// requests are logged at the error level, successful requests at the info
+// level.
+func LogSimpleMethodEndpoint(logger log.Logger) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+			defer func(begin time.Time) {
+				l := level.Info(logger)
+				if err != nil {
+					l = level.Error(logger)
+				}
+				l.Log("method", "SimpleService.SimpleMethod", "took", time.Since(begin), "err", err)
+			}(time.Now())
+			return next(ctx, request)
+		}
+	}
+}
+`
+
+var NonStreamingMethodEndpointLoggingCode = `// LogNonStreamingMethodEndpoint returns an endpoint middleware that logs the
+// StreamingService NonStreamingMethod requests. The log entries contain the
+// method name, the time it took to process the request and the error if any.
+// Failed requests are logged at the error level, successful requests at the
+// info level.
+func LogNonStreamingMethodEndpoint(logger log.Logger) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+			defer func(begin time.Time) {
+				l := level.Info(logger)
+				if err != nil {
+					l = level.Error(logger)
+				}
+				l.Log("method", "StreamingService.NonStreamingMethod", "took", time.Since(begin), "err", err)
+			}(time.Now())
+			return next(ctx, request)
+		}
+	}
+}
+`
+
+var MultiEndpointClientStructCode = `// Client lists the MultiEndpointService service endpoints that make HTTP
+// requests using go-kit clients.
+type Client struct {
+	c       *client.Client
+	enc     func(*http.Request) goahttp.Encoder
+	dec     func(*http.Response) goahttp.Decoder
+	options []kithttp.ClientOption
+}
+
+// NewClient instantiates go-kit HTTP clients for all the MultiEndpointService
+// service servers. The arguments are the same as the goa client ones, doer is
+// used to make the HTTP requests and options are appended to the default
+// options returned by ClientOptions.
+func NewClient(
+	scheme string,
+	host string,
+	doer goahttp.Doer,
+	enc func(*http.Request) goahttp.Encoder,
+	dec func(*http.Response) goahttp.Decoder,
+	restoreBody bool,
+	options ...kithttp.ClientOption,
+) *Client {
+	return &Client{
+		c:       client.NewClient(scheme, host, doer, enc, dec, restoreBody),
+		enc:     enc,
+		dec:     dec,
+		options: ClientOptions(append([]kithttp.ClientOption{kithttp.SetClient(doer)}, options...)...),
+	}
+}
+`
+
+var Endpoint1ClientMethodCode = `// Endpoint1 returns an endpoint that makes HTTP requests to the
+// MultiEndpointService service Endpoint1 server using a go-kit client.
+func (c *Client) Endpoint1() endpoint.Endpoint {
+	return kithttp.NewExplicitClient(
+		func(ctx context.Context, v interface{}) (*http.Request, error) {
+			req, err := c.c.BuildEndpoint1Request(ctx, v)
+			if err != nil {
+				return nil, err
+			}
+			if err := EncodeEndpoint1Request(c.enc)(ctx, req, v); err != nil {
+				return nil, err
+			}
+			return req, nil
+		},
+		DecodeEndpoint1Response(c.dec),
+		c.options...,
+	).Endpoint()
+}
+`
+
+var Endpoint2ClientMethodCode = `// Endpoint2 returns an endpoint that makes HTTP requests to the
+// MultiEndpointService service Endpoint2 server using a go-kit client.
+func (c *Client) Endpoint2() endpoint.Endpoint {
+	return kithttp.NewExplicitClient(
+		func(ctx context.Context, v interface{}) (*http.Request, error) {
+			req, err := c.c.BuildEndpoint2Request(ctx, v)
+			if err != nil {
+				return nil, err
+			}
+			return req, nil
+		},
+		DecodeEndpoint2Response(c.dec),
+		c.options...,
+	).Endpoint()
+}
+`
+
+var TransportMethodTransportTestCode = `// TestTransportMethodTransport makes sure that the go-kit server and client of
+// the TransportService TransportMethod endpoint round trip the design examples
+// and that the go-kit server encodes the responses like the goa server.
+func TestTransportMethodTransport(t *testing.T) {
+	var (
+		payload  interface{} = &transportservice.TransportMethodPayload{A: int(1), B: []string{"b"}}
+		result   interface{} = &transportservice.TransportMethodResult{C: "c"}
+		received interface{}
+	)
+	mux := goahttp.NewMuxer()
+	MountTransportMethodHandler(mux, kithttp.NewServer(
+		func(_ context.Context, request interface{}) (interface{}, error) {
+			received = request
+			return result, nil
+		},
+		DecodeTransportMethodRequest(mux, goahttp.RequestDecoder),
+		EncodeTransportMethodResponse(goahttp.ResponseEncoder),
+	))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := kitclient.NewClient(u.Scheme, u.Host, http.DefaultClient, goahttp.RequestEncoder, goahttp.ResponseDecoder, false)
+	res, err := c.TransportMethod()(context.Background(), payload)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(received, payload) {
+		t.Errorf("invalid decoded payload, got %#v, expected %#v", received, payload)
+	}
+	if !reflect.DeepEqual(res, result) {
+		t.Errorf("invalid decoded result, got %#v, expected %#v", res, result)
+	}
+
+	kitw, goaw := httptest.NewRecorder(), httptest.NewRecorder()
+	if err := EncodeTransportMethodResponse(goahttp.ResponseEncoder)(context.Background(), kitw, result); err != nil {
+		t.Fatalf("go-kit encoder error: %v", err)
+	}
+	if err := goaserver.EncodeTransportMethodResponse(goahttp.ResponseEncoder)(context.Background(), goaw, result); err != nil {
+		t.Fatalf("goa encoder error: %v", err)
+	}
+	if kitw.Code != goaw.Code || kitw.Body.String() != goaw.Body.String() {
+		t.Errorf("invalid response, got %d %q, expected %d %q", kitw.Code, kitw.Body.String(), goaw.Code, goaw.Body.String())
+	}
+}
+
+// TestTransportMethodTransportBadRequest makes sure that the go-kit server and
+// client of the TransportService TransportMethod endpoint round trip the
+// "bad_request" error.
+func TestTransportMethodTransportBadRequest(t *testing.T) {
+	var payload interface{} = &transportservice.TransportMethodPayload{A: int(1), B: []string{"b"}}
+	mux := goahttp.NewMuxer()
+	MountTransportMethodHandler(mux, kithttp.NewServer(
+		func(context.Context, interface{}) (interface{}, error) {
+			return nil, transportservice.MakeBadRequest(fmt.Errorf("test error"))
+		},
+		DecodeTransportMethodRequest(mux, goahttp.RequestDecoder),
+		EncodeTransportMethodResponse(goahttp.ResponseEncoder),
+		kithttp.ServerErrorEncoder(EncodeTransportMethodError(goahttp.ResponseEncoder)),
+	))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := kitclient.NewClient(u.Scheme, u.Host, http.DefaultClient, goahttp.RequestEncoder, goahttp.ResponseDecoder, false)
+	_, err = c.TransportMethod()(context.Background(), payload)
+	serr, ok := err.(*goa.ServiceError)
+	if !ok {
+		t.Fatalf("got error %#v, expected a *goa.ServiceError", err)
+	}
+	if serr.Name != "bad_request" {
+		t.Errorf("got error %q, expected %q", serr.Name, "bad_request")
+	}
+}
+`
+
+var SimpleMethodTransportTestCode = `// TestSimpleMethodTransport makes sure that the go-kit server and client of
+// the SimpleService SimpleMethod endpoint round trip the design examples and
+// that the go-kit server encodes the responses like the goa server.
+func TestSimpleMethodTransport(t *testing.T) {
+	var (
+		payload  interface{}
+		result   interface{}
+		received interface{}
+	)
+	mux := goahttp.NewMuxer()
+	MountSimpleMethodHandler(mux, kithttp.NewServer(
+		func(_ context.Context, request interface{}) (interface{}, error) {
+			received = request
+			return result, nil
+		},
+		func(context.Context, *http.Request) (interface{}, error) { return nil, nil },
+		EncodeSimpleMethodResponse(goahttp.ResponseEncoder),
+	))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := kitclient.NewClient(u.Scheme, u.Host, http.DefaultClient, goahttp.RequestEncoder, goahttp.ResponseDecoder, false)
+	res, err := c.SimpleMethod()(context.Background(), payload)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(received, payload) {
+		t.Errorf("invalid decoded payload, got %#v, expected %#v", received, payload)
+	}
+	if !reflect.DeepEqual(res, result) {
+		t.Errorf("invalid decoded result, got %#v, expected %#v", res, result)
+	}
+
+	kitw, goaw := httptest.NewRecorder(), httptest.NewRecorder()
+	if err := EncodeSimpleMethodResponse(goahttp.ResponseEncoder)(context.Background(), kitw, result); err != nil {
+		t.Fatalf("go-kit encoder error: %v", err)
+	}
+	if err := goaserver.EncodeSimpleMethodResponse(goahttp.ResponseEncoder)(context.Background(), goaw, result); err != nil {
+		t.Fatalf("goa encoder error: %v", err)
+	}
+	if kitw.Code != goaw.Code || kitw.Body.String() != goaw.Body.String() {
+		t.Errorf("invalid response, got %d %q, expected %d %q", kitw.Code, kitw.Body.String(), goaw.Code, goaw.Body.String())
+	}
+}
+`
+
+var TransportServiceEndpointSetCode = `// Set collects the go-kit endpoints of the TransportService service. Set
+// implements the service interface on top of the endpoints so that they can be
+// used anywhere the service is expected, e.g. to make requests using go-kit
+// clients.
+type Set struct {
+	TransportMethodEndpoint endpoint.Endpoint
+}
+
+// Set implements the service interface.
+var _ transportservice.Service = Set{}
+
+// NewSet returns the set of go-kit endpoints that call the methods of svc.
+func NewSet(svc transportservice.Service) Set {
+	return Set{
+		TransportMethodEndpoint: MakeTransportMethodEndpoint(svc),
+	}
+}
+`
+
+var TransportMethodMakeEndpointCode = `// MakeTransportMethodEndpoint returns a go-kit endpoint that calls the
+// TransportService service TransportMethod method.
+func MakeTransportMethodEndpoint(svc transportservice.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		p := request.(*transportservice.TransportMethodPayload)
+		return svc.TransportMethod(ctx, p)
+	}
+}
+`
+
+var TransportMethodEndpointSetMethodCode = `// TransportMethod calls the TransportMethod endpoint.
+func (s Set) TransportMethod(ctx context.Context, p *transportservice.TransportMethodPayload) (res *transportservice.TransportMethodResult, err error) {
+	var resp interface{}
+	resp, err = s.TransportMethodEndpoint(ctx, p)
+	if err != nil {
+		return
+	}
+	return resp.(*transportservice.TransportMethodResult), nil
+}
+`
+
+var SimpleMethodMakeEndpointCode = `// MakeSimpleMethodEndpoint returns a go-kit endpoint that calls the
+// SimpleService service SimpleMethod method.
+func MakeSimpleMethodEndpoint(svc simpleservice.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		return nil, svc.SimpleMethod(ctx)
+	}
+}
+`
+
+var SimpleMethodEndpointSetMethodCode = `// SimpleMethod calls the SimpleMethod endpoint.
+func (s Set) SimpleMethod(ctx context.Context) (err error) {
+	_, err = s.SimpleMethodEndpoint(ctx, nil)
+	return
+}
+`
+
+var ValidationMethodValidateEndpointCode = `// ValidateValidationMethodPayload runs the validations defined in the design
+// on the ValidationService ValidationMethod payload.
+func ValidateValidationMethodPayload(payload *validationservice.ValidationMethodPayload) (err error) {
+	if utf8.RuneCountInString(payload.Name) < 3 {
+		err = goa.MergeErrors(err, goa.InvalidLengthError("payload.name", payload.Name, utf8.RuneCountInString(payload.Name), 3, true))
+	}
+	return
+}
+
+// ValidateValidationMethodResult runs the validations defined in the design on
+// the ValidationService ValidationMethod result.
+func ValidateValidationMethodResult(result string) (err error) {
+	if utf8.RuneCountInString(result) < 1 {
+		err = goa.MergeErrors(err, goa.InvalidLengthError("result", result, utf8.RuneCountInString(result), 1, true))
+	}
+	return
+}
+
+// ValidateValidationMethodEndpoint returns an endpoint middleware that
+// validates the ValidationService ValidationMethod requests and optionally the
+// responses. The middleware enforces the design validations regardless of the
+// transport, e.g. when the endpoint is called in-process.
+func ValidateValidationMethodEndpoint(validateResult bool) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (interface{}, error) {
+			if payload, ok := request.(*validationservice.ValidationMethodPayload); ok && payload != nil {
+				if err := ValidateValidationMethodPayload(payload); err != nil {
+					return nil, err
+				}
+			}
+			response, err := next(ctx, request)
+			if err != nil || !validateResult {
+				return response, err
+			}
+			if result, ok := response.(string); ok {
+				if err := ValidateValidationMethodResult(result); err != nil {
+					return nil, err
+				}
+			}
+			return response, nil
+		}
+	}
+}
+`
+
+var NestedValidationMethodValidateEndpointCode = `// ValidateNestedValidationMethodPayload runs the validations defined in the
+// design on the NestedValidationService NestedValidationMethod payload.
+func ValidateNestedValidationMethodPayload(payload *nestedvalidationservice.NestedValidationMethodPayload) (err error) {
+	if payload.Item != nil {
+		if err2 := ValidateItem(payload.Item); err2 != nil {
+			err = goa.MergeErrors(err, err2)
+		}
+	}
+	for _, e := range payload.Items {
+		if e != nil {
+			if err2 := ValidateItem(e); err2 != nil {
+				err = goa.MergeErrors(err, err2)
+			}
+		}
+	}
+	return
+}
+
+// ValidateNestedValidationMethodEndpoint returns an endpoint middleware that
+// validates the NestedValidationService NestedValidationMethod requests. The
+// middleware enforces the design validations regardless of the transport, e.g.
+// when the endpoint is called in-process.
+func ValidateNestedValidationMethodEndpoint() endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (interface{}, error) {
+			if payload, ok := request.(*nestedvalidationservice.NestedValidationMethodPayload); ok && payload != nil {
+				if err := ValidateNestedValidationMethodPayload(payload); err != nil {
+					return nil, err
+				}
+			}
+			return next(ctx, request)
+		}
+	}
+}
+`
+
+var ItemValidateTypeCode = `// ValidateItem runs the validations defined in the design on the Item type.
+func ValidateItem(item *nestedvalidationservice.Item) (err error) {
+	if utf8.RuneCountInString(item.Name) < 3 {
+		err = goa.MergeErrors(err, goa.InvalidLengthError("item.name", item.Name, utf8.RuneCountInString(item.Name), 3, true))
+	}
+	return
+}
+`
+
+var HealthServiceCode = `// Service implements the health service. Liveness always succeeds while
+// readiness succeeds only if all the registered checkers do.
+type Service struct {
+	mu       sync.RWMutex
+	names    []string
+	checkers map[string]Checker
+}
+
+// Service implements the service interface.
+var _ health.Service = (*Service)(nil)
+
+// New returns a health service with no checker.
+func New() *Service {
+	return &Service{checkers: make(map[string]Checker)}
+}
+
+// Register registers a readiness checker under the given name. It replaces the
+// checker previously registered under the same name if any.
+func (s *Service) Register(name string, c Checker) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.checkers[name]; !ok {
+		s.names = append(s.names, name)
+	}
+	s.checkers[name] = c
+}
+
+// Liveness reports that the server is running.
+func (s *Service) Liveness(ctx context.Context) (*health.HealthStatus, error) {
+	return &health.HealthStatus{Status: "ok"}, nil
+}
+
+// Readiness runs the registered checkers concurrently. It returns a not_ready
+// error listing the checkers that failed if any.
+func (s *Service) Readiness(ctx context.Context) (*health.HealthStatus, error) {
+	s.mu.RLock()
+	names := make([]string, len(s.names))
+	checkers := make([]Checker, len(s.names))
+	for i, name := range s.names {
+		names[i] = name
+		checkers[i] = s.checkers[name]
+	}
+	s.mu.RUnlock()
+
+	errs := make([]error, len(checkers))
+	var wg sync.WaitGroup
+	for i, c := range checkers {
+		wg.Add(1)
+		go func(i int, c Checker) {
+			defer wg.Done()
+			errs[i] = c.Check(ctx)
+		}(i, c)
+	}
+	wg.Wait()
+
+	res := &health.HealthStatus{Status: "ok", Checks: make(map[string]string, len(names))}
+	var failed []string
+	for i, name := range names {
+		if errs[i] != nil {
+			res.Checks[name] = errs[i].Error()
+			failed = append(failed, fmt.Sprintf("%s: %s", name, errs[i]))
+			continue
+		}
+		res.Checks[name] = "ok"
+	}
+	if len(failed) > 0 {
+		return nil, health.MakeNotReady(errors.New(strings.Join(failed, "; ")))
+	}
+	return res, nil
+}
+`
+
+var PolicyMethodServerTimeoutCode = `// TimeoutPolicyMethodEndpoint returns an endpoint middleware that cancels the
+// context of the PolicyService PolicyMethod requests after 5s.
+func TimeoutPolicyMethodEndpoint() endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (interface{}, error) {
+			ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			defer cancel()
+			return next(ctx, request)
+		}
+	}
+}
+`
+
+var PolicyMethodClientTimeoutCode = `// TimeoutPolicyMethodEndpoint returns an endpoint middleware that cancels the
+// requests made to the PolicyService PolicyMethod server after 5s.
+func TimeoutPolicyMethodEndpoint() endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (interface{}, error) {
+			ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			defer cancel()
+			return next(ctx, request)
+		}
+	}
+}
+`
+
+var PolicyMethodClientRetryCode = `// RetryPolicyMethodEndpoint returns an endpoint middleware that retries the
+// requests made to the PolicyService PolicyMethod server up to 3 times,
+// waiting for 100ms before each retry. Only the errors marked as temporary or
+// timeout in the design are retried, see RetryablePolicyMethodError.
+func RetryPolicyMethodEndpoint() endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+			for i := 0; ; i++ {
+				response, err = next(ctx, request)
+				if err == nil || i == 3 || !RetryablePolicyMethodError(err) {
+					return
+				}
+				select {
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				case <-time.After(100 * time.Millisecond):
+				}
+			}
+		}
+	}
+}
+
+// RetryablePolicyMethodError returns true if err is one of the PolicyService
+// PolicyMethod errors marked as temporary or timeout in the design.
+func RetryablePolicyMethodError(err error) bool {
+	se, ok := err.(*goa.ServiceError)
+	if !ok {
+		return false
+	}
+	switch se.Name {
+	case "unavailable":
+		return true
+	}
+	return false
+}
+`
+
+var PolicyMethodClientMethodCode = `// PolicyMethod returns an endpoint that makes HTTP requests to the
+// PolicyService service PolicyMethod server using a go-kit client.
+// The endpoint applies the timeout and retry policies defined in the design.
+func (c *Client) PolicyMethod() endpoint.Endpoint {
+	e := kithttp.NewExplicitClient(
+		func(ctx context.Context, v interface{}) (*http.Request, error) {
+			req, err := c.c.BuildPolicyMethodRequest(ctx, v)
+			if err != nil {
+				return nil, err
+			}
+			return req, nil
+		},
+		DecodePolicyMethodResponse(c.dec),
+		c.options...,
+	).Endpoint()
+	e = TimeoutPolicyMethodEndpoint()(e)
+	e = RetryPolicyMethodEndpoint()(e)
+	return e
+}
+`
+
+var NoPolicyMethodClientMethodCode = `// NoPolicyMethod returns an endpoint that makes HTTP requests to the
+// PolicyService service NoPolicyMethod server using a go-kit client.
+func (c *Client) NoPolicyMethod() endpoint.Endpoint {
+	return kithttp.NewExplicitClient(
+		func(ctx context.Context, v interface{}) (*http.Request, error) {
+			req, err := c.c.BuildNoPolicyMethodRequest(ctx, v)
+			if err != nil {
+				return nil, err
+			}
+			return req, nil
+		},
+		DecodeNoPolicyMethodResponse(c.dec),
+		c.options...,
+	).Endpoint()
+}
+`
+
+var AMQPMethodSubscriberCode = `// DecodeAMQPMethodRequest is a go-kit AMQP DecodeRequestFunc that decodes JSON
+// encoded AMQPService AMQPMethod payloads.
+func DecodeAMQPMethodRequest(_ context.Context, deliv *amqp.Delivery) (interface{}, error) {
+	var body AMQPMethodRequestBody
+	if err := json.Unmarshal(deliv.Body, &body); err != nil {
+		return nil, err
+	}
+	if err := ValidateAMQPMethodRequestBody(&body); err != nil {
+		return nil, err
+	}
+	return NewAMQPMethodPayload(&body), nil
+}
+
+// NewAMQPMethodSubscriber returns a go-kit AMQP subscriber that serves
+// AMQPService AMQPMethod requests using the given endpoint. The requests are
+// fire-and-forget: the subscriber does not publish the endpoint results and
+// errors. The deliveries are acknowledged once served and rejected if the
+// request fails, see AckResponsePublisher and EncodeError.
+func NewAMQPMethodSubscriber(e endpoint.Endpoint, options ...kitamqp.SubscriberOption) *kitamqp.Subscriber {
+	return kitamqp.NewSubscriber(
+		e,
+		DecodeAMQPMethodRequest,
+		kitamqp.EncodeNopResponse,
+		append([]kitamqp.SubscriberOption{
+			kitamqp.SubscriberResponsePublisher(AckResponsePublisher),
+			kitamqp.SubscriberErrorEncoder(EncodeError),
+		}, options...)...,
+	)
+}
+`
+
+var AMQPNoPayloadMethodSubscriberCode = `// DecodeAMQPNoPayloadMethodRequest is a go-kit AMQP DecodeRequestFunc that
+// decodes JSON encoded AMQPService AMQPNoPayloadMethod payloads.
+func DecodeAMQPNoPayloadMethodRequest(_ context.Context, deliv *amqp.Delivery) (interface{}, error) {
+	return nil, nil
+}
+
+// NewAMQPNoPayloadMethodSubscriber returns a go-kit AMQP subscriber that
+// serves AMQPService AMQPNoPayloadMethod requests using the given endpoint.
+// The requests are fire-and-forget: the subscriber does not publish the
+// endpoint results and errors. The deliveries are acknowledged once served and
+// rejected if the request fails, see AckResponsePublisher and EncodeError.
+func NewAMQPNoPayloadMethodSubscriber(e endpoint.Endpoint, options ...kitamqp.SubscriberOption) *kitamqp.Subscriber {
+	return kitamqp.NewSubscriber(
+		e,
+		DecodeAMQPNoPayloadMethodRequest,
+		kitamqp.EncodeNopResponse,
+		append([]kitamqp.SubscriberOption{
+			kitamqp.SubscriberResponsePublisher(AckResponsePublisher),
+			kitamqp.SubscriberErrorEncoder(EncodeError),
+		}, options...)...,
+	)
+}
+`
+
+var AMQPAckCode = `// AckResponsePublisher is a go-kit AMQP ResponsePublisher that acknowledges
+// the deliveries served successfully. It does not publish the responses.
+func AckResponsePublisher(_ context.Context, deliv *amqp.Delivery, _ kitamqp.Channel, _ *amqp.Publishing) error {
+	return deliv.Ack(false)
+}
+
+// EncodeError is a go-kit AMQP ErrorEncoder that rejects the deliveries whose
+// request failed. The deliveries are requeued if the error is a goa service
+// error marked as temporary or timeout in the design and discarded (or dead
+// lettered if the queue is configured so) otherwise.
+func EncodeError(_ context.Context, err error, deliv *amqp.Delivery, _ kitamqp.Channel, _ *amqp.Publishing) {
+	var requeue bool
+	if se, ok := err.(*goa.ServiceError); ok {
+		requeue = se.Temporary || se.Timeout
+	}
+	deliv.Nack(false, requeue)
+}
+`
+
+var AMQPConsumeCode = `// Consume consumes the messages of the AMQPService service queues on the given
+// channel and serves them using the service endpoints. The messages are
+// acknowledged once served and rejected if the request fails, the errors are
+// logged with logger. Consume returns once all the queues are consumed, the
+// messages are served until the channel closes the delivery channels.
+func Consume(ch kitamqp.Channel, endpoints *amqpservice.Endpoints, logger log.Logger, options ...kitamqp.SubscriberOption) error {
+	options = append([]kitamqp.SubscriberOption{kitamqp.SubscriberErrorHandler(transport.NewLogErrorHandler(logger))}, options...)
+	if err := consume(ch, "amqp.method", NewAMQPMethodSubscriber(endpoints.AMQPMethod, options...)); err != nil {
+		return err
+	}
+	if err := consume(ch, "amqp.no_payload", NewAMQPNoPayloadMethodSubscriber(endpoints.AMQPNoPayloadMethod, options...)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// consume serves the messages delivered by the given queue using sub.
+func consume(ch kitamqp.Channel, queue string, sub *kitamqp.Subscriber) error {
+	deliveries, err := ch.Consume(queue, "", false, false, false, false, nil)
+	if err != nil {
+		return err
+	}
+	serve := sub.ServeDelivery(ch)
+	go func() {
+		for deliv := range deliveries {
+			deliv := deliv
+			serve(&deliv)
+		}
+	}()
+	return nil
+}
+`
+
+var AMQPMethodPublisherCode = `// EncodeAMQPMethodRequest is a go-kit AMQP EncodeRequestFunc that encodes
+// AMQPService AMQPMethod payloads using JSON.
+func EncodeAMQPMethodRequest(ctx context.Context, pub *amqp.Publishing, request interface{}) error {
+	return EncodeJSONRequest(ctx, pub, NewAMQPMethodRequestBody(request.(*amqpservice.AMQPMethodPayload)))
+}
+
+// NewAMQPMethodPublisher returns a go-kit AMQP publisher that publishes
+// AMQPService AMQPMethod requests to the "amqp" exchange with the
+// "amqp.method" routing key. The requests are fire-and-forget: the publisher
+// does not wait for a response and its endpoint returns a nil response.
+func NewAMQPMethodPublisher(ch kitamqp.Channel, options ...kitamqp.PublisherOption) *kitamqp.Publisher {
+	return kitamqp.NewPublisher(
+		ch,
+		&amqp.Queue{},
+		EncodeAMQPMethodRequest,
+		func(context.Context, *amqp.Delivery) (interface{}, error) { return nil, nil },
+		append([]kitamqp.PublisherOption{
+			kitamqp.PublisherBefore(kitamqp.SetPublishExchange("amqp"), kitamqp.SetPublishKey("amqp.method")),
+			kitamqp.PublisherDeliverer(kitamqp.SendAndForgetDeliverer),
+		}, options...)...,
+	)
+}
+`
+
+var AMQPNoPayloadMethodPublisherCode = `// NewAMQPNoPayloadMethodPublisher returns a go-kit AMQP publisher that
+// publishes AMQPService AMQPNoPayloadMethod requests to the "" exchange with
+// the "amqp.no_payload" routing key. The requests are fire-and-forget: the
+// publisher does not wait for a response and its endpoint returns a nil
+// response.
+func NewAMQPNoPayloadMethodPublisher(ch kitamqp.Channel, options ...kitamqp.PublisherOption) *kitamqp.Publisher {
+	return kitamqp.NewPublisher(
+		ch,
+		&amqp.Queue{},
+		EncodeJSONRequest,
+		func(context.Context, *amqp.Delivery) (interface{}, error) { return nil, nil },
+		append([]kitamqp.PublisherOption{
+			kitamqp.PublisherBefore(kitamqp.SetPublishExchange(""), kitamqp.SetPublishKey("amqp.no_payload")),
+			kitamqp.PublisherDeliverer(kitamqp.SendAndForgetDeliverer),
+		}, options...)...,
+	)
+}
+`
+
+var AMQPMethodTestCode = `// TestAMQPMethodAMQP makes sure that the AMQPService AMQPMethod requests
+// published by the go-kit AMQP publisher are served by the subscriber with the
+// design example payload.
+func TestAMQPMethodAMQP(t *testing.T) {
+	ch := newChannel()
+	received := make(chan interface{}, 1)
+	endpoints := &amqpservice.Endpoints{
+		AMQPMethod: func(_ context.Context, request interface{}) (interface{}, error) {
+			received <- request
+			return nil, nil
+		},
+	}
+	if err := Consume(ch, endpoints, log.NewNopLogger()); err != nil {
+		t.Fatalf("failed to consume: %s", err)
+	}
+	payload := &amqpservice.AMQPMethodPayload{ID: "a"}
+	if _, err := kitclient.NewAMQPMethodPublisher(ch).Endpoint()(context.Background(), payload); err != nil {
+		t.Fatalf("failed to publish: %s", err)
+	}
+	select {
+	case got := <-received:
+		if !reflect.DeepEqual(got, payload) {
+			t.Errorf("invalid payload, got %#v, expected %#v", got, payload)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("request not served")
+	}
+	select {
+	case ack := <-ch.acks:
+		if ack != "ack" {
+			t.Errorf("invalid acknowledgement, got %q, expected \"ack\"", ack)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("delivery not acknowledged")
+	}
+}
+`
+
+var AMQPNoPayloadMethodTestCode = `// TestAMQPNoPayloadMethodAMQP makes sure that the AMQPService
+// AMQPNoPayloadMethod requests published by the go-kit AMQP publisher are
+// served by the subscriber with the design example payload.
+func TestAMQPNoPayloadMethodAMQP(t *testing.T) {
+	ch := newChannel()
+	received := make(chan interface{}, 1)
+	endpoints := &amqpservice.Endpoints{
+		AMQPNoPayloadMethod: func(_ context.Context, request interface{}) (interface{}, error) {
+			received <- request
+			return nil, nil
+		},
+	}
+	if err := Consume(ch, endpoints, log.NewNopLogger()); err != nil {
+		t.Fatalf("failed to consume: %s", err)
+	}
+	if _, err := kitclient.NewAMQPNoPayloadMethodPublisher(ch).Endpoint()(context.Background(), nil); err != nil {
+		t.Fatalf("failed to publish: %s", err)
+	}
+	select {
+	case got := <-received:
+		if got != nil {
+			t.Errorf("invalid payload, got %#v, expected nil", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("request not served")
+	}
+	select {
+	case ack := <-ch.acks:
+		if ack != "ack" {
+			t.Errorf("invalid acknowledgement, got %q, expected \"ack\"", ack)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("delivery not acknowledged")
+	}
+}
+`
+
+var AMQPMethodRejectTestCode = `// TestAMQPMethodAMQPReject makes sure that the AMQPService AMQPMethod
+// deliveries are rejected without being requeued when the payload is invalid
+// or when the endpoint fails.
+func TestAMQPMethodAMQPReject(t *testing.T) {
+	ch := newChannel()
+	received := make(chan interface{}, 1)
+	endpoints := &amqpservice.Endpoints{
+		AMQPMethod: func(_ context.Context, request interface{}) (interface{}, error) {
+			received <- request
+			return nil, errors.New("failed")
+		},
+	}
+	if err := Consume(ch, endpoints, log.NewNopLogger()); err != nil {
+		t.Fatalf("failed to consume: %s", err)
+	}
+	rejected := func(name string) {
+		select {
+		case ack := <-ch.acks:
+			if ack != "nack" {
+				t.Errorf("%s: invalid acknowledgement, got %q, expected \"nack\"", name, ack)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("%s: delivery not rejected", name)
+		}
+	}
+	if err := ch.Publish("amqp", "amqp.method", false, false, amqp.Publishing{ContentType: "application/json", Body: []byte("{}")}); err != nil {
+		t.Fatalf("failed to publish: %s", err)
+	}
+	rejected("invalid payload")
+	select {
+	case got := <-received:
+		t.Errorf("invalid payload served, got %#v", got)
+	default:
+	}
+	if _, err := kitclient.NewAMQPMethodPublisher(ch).Endpoint()(context.Background(), &amqpservice.AMQPMethodPayload{ID: "a"}); err != nil {
+		t.Fatalf("failed to publish: %s", err)
+	}
+	rejected("endpoint error")
+	select {
+	case <-received:
+	default:
+		t.Error("request not served")
+	}
+}
+`
+
+var AMQPNoPayloadMethodRejectTestCode = `// TestAMQPNoPayloadMethodAMQPReject makes sure that the AMQPService
+// AMQPNoPayloadMethod deliveries are rejected without being requeued when the
+// payload is invalid or when the endpoint fails.
+func TestAMQPNoPayloadMethodAMQPReject(t *testing.T) {
+	ch := newChannel()
+	received := make(chan interface{}, 1)
+	endpoints := &amqpservice.Endpoints{
+		AMQPNoPayloadMethod: func(_ context.Context, request interface{}) (interface{}, error) {
+			received <- request
+			return nil, errors.New("failed")
+		},
+	}
+	if err := Consume(ch, endpoints, log.NewNopLogger()); err != nil {
+		t.Fatalf("failed to consume: %s", err)
+	}
+	rejected := func(name string) {
+		select {
+		case ack := <-ch.acks:
+			if ack != "nack" {
+				t.Errorf("%s: invalid acknowledgement, got %q, expected \"nack\"", name, ack)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("%s: delivery not rejected", name)
+		}
+	}
+	if _, err := kitclient.NewAMQPNoPayloadMethodPublisher(ch).Endpoint()(context.Background(), nil); err != nil {
+		t.Fatalf("failed to publish: %s", err)
+	}
+	rejected("endpoint error")
+	select {
+	case <-received:
+	default:
+		t.Error("request not served")
+	}
+}
+`
+
+var AMQPServerBodyTypesCode = `// AMQPMethodRequestBody is the type of the JSON encoded AMQPService AMQPMethod
+// payloads.
+type AMQPMethodRequestBody struct {
+	ID *string ` + "`" + `form:"id" json:"id" xml:"id"` + "`" + `
+}
+
+// ValidateAMQPMethodRequestBody runs the validations defined in the design on
+// AMQPMethodRequestBody.
+func ValidateAMQPMethodRequestBody(body *AMQPMethodRequestBody) (err error) {
+	if body.ID == nil {
+		err = goa.MergeErrors(err, goa.MissingFieldError("id", "body"))
+	}
+	return
+}
+
+// NewAMQPMethodPayload builds a value of type *amqpservice.AMQPMethodPayload
+// from the decoded AMQPMethodRequestBody body.
+func NewAMQPMethodPayload(body *AMQPMethodRequestBody) *amqpservice.AMQPMethodPayload {
+	v := &amqpservice.AMQPMethodPayload{
+		ID: *body.ID,
+	}
+	return v
+}
+`
+
+var AMQPClientBodyTypesCode = `// AMQPMethodRequestBody is the type of the JSON encoded AMQPService AMQPMethod
+// payloads.
+type AMQPMethodRequestBody struct {
+	ID string ` + "`" + `form:"id" json:"id" xml:"id"` + "`" + `
+}
+
+// NewAMQPMethodRequestBody builds the AMQPMethodRequestBody body from a value
+// of type *amqpservice.AMQPMethodPayload.
+func NewAMQPMethodRequestBody(p *amqpservice.AMQPMethodPayload) *AMQPMethodRequestBody {
+	v := &AMQPMethodRequestBody{
+		ID: p.ID,
+	}
+	return v
+}
+`
+
+var NATSServerBodyTypesCode = `// NATSMethodRequestBody is the type of the JSON encoded NATSService NATSMethod
+// payloads.
+type NATSMethodRequestBody struct {
+	ID *string ` + "`" + `form:"id,omitempty" json:"id,omitempty" xml:"id,omitempty"` + "`" + `
+}
+
+// NewNATSMethodPayload builds a value of type *natsservice.NATSMethodPayload
+// from the decoded NATSMethodRequestBody body.
+func NewNATSMethodPayload(body *NATSMethodRequestBody) *natsservice.NATSMethodPayload {
+	v := &natsservice.NATSMethodPayload{
+		ID: body.ID,
+	}
+	return v
+}
+`
+
+var NATSClientBodyTypesCode = `// NATSMethodRequestBody is the type of the JSON encoded NATSService NATSMethod
+// payloads.
+type NATSMethodRequestBody struct {
+	ID *string ` + "`" + `form:"id,omitempty" json:"id,omitempty" xml:"id,omitempty"` + "`" + `
+}
+
+// NewNATSMethodRequestBody builds the NATSMethodRequestBody body from a value
+// of type *natsservice.NATSMethodPayload.
+func NewNATSMethodRequestBody(p *natsservice.NATSMethodPayload) *NATSMethodRequestBody {
+	v := &NATSMethodRequestBody{
+		ID: p.ID,
+	}
+	return v
+}
+`
+
+var NATSValidationServerBodyTypesCode = `// NATSValidationMethodRequestBody is the type of the JSON encoded
+// NATSValidationService NATSValidationMethod payloads.
+type NATSValidationMethodRequestBody struct {
+	ID *string ` + "`" + `form:"id" json:"id" xml:"id"` + "`" + `
+}
+
+// ValidateNATSValidationMethodRequestBody runs the validations defined in the
+// design on NATSValidationMethodRequestBody.
+func ValidateNATSValidationMethodRequestBody(body *NATSValidationMethodRequestBody) (err error) {
+	if body.ID == nil {
+		err = goa.MergeErrors(err, goa.MissingFieldError("id", "body"))
+	}
+	if body.ID != nil {
+		if utf8.RuneCountInString(*body.ID) < 1 {
+			err = goa.MergeErrors(err, goa.InvalidLengthError("body.id", *body.ID, utf8.RuneCountInString(*body.ID), 1, true))
+		}
+	}
+	return
+}
+
+// NewNATSValidationMethodPayload builds a value of type
+// *natsvalidationservice.NATSValidationMethodPayload from the decoded
+// NATSValidationMethodRequestBody body.
+func NewNATSValidationMethodPayload(body *NATSValidationMethodRequestBody) *natsvalidationservice.NATSValidationMethodPayload {
+	v := &natsvalidationservice.NATSValidationMethodPayload{
+		ID: *body.ID,
+	}
+	return v
+}
+`
@@ -1,7 +1,10 @@
 package testdata
 
 import (
+	"time"
+
 	. "goa.design/goa/dsl"
+	goakit "goa.design/plugins/goakit/dsl"
 )
 
 var SimpleServiceDSL = func() {
@@ -94,3 +97,248 @@ var MultiServiceDSL = func() {
 		})
 	})
 }
+
+var TracingDSL = func() {
+	Service("TracingService", func() {
+		goakit.Tracing()
+		Method("TracingMethod", func() {
+			Payload(func() {
+				Attribute("id")
+			})
+			HTTP(func() {
+				GET("/")
+			})
+		})
+	})
+}
+
+var MultipartDSL = func() {
+	Service("MultipartService", func() {
+		Method("MultipartMethod", func() {
+			Payload(func() {
+				Attribute("id")
+			})
+			HTTP(func() {
+				POST("/")
+				MultipartRequest()
+			})
+		})
+	})
+}
+
+var StreamingDSL = func() {
+	Service("StreamingService", func() {
+		Method("StreamingMethod", func() {
+			StreamingResult(String)
+			HTTP(func() {
+				GET("/stream")
+			})
+		})
+		Method("NonStreamingMethod", func() {
+			HTTP(func() {
+				GET("/")
+			})
+		})
+	})
+}
+
+var NATSDSL = func() {
+	Service("NATSService", func() {
+		goakit.NATS("nats")
+		Method("NATSMethod", func() {
+			Payload(func() {
+				Attribute("id")
+			})
+			Result(String)
+			HTTP(func() {
+				GET("/")
+			})
+		})
+		Method("NATSOverrideMethod", func() {
+			goakit.NATS("nats.override")
+			HTTP(func() {
+				GET("/override")
+			})
+		})
+	})
+}
+
+var NATSValidationDSL = func() {
+	Service("NATSValidationService", func() {
+		goakit.NATS("nats")
+		Method("NATSValidationMethod", func() {
+			Payload(func() {
+				Attribute("id", String, func() {
+					MinLength(1)
+				})
+				Required("id")
+			})
+			HTTP(func() {
+				GET("/")
+			})
+		})
+	})
+}
+
+var JSONRPCDSL = func() {
+	Service("JSONRPCService", func() {
+		goakit.JSONRPC()
+		Method("JSONRPCMethod", func() {
+			Payload(func() {
+				Attribute("id")
+			})
+			Result(String)
+			HTTP(func() {
+				GET("/")
+			})
+		})
+	})
+}
+
+var JSONRPCValidationDSL = func() {
+	Service("JSONRPCValidationService", func() {
+		goakit.JSONRPC()
+		Method("JSONRPCValidationMethod", func() {
+			Payload(func() {
+				Attribute("id", String, func() {
+					MinLength(1)
+				})
+				Required("id")
+			})
+			HTTP(func() {
+				GET("/")
+			})
+		})
+	})
+}
+
+var TransportDSL = func() {
+	Service("TransportService", func() {
+		Method("TransportMethod", func() {
+			Payload(func() {
+				Attribute("a", Int, func() {
+					Example(1)
+				})
+				Attribute("b", ArrayOf(String), func() {
+					Example([]string{"b"})
+				})
+				Required("a", "b")
+			})
+			Result(func() {
+				Attribute("c", String, func() {
+					Example("c")
+				})
+				Required("c")
+			})
+			Error("bad_request")
+			HTTP(func() {
+				POST("/")
+				Response("bad_request", StatusBadRequest)
+			})
+		})
+	})
+}
+
+var ValidationDSL = func() {
+	Service("ValidationService", func() {
+		Method("ValidationMethod", func() {
+			Payload(func() {
+				Attribute("name", String, func() {
+					MinLength(3)
+				})
+				Required("name")
+			})
+			Result(String, func() {
+				MinLength(1)
+			})
+			HTTP(func() {
+				POST("/")
+			})
+		})
+	})
+}
+
+var NestedValidationDSL = func() {
+	var Item = Type("Item", func() {
+		Attribute("name", String, func() {
+			MinLength(3)
+		})
+		Required("name")
+	})
+	Service("NestedValidationService", func() {
+		Method("NestedValidationMethod", func() {
+			Payload(func() {
+				Attribute("item", Item)
+				Attribute("items", ArrayOf(Item))
+			})
+			HTTP(func() {
+				POST("/")
+			})
+		})
+	})
+}
+
+var HealthDSL = func() {
+	API("HealthAPI", func() {
+		goakit.Health()
+	})
+	Service("HealthCheckedService", func() {
+		Method("HealthCheckedMethod", func() {
+			HTTP(func() {
+				GET("/")
+			})
+		})
+	})
+}
+
+var PolicyDSL = func() {
+	Service("PolicyService", func() {
+		Method("PolicyMethod", func() {
+			goakit.Timeout(5 * time.Second)
+			goakit.Retry(3, 100*time.Millisecond)
+			Result(String)
+			Error("unavailable", func() {
+				Temporary()
+			})
+			Error("bad_request")
+			HTTP(func() {
+				GET("/")
+				Response("unavailable", StatusServiceUnavailable)
+				Response("bad_request", StatusBadRequest)
+			})
+		})
+		Method("NoPolicyMethod", func() {
+			HTTP(func() {
+				GET("/none")
+			})
+		})
+	})
+}
+
+var AMQPDSL = func() {
+	Service("AMQPService", func() {
+		Method("AMQPMethod", func() {
+			goakit.AMQP("amqp", "amqp.method")
+			Payload(func() {
+				Attribute("id", String, func() {
+					Example("a")
+				})
+				Required("id")
+			})
+			HTTP(func() {
+				POST("/")
+			})
+		})
+		Method("AMQPNoPayloadMethod", func() {
+			goakit.AMQP("", "amqp.no_payload")
+			HTTP(func() {
+				GET("/")
+			})
+		})
+		Method("HTTPMethod", func() {
+			HTTP(func() {
+				GET("/http")
+			})
+		})
+	})
+}
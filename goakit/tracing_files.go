@@ -0,0 +1,122 @@
+package goakit
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"goa.design/goa/codegen"
+	goaexpr "goa.design/goa/expr"
+	httpcodegen "goa.design/goa/http/codegen"
+	"goa.design/plugins/goakit/expr"
+)
+
+// tracingData contains the data needed to render the tracing templates of an
+// endpoint.
+type tracingData struct {
+	*httpcodegen.EndpointData
+	// SpanName is the name of the spans created for the endpoint.
+	SpanName string
+}
+
+// TracingFiles produces the files defining the go-kit server and client
+// options and endpoint middlewares that propagate the trace context for the
+// services that have tracing enabled.
+func TracingFiles(genpkg string, root *goaexpr.RootExpr) []*codegen.File {
+	var fw []*codegen.File
+	for _, svc := range root.API.HTTP.Services {
+		if !expr.Traced(svc.Name()) {
+			continue
+		}
+		fw = append(fw, serverTracing(svc), clientTracing(svc))
+	}
+	return fw
+}
+
+// serverTracing returns the file defining the go-kit HTTP server tracing
+// options and endpoint middlewares.
+func serverTracing(svc *goaexpr.HTTPServiceExpr) *codegen.File {
+	path := filepath.Join(codegen.Gendir, "http", codegen.SnakeCase(svc.Name()), "kitserver", "tracing.go")
+	data := httpcodegen.HTTPServices.Get(svc.Name())
+	title := fmt.Sprintf("%s go-kit HTTP server tracing", svc.Name())
+	sections := []*codegen.SectionTemplate{
+		codegen.Header(title, "server", []*codegen.ImportSpec{
+			{Path: "github.com/go-kit/kit/endpoint"},
+			{Path: "github.com/go-kit/kit/log"},
+			{Path: "github.com/go-kit/kit/tracing/opentracing", Name: "kitot"},
+			{Path: "github.com/go-kit/kit/transport/http", Name: "kithttp"},
+			{Path: "github.com/opentracing/opentracing-go", Name: "opentracing"},
+		}),
+	}
+	for _, e := range data.Endpoints {
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "goakit-server-tracing",
+			Source: serverTracingT,
+			Data:   buildTracingData(e),
+		})
+	}
+
+	return &codegen.File{Path: path, SectionTemplates: sections}
+}
+
+// clientTracing returns the file defining the go-kit HTTP client tracing
+// options and endpoint middlewares.
+func clientTracing(svc *goaexpr.HTTPServiceExpr) *codegen.File {
+	path := filepath.Join(codegen.Gendir, "http", codegen.SnakeCase(svc.Name()), "kitclient", "tracing.go")
+	data := httpcodegen.HTTPServices.Get(svc.Name())
+	title := fmt.Sprintf("%s go-kit HTTP client tracing", svc.Name())
+	sections := []*codegen.SectionTemplate{
+		codegen.Header(title, "client", []*codegen.ImportSpec{
+			{Path: "github.com/go-kit/kit/endpoint"},
+			{Path: "github.com/go-kit/kit/log"},
+			{Path: "github.com/go-kit/kit/tracing/opentracing", Name: "kitot"},
+			{Path: "github.com/go-kit/kit/transport/http", Name: "kithttp"},
+			{Path: "github.com/opentracing/opentracing-go", Name: "opentracing"},
+		}),
+	}
+	for _, e := range data.Endpoints {
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "goakit-client-tracing",
+			Source: clientTracingT,
+			Data:   buildTracingData(e),
+		})
+	}
+
+	return &codegen.File{Path: path, SectionTemplates: sections}
+}
+
+// buildTracingData builds the data needed to render the tracing templates of
+// the given endpoint.
+func buildTracingData(e *httpcodegen.EndpointData) *tracingData {
+	return &tracingData{
+		EndpointData: e,
+		SpanName:     expr.SpanName(e.ServiceName, e.Method.Name),
+	}
+}
+
+// input: tracingData
+const serverTracingT = `{{ printf "%sTraceServerOptions returns the go-kit HTTP server options that extract the span context from incoming %s %s requests." .Method.VarName .ServiceName .Method.Name | comment }}
+func {{ .Method.VarName }}TraceServerOptions(tracer opentracing.Tracer, logger log.Logger) []kithttp.ServerOption {
+	return []kithttp.ServerOption{
+		kithttp.ServerBefore(kitot.HTTPToContext(tracer, {{ printf "%q" .SpanName }}, logger)),
+	}
+}
+
+{{ printf "Trace%sEndpoint returns an endpoint middleware that records a %q span for each %s %s request." .Method.VarName .SpanName .ServiceName .Method.Name | comment }}
+func Trace{{ .Method.VarName }}Endpoint(tracer opentracing.Tracer) endpoint.Middleware {
+	return kitot.TraceServer(tracer, {{ printf "%q" .SpanName }})
+}
+`
+
+// input: tracingData
+const clientTracingT = `{{ printf "%sTraceClientOptions returns the go-kit HTTP client options that inject the span context into outgoing %s %s requests." .Method.VarName .ServiceName .Method.Name | comment }}
+func {{ .Method.VarName }}TraceClientOptions(tracer opentracing.Tracer, logger log.Logger) []kithttp.ClientOption {
+	return []kithttp.ClientOption{
+		kithttp.ClientBefore(kitot.ContextToHTTP(tracer, logger)),
+	}
+}
+
+{{ printf "Trace%sEndpoint returns an endpoint middleware that records a %q span for each %s %s request." .Method.VarName .SpanName .ServiceName .Method.Name | comment }}
+func Trace{{ .Method.VarName }}Endpoint(tracer opentracing.Tracer) endpoint.Middleware {
+	return kitot.TraceClient(tracer, {{ printf "%q" .SpanName }})
+}
+`
@@ -0,0 +1,49 @@
+package goakit
+
+import (
+	"strings"
+	"testing"
+
+	"goa.design/goa/expr"
+	"goa.design/plugins/goakit/testdata"
+)
+
+func TestTracingFiles(t *testing.T) {
+	cases := map[string]struct {
+		DSL        func()
+		ServerCode []string
+		ClientCode []string
+	}{
+		"tracing": {
+			DSL:        testdata.TracingDSL,
+			ServerCode: []string{testdata.TracingMethodServerTracingCode},
+			ClientCode: []string{testdata.TracingMethodClientTracingCode},
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			runDSL(t, c.DSL)
+			fs := TracingFiles("", expr.Root)
+			if len(fs) != 2 {
+				t.Fatalf("got %d files, expected 2", len(fs))
+			}
+			for _, f := range fs {
+				switch {
+				case strings.Contains(f.Path, "kitserver"):
+					testCode(t, f, "goakit-server-tracing", c.ServerCode)
+				case strings.Contains(f.Path, "kitclient"):
+					testCode(t, f, "goakit-client-tracing", c.ClientCode)
+				default:
+					t.Errorf("unexpected file %s", f.Path)
+				}
+			}
+		})
+	}
+}
+
+func TestTracingFilesDisabled(t *testing.T) {
+	runDSL(t, testdata.SimpleServiceDSL)
+	if fs := TracingFiles("", expr.Root); len(fs) != 0 {
+		t.Errorf("got %d files, expected none", len(fs))
+	}
+}
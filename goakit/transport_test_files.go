@@ -0,0 +1,326 @@
+package goakit
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"goa.design/goa/codegen"
+	"goa.design/goa/expr"
+	httpcodegen "goa.design/goa/http/codegen"
+)
+
+type (
+	// transportTestData contains the data needed to render the transport
+	// test of an endpoint.
+	transportTestData struct {
+		*httpcodegen.EndpointData
+		// ServicePkgName is the name of the service package.
+		ServicePkgName string
+		// Payload is the Go expression that initializes the example
+		// payload, empty if the method has no payload.
+		Payload string
+		// Result is the Go expression that initializes the example result,
+		// empty if the method has no result.
+		Result string
+		// Errors lists the method errors that use the default error type.
+		Errors []*transportTestErrorData
+	}
+
+	// transportTestErrorData contains the data needed to render the
+	// transport test of an endpoint error.
+	transportTestErrorData struct {
+		// Name is the name of the error.
+		Name string
+		// VarName is the Go name of the error.
+		VarName string
+		// MakeFunc is the name of the service function that creates the
+		// error.
+		MakeFunc string
+	}
+)
+
+// TransportTestFiles produces the files defining the tests that make sure that
+// the go-kit server and client encoders and decoders of each service round
+// trip using the design examples.
+func TransportTestFiles(genpkg string, root *expr.RootExpr) []*codegen.File {
+	var fw []*codegen.File
+	for _, svc := range root.API.HTTP.Services {
+		if f := transportTest(genpkg, svc); f != nil {
+			fw = append(fw, f)
+		}
+	}
+	return fw
+}
+
+// transportTest returns the file defining the transport tests of the given
+// service. It returns nil if the service has no go-kit client (see
+// hasKitClient) or if none of the service methods payload and result examples
+// can be initialized in Go, see exampleLiteral.
+func transportTest(genpkg string, svc *expr.HTTPServiceExpr) *codegen.File {
+	data := httpcodegen.HTTPServices.Get(svc.Name())
+	if !hasKitClient(data) {
+		return nil
+	}
+	var sections []*codegen.SectionTemplate
+	for _, e := range data.Endpoints {
+		td := buildTransportTestData(svc.ServiceExpr, data, e)
+		if td == nil {
+			continue
+		}
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "goakit-transport-test",
+			Source: transportTestT,
+			Data:   td,
+		})
+	}
+	if len(sections) == 0 {
+		return nil
+	}
+	path := filepath.Join(codegen.Gendir, "http", codegen.SnakeCase(svc.Name()), "kitserver", "encode_decode_test.go")
+	title := fmt.Sprintf("%s go-kit HTTP transport tests", svc.Name())
+	header := codegen.Header(title, "server", []*codegen.ImportSpec{
+		{Path: "context"},
+		{Path: "fmt"},
+		{Path: "net/http"},
+		{Path: "net/http/httptest"},
+		{Path: "net/url"},
+		{Path: "reflect"},
+		{Path: "testing"},
+		{Path: "github.com/go-kit/kit/transport/http", Name: "kithttp"},
+		{Path: "goa.design/goa", Name: "goa"},
+		{Path: "goa.design/goa/http", Name: "goahttp"},
+		{Path: genpkg + "/" + codegen.SnakeCase(svc.Name()), Name: data.Service.PkgName},
+		{Path: genpkg + "/http/" + data.Service.Name + "/kitclient", Name: "kitclient"},
+		{Path: genpkg + "/http/" + data.Service.Name + "/server", Name: "goaserver"},
+	})
+
+	return &codegen.File{Path: path, SectionTemplates: append([]*codegen.SectionTemplate{header}, sections...)}
+}
+
+// buildTransportTestData builds the data needed to render the transport test of
+// the given endpoint. It returns nil if the endpoint cannot be tested: if its
+// result is viewed or if its payload or result example cannot be initialized
+// in Go.
+func buildTransportTestData(svc *expr.ServiceExpr, data *httpcodegen.ServiceData, e *httpcodegen.EndpointData) *transportTestData {
+	if e.Method.ViewedResult != nil {
+		return nil
+	}
+	m := svc.Method(e.Method.Name)
+	if m == nil {
+		return nil
+	}
+	td := &transportTestData{EndpointData: e, ServicePkgName: data.Service.PkgName}
+	r := expr.Root.API.Random()
+	if m.Payload.Type != expr.Empty {
+		lit, ok := exampleLiteral(m.Payload, m.Payload.Example(r), e.Payload.Ref)
+		if !ok {
+			return nil
+		}
+		td.Payload = lit
+	}
+	if m.Result.Type != expr.Empty {
+		lit, ok := exampleLiteral(m.Result, m.Result.Example(r), e.Result.Ref)
+		if !ok {
+			return nil
+		}
+		td.Result = lit
+	}
+	for _, er := range m.Errors {
+		if er.Type != expr.ErrorResult {
+			continue
+		}
+		varName := codegen.Goify(er.Name, true)
+		td.Errors = append(td.Errors, &transportTestErrorData{
+			Name:     er.Name,
+			VarName:  varName,
+			MakeFunc: "Make" + varName,
+		})
+	}
+	return td
+}
+
+// exampleLiteral returns the Go expression that initializes a value of the
+// type with the given Go reference to the example ex of attribute att. The
+// supported types are primitives, arrays of primitives and objects whose
+// attributes are primitives or arrays of primitives. exampleLiteral returns
+// false if the type is not supported.
+func exampleLiteral(att *expr.AttributeExpr, ex interface{}, ref string) (string, bool) {
+	switch {
+	case expr.IsPrimitive(att.Type):
+		return primitiveLiteral(att.Type, ex)
+	case expr.IsArray(att.Type):
+		return arrayLiteral(att.Type, ex)
+	case expr.IsObject(att.Type):
+		if !strings.HasPrefix(ref, "*") {
+			return "", false
+		}
+		vals, ok := ex.(map[string]interface{})
+		if !ok {
+			return "", false
+		}
+		obj := expr.AsObject(att.Type)
+		var names []string
+		for n := range vals {
+			if obj.Attribute(n) == nil {
+				return "", false
+			}
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		fields := make([]string, len(names))
+		for i, n := range names {
+			fatt := obj.Attribute(n)
+			var (
+				lit string
+				ok  bool
+			)
+			switch {
+			case expr.IsPrimitive(fatt.Type):
+				lit, ok = primitiveLiteral(fatt.Type, vals[n])
+				if ok && att.IsPrimitivePointer(n, true) {
+					typ := codegen.GoNativeTypeName(fatt.Type)
+					lit = fmt.Sprintf("func() *%s { v := %s; return &v }()", typ, lit)
+				}
+			case expr.IsArray(fatt.Type):
+				lit, ok = arrayLiteral(fatt.Type, vals[n])
+			}
+			if !ok {
+				return "", false
+			}
+			fields[i] = fmt.Sprintf("%s: %s", codegen.Goify(n, true), lit)
+		}
+		return fmt.Sprintf("&%s{%s}", ref[1:], strings.Join(fields, ", ")), true
+	}
+	return "", false
+}
+
+// arrayLiteral returns the Go expression that initializes the example ex of the
+// array type dt. It returns false if the elements are not primitives.
+func arrayLiteral(dt expr.DataType, ex interface{}) (string, bool) {
+	elem := expr.AsArray(dt).ElemType
+	if !expr.IsPrimitive(elem.Type) {
+		return "", false
+	}
+	vals := reflect.ValueOf(ex)
+	if vals.Kind() != reflect.Slice {
+		return "", false
+	}
+	elems := make([]string, vals.Len())
+	for i := range elems {
+		lit, ok := primitiveLiteral(elem.Type, vals.Index(i).Interface())
+		if !ok {
+			return "", false
+		}
+		elems[i] = lit
+	}
+	return fmt.Sprintf("[]%s{%s}", codegen.GoNativeTypeName(elem.Type), strings.Join(elems, ", ")), true
+}
+
+// primitiveLiteral returns the Go expression that initializes the example ex of
+// the primitive type dt. It returns false for the Any type.
+func primitiveLiteral(dt expr.DataType, ex interface{}) (string, bool) {
+	switch dt.Kind() {
+	case expr.BooleanKind:
+		return fmt.Sprintf("%v", ex), true
+	case expr.StringKind:
+		return fmt.Sprintf("%q", ex), true
+	case expr.BytesKind:
+		return fmt.Sprintf("[]byte(%q)", ex), true
+	case expr.IntKind, expr.Int32Kind, expr.Int64Kind, expr.UIntKind, expr.UInt32Kind, expr.UInt64Kind,
+		expr.Float32Kind, expr.Float64Kind:
+		return fmt.Sprintf("%s(%v)", codegen.GoNativeTypeName(dt), ex), true
+	}
+	return "", false
+}
+
+// input: transportTestData
+const transportTestT = `{{ printf "Test%sTransport makes sure that the go-kit server and client of the %s %s endpoint round trip the design examples and that the go-kit server encodes the responses like the goa server." .Method.VarName .ServiceName .Method.Name | comment }}
+func Test{{ .Method.VarName }}Transport(t *testing.T) {
+	var (
+		payload  interface{}{{ if .Payload }} = {{ .Payload }}{{ end }}
+		result   interface{}{{ if .Result }} = {{ .Result }}{{ end }}
+		received interface{}
+	)
+	mux := goahttp.NewMuxer()
+	Mount{{ .Method.VarName }}Handler(mux, kithttp.NewServer(
+		func(_ context.Context, request interface{}) (interface{}, error) {
+			received = request
+			return result, nil
+		},
+	{{- if .Payload }}
+		{{ .RequestDecoder }}(mux, goahttp.RequestDecoder),
+	{{- else }}
+		func(context.Context, *http.Request) (interface{}, error) { return nil, nil },
+	{{- end }}
+		{{ .ResponseEncoder }}(goahttp.ResponseEncoder),
+	))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := kitclient.NewClient(u.Scheme, u.Host, http.DefaultClient, goahttp.RequestEncoder, goahttp.ResponseDecoder, false)
+	res, err := c.{{ .Method.VarName }}()(context.Background(), payload)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(received, payload) {
+		t.Errorf("invalid decoded payload, got %#v, expected %#v", received, payload)
+	}
+	if !reflect.DeepEqual(res, result) {
+		t.Errorf("invalid decoded result, got %#v, expected %#v", res, result)
+	}
+
+	kitw, goaw := httptest.NewRecorder(), httptest.NewRecorder()
+	if err := {{ .ResponseEncoder }}(goahttp.ResponseEncoder)(context.Background(), kitw, result); err != nil {
+		t.Fatalf("go-kit encoder error: %v", err)
+	}
+	if err := goaserver.{{ .ResponseEncoder }}(goahttp.ResponseEncoder)(context.Background(), goaw, result); err != nil {
+		t.Fatalf("goa encoder error: %v", err)
+	}
+	if kitw.Code != goaw.Code || kitw.Body.String() != goaw.Body.String() {
+		t.Errorf("invalid response, got %d %q, expected %d %q", kitw.Code, kitw.Body.String(), goaw.Code, goaw.Body.String())
+	}
+}
+{{- range .Errors }}
+
+{{ printf "Test%sTransport%s makes sure that the go-kit server and client of the %s %s endpoint round trip the %q error." $.Method.VarName .VarName $.ServiceName $.Method.Name .Name | comment }}
+func Test{{ $.Method.VarName }}Transport{{ .VarName }}(t *testing.T) {
+	var payload interface{}{{ if $.Payload }} = {{ $.Payload }}{{ end }}
+	mux := goahttp.NewMuxer()
+	Mount{{ $.Method.VarName }}Handler(mux, kithttp.NewServer(
+		func(context.Context, interface{}) (interface{}, error) {
+			return nil, {{ $.ServicePkgName }}.{{ .MakeFunc }}(fmt.Errorf("test error"))
+		},
+	{{- if $.Payload }}
+		{{ $.RequestDecoder }}(mux, goahttp.RequestDecoder),
+	{{- else }}
+		func(context.Context, *http.Request) (interface{}, error) { return nil, nil },
+	{{- end }}
+		{{ $.ResponseEncoder }}(goahttp.ResponseEncoder),
+		kithttp.ServerErrorEncoder({{ $.ErrorEncoder }}(goahttp.ResponseEncoder)),
+	))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := kitclient.NewClient(u.Scheme, u.Host, http.DefaultClient, goahttp.RequestEncoder, goahttp.ResponseDecoder, false)
+	_, err = c.{{ $.Method.VarName }}()(context.Background(), payload)
+	serr, ok := err.(*goa.ServiceError)
+	if !ok {
+		t.Fatalf("got error %#v, expected a *goa.ServiceError", err)
+	}
+	if serr.Name != {{ printf "%q" .Name }} {
+		t.Errorf("got error %q, expected %q", serr.Name, {{ printf "%q" .Name }})
+	}
+}
+{{- end }}
+`
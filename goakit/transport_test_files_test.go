@@ -0,0 +1,81 @@
+package goakit
+
+import (
+	"testing"
+
+	"goa.design/goa/expr"
+	"goa.design/plugins/goakit/testdata"
+)
+
+func TestTransportTestFiles(t *testing.T) {
+	cases := map[string]struct {
+		DSL  func()
+		Code []string
+	}{
+		"simple":    {testdata.SimpleServiceDSL, []string{testdata.SimpleMethodTransportTestCode}},
+		"transport": {testdata.TransportDSL, []string{testdata.TransportMethodTransportTestCode}},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			runDSL(t, c.DSL)
+			fs := TransportTestFiles("", expr.Root)
+			if len(fs) != 1 {
+				t.Fatalf("got %d files, expected 1", len(fs))
+			}
+			testCode(t, fs[0], "goakit-transport-test", c.Code)
+		})
+	}
+}
+
+func TestTransportTestFilesSkipped(t *testing.T) {
+	cases := map[string]func(){
+		"file-server": testdata.FileServerDSL,
+		"multipart":   testdata.MultipartDSL,
+		"streaming":   testdata.StreamingDSL,
+	}
+	for name, dsl := range cases {
+		t.Run(name, func(t *testing.T) {
+			runDSL(t, dsl)
+			if fs := TransportTestFiles("", expr.Root); len(fs) != 0 {
+				t.Errorf("got %d files, expected none", len(fs))
+			}
+		})
+	}
+}
+
+func TestExampleLiteral(t *testing.T) {
+	obj := &expr.AttributeExpr{
+		Type: &expr.Object{
+			{Name: "a", Attribute: &expr.AttributeExpr{Type: expr.Int}},
+			{Name: "b", Attribute: &expr.AttributeExpr{Type: expr.String}},
+			{Name: "c", Attribute: &expr.AttributeExpr{Type: &expr.Array{ElemType: &expr.AttributeExpr{Type: expr.Float64}}}},
+		},
+		Validation: &expr.ValidationExpr{Required: []string{"a"}},
+	}
+	cases := map[string]struct {
+		Attribute *expr.AttributeExpr
+		Example   interface{}
+		Ref       string
+		Expected  string
+		OK        bool
+	}{
+		"string":  {&expr.AttributeExpr{Type: expr.String}, "foo", "string", `"foo"`, true},
+		"int32":   {&expr.AttributeExpr{Type: expr.Int32}, 42, "int32", "int32(42)", true},
+		"bytes":   {&expr.AttributeExpr{Type: expr.Bytes}, []byte("foo"), "[]byte", `[]byte("foo")`, true},
+		"array":   {&expr.AttributeExpr{Type: &expr.Array{ElemType: &expr.AttributeExpr{Type: expr.String}}}, []interface{}{"a", "b"}, "[]string", `[]string{"a", "b"}`, true},
+		"object":  {obj, map[string]interface{}{"a": 1, "b": "b", "c": []float64{1.5}}, "*svc.Payload", `&svc.Payload{A: int(1), B: func() *string { v := "b"; return &v }(), C: []float64{float64(1.5)}}`, true},
+		"any":     {&expr.AttributeExpr{Type: expr.Any}, "foo", "interface{}", "", false},
+		"unknown": {obj, map[string]interface{}{"d": 1}, "*svc.Payload", "", false},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			lit, ok := exampleLiteral(c.Attribute, c.Example, c.Ref)
+			if ok != c.OK {
+				t.Fatalf("got ok %v, expected %v", ok, c.OK)
+			}
+			if lit != c.Expected {
+				t.Errorf("got %s, expected %s", lit, c.Expected)
+			}
+		})
+	}
+}
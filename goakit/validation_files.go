@@ -0,0 +1,284 @@
+package goakit
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"goa.design/goa/codegen"
+	"goa.design/goa/expr"
+	httpcodegen "goa.design/goa/http/codegen"
+)
+
+// validationData contains the data needed to render the validation templates
+// of an endpoint.
+type validationData struct {
+	*httpcodegen.EndpointData
+	// PayloadRef is the reference to the payload type, empty if the payload
+	// is not validated.
+	PayloadRef string
+	// PayloadPointer is true if the payload type is a pointer.
+	PayloadPointer bool
+	// PayloadValidation is the code that validates the payload held by the
+	// "payload" variable.
+	PayloadValidation string
+	// ResultRef is the reference to the result type, empty if the result is
+	// not validated.
+	ResultRef string
+	// ResultPointer is true if the result type is a pointer.
+	ResultPointer bool
+	// ResultValidation is the code that validates the result held by the
+	// "result" variable.
+	ResultValidation string
+}
+
+// typeValidationData contains the data needed to render the validation
+// function of a user type used by the service method payloads or results.
+type typeValidationData struct {
+	// Name is the name of the user type.
+	Name string
+	// VarName is the name of the validated variable.
+	VarName string
+	// Ref is the reference to the user type Go type.
+	Ref string
+	// Validation is the code that validates the value held by the VarName
+	// variable.
+	Validation string
+}
+
+// ValidationFiles produces the files defining the go-kit endpoint middlewares
+// that validate the service method payloads and results.
+func ValidationFiles(genpkg string, root *expr.RootExpr) []*codegen.File {
+	var fw []*codegen.File
+	for _, svc := range root.API.HTTP.Services {
+		if f := endpointValidation(genpkg, svc); f != nil {
+			fw = append(fw, f)
+		}
+	}
+	return fw
+}
+
+// endpointValidation returns the file defining the validation functions and
+// endpoint middlewares of the given service. It returns nil if the design
+// defines no validation on the service method payloads and results.
+func endpointValidation(genpkg string, svc *expr.HTTPServiceExpr) *codegen.File {
+	data := httpcodegen.HTTPServices.Get(svc.Name())
+	var (
+		vds   []*validationData
+		types []*expr.AttributeExpr
+	)
+	for _, e := range data.Endpoints {
+		if isStreaming(e) {
+			continue
+		}
+		m := svc.ServiceExpr.Method(e.Method.Name)
+		if m == nil {
+			continue
+		}
+		vd := &validationData{EndpointData: e}
+		if code := validationCode(m.Payload, "payload"); code != "" {
+			vd.PayloadRef = e.Payload.Ref
+			vd.PayloadPointer = strings.HasPrefix(e.Payload.Ref, "*")
+			vd.PayloadValidation = code
+		}
+		if code := validationCode(m.Result, "result"); code != "" && e.Method.ViewedResult == nil {
+			vd.ResultRef = e.Result.Ref
+			vd.ResultPointer = strings.HasPrefix(e.Result.Ref, "*")
+			vd.ResultValidation = code
+		}
+		if vd.PayloadRef != "" {
+			types = append(types, m.Payload)
+		}
+		if vd.ResultRef != "" {
+			types = append(types, m.Result)
+		}
+		if vd.PayloadRef != "" || vd.ResultRef != "" {
+			vds = append(vds, vd)
+		}
+	}
+	if len(vds) == 0 {
+		return nil
+	}
+	// The validation code of the payloads and results calls the validation
+	// functions of the nested user types, the functions are generated once
+	// per type. The validation functions of the payload and result types
+	// may have the same name, they are identical then.
+	generated := make(map[string]bool)
+	for _, vd := range vds {
+		if vd.PayloadRef != "" {
+			generated["Validate"+vd.Method.VarName+"Payload"] = true
+		}
+		if vd.ResultRef != "" {
+			generated["Validate"+vd.Method.VarName+"Result"] = true
+		}
+	}
+	var tvds []*typeValidationData
+	for _, ut := range nestedUserTypes(types) {
+		name := codegen.Goify(ut.Name(), true)
+		if generated["Validate"+name] {
+			continue
+		}
+		varName := codegen.Goify(ut.Name(), false)
+		code := codegen.RecursiveValidationCode(ut.Attribute(), true, false, true, varName)
+		if code == "" {
+			continue
+		}
+		generated["Validate"+name] = true
+		tvds = append(tvds, &typeValidationData{
+			Name:       name,
+			VarName:    varName,
+			Ref:        data.Service.Scope.GoFullTypeRef(&expr.AttributeExpr{Type: ut}, data.Service.PkgName),
+			Validation: code,
+		})
+	}
+	path := filepath.Join(codegen.Gendir, codegen.SnakeCase(svc.Name()), "kitendpoint", "validate.go")
+	title := fmt.Sprintf("%s go-kit endpoint validation", svc.Name())
+	sections := []*codegen.SectionTemplate{
+		codegen.Header(title, "kitendpoint", []*codegen.ImportSpec{
+			{Path: "context"},
+			{Path: "unicode/utf8"},
+			{Path: "github.com/go-kit/kit/endpoint"},
+			{Path: "goa.design/goa", Name: "goa"},
+			{Path: genpkg + "/" + codegen.SnakeCase(svc.Name()), Name: data.Service.PkgName},
+		}),
+	}
+	for _, vd := range vds {
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "goakit-validate-endpoint",
+			Source: validateEndpointT,
+			Data:   vd,
+		})
+	}
+	for _, tvd := range tvds {
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "goakit-validate-type",
+			Source: validateTypeT,
+			Data:   tvd,
+		})
+	}
+
+	return &codegen.File{Path: path, SectionTemplates: sections}
+}
+
+// payloadValidation returns whether the payload of the given endpoint must be
+// validated by the transport decoders and whether the payload type is a
+// pointer. The decoders call the Validate<Method>Payload function generated
+// by ValidationFiles.
+func payloadValidation(svc *expr.HTTPServiceExpr, e *httpcodegen.EndpointData) (validate, pointer bool) {
+	m := svc.ServiceExpr.Method(e.Method.Name)
+	if m == nil || validationCode(m.Payload, "payload") == "" {
+		return false, false
+	}
+	return true, strings.HasPrefix(e.Payload.Ref, "*")
+}
+
+// validationCode returns the code that validates the value of the given
+// attribute held by the variable named target, empty if there is no
+// validation to run. The code calls the validation functions of the nested
+// user types, see nestedUserTypes.
+func validationCode(att *expr.AttributeExpr, target string) string {
+	if att.Type == expr.Empty {
+		return ""
+	}
+	if ut, ok := att.Type.(expr.UserType); ok {
+		att = ut.Attribute()
+	}
+	return codegen.RecursiveValidationCode(att, true, false, true, target)
+}
+
+// nestedUserTypes returns the object user types used by the attributes of the
+// given payloads and results, including the types of the nested attributes,
+// array elements and map values. goa does not generate validation functions
+// for the service types so the plugin generates them for these types.
+func nestedUserTypes(atts []*expr.AttributeExpr) []expr.UserType {
+	var (
+		uts     []expr.UserType
+		seen    = make(map[string]bool)
+		collect func(*expr.AttributeExpr, bool)
+	)
+	collect = func(att *expr.AttributeExpr, top bool) {
+		switch dt := att.Type.(type) {
+		case expr.UserType:
+			if dt == expr.Empty || !expr.IsObject(dt) {
+				collect(dt.Attribute(), top)
+				return
+			}
+			if !top {
+				if seen[dt.ID()] {
+					return
+				}
+				seen[dt.ID()] = true
+				uts = append(uts, dt)
+			}
+			collect(dt.Attribute(), false)
+		case *expr.Object:
+			for _, nat := range *dt {
+				collect(nat.Attribute, false)
+			}
+		case *expr.Array:
+			collect(dt.ElemType, false)
+		case *expr.Map:
+			collect(dt.KeyType, false)
+			collect(dt.ElemType, false)
+		}
+	}
+	for _, att := range atts {
+		collect(att, true)
+	}
+	return uts
+}
+
+// input: validationData
+const validateEndpointT = `{{ if .PayloadRef -}}
+{{ printf "Validate%sPayload runs the validations defined in the design on the %s %s payload." .Method.VarName .ServiceName .Method.Name | comment }}
+func Validate{{ .Method.VarName }}Payload(payload {{ .PayloadRef }}) (err error) {
+	{{ .PayloadValidation }}
+	return
+}
+
+{{ end -}}
+{{ if .ResultRef -}}
+{{ printf "Validate%sResult runs the validations defined in the design on the %s %s result." .Method.VarName .ServiceName .Method.Name | comment }}
+func Validate{{ .Method.VarName }}Result(result {{ .ResultRef }}) (err error) {
+	{{ .ResultValidation }}
+	return
+}
+
+{{ end -}}
+{{ printf "Validate%sEndpoint returns an endpoint middleware that validates the %s %s requests%s. The middleware enforces the design validations regardless of the transport, e.g. when the endpoint is called in-process." .Method.VarName .ServiceName .Method.Name (and .ResultRef " and optionally the responses") | comment }}
+func Validate{{ .Method.VarName }}Endpoint({{ if .ResultRef }}validateResult bool{{ end }}) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (interface{}, error) {
+		{{- if .PayloadRef }}
+			if payload, ok := request.({{ .PayloadRef }}); ok{{ if .PayloadPointer }} && payload != nil{{ end }} {
+				if err := Validate{{ .Method.VarName }}Payload(payload); err != nil {
+					return nil, err
+				}
+			}
+		{{- end }}
+		{{- if .ResultRef }}
+			response, err := next(ctx, request)
+			if err != nil || !validateResult {
+				return response, err
+			}
+			if result, ok := response.({{ .ResultRef }}); ok{{ if .ResultPointer }} && result != nil{{ end }} {
+				if err := Validate{{ .Method.VarName }}Result(result); err != nil {
+					return nil, err
+				}
+			}
+			return response, nil
+		{{- else }}
+			return next(ctx, request)
+		{{- end }}
+		}
+	}
+}
+`
+
+// input: typeValidationData
+const validateTypeT = `{{ printf "Validate%s runs the validations defined in the design on the %s type." .Name .Name | comment }}
+func Validate{{ .Name }}({{ .VarName }} {{ .Ref }}) (err error) {
+	{{ .Validation }}
+	return
+}
+`
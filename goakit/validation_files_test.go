@@ -0,0 +1,46 @@
+package goakit
+
+import (
+	"testing"
+
+	"goa.design/goa/expr"
+	"goa.design/plugins/goakit/testdata"
+)
+
+func TestValidationFiles(t *testing.T) {
+	cases := map[string]struct {
+		DSL          func()
+		EndpointCode []string
+		TypeCode     []string
+	}{
+		"validation": {testdata.ValidationDSL, []string{testdata.ValidationMethodValidateEndpointCode}, nil},
+		"nested":     {testdata.NestedValidationDSL, []string{testdata.NestedValidationMethodValidateEndpointCode}, []string{testdata.ItemValidateTypeCode}},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			runDSL(t, c.DSL)
+			fs := ValidationFiles("", expr.Root)
+			if len(fs) != 1 {
+				t.Fatalf("got %d files, expected 1", len(fs))
+			}
+			testCode(t, fs[0], "goakit-validate-endpoint", c.EndpointCode)
+			testCode(t, fs[0], "goakit-validate-type", c.TypeCode)
+		})
+	}
+}
+
+func TestValidationFilesSkipped(t *testing.T) {
+	cases := map[string]func(){
+		"no-validation": testdata.SimpleServiceDSL,
+		"file-server":   testdata.FileServerDSL,
+		"streaming":     testdata.StreamingDSL,
+	}
+	for name, dsl := range cases {
+		t.Run(name, func(t *testing.T) {
+			runDSL(t, dsl)
+			if fs := ValidationFiles("", expr.Root); len(fs) != 0 {
+				t.Errorf("got %d files, expected none", len(fs))
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package logger_test
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"goa.design/goa/codegen"
+	"goa.design/goa/codegen/generator"
+	"goa.design/goa/eval"
+	"goa.design/goa/expr"
+	httpcodegen "goa.design/goa/http/codegen"
+	"goa.design/plugins/logger"
+	"goa.design/plugins/logger/testdata"
+	"goa.design/plugins/sloglogger"
+	"goa.design/plugins/zaplogger"
+	zaploggerexpr "goa.design/plugins/zaplogger/expr"
+	"goa.design/plugins/zerologger"
+)
+
+// TestBackends generates the same example with each backend and builds it, the
+// example code must compile whichever backend is imported by the design.
+func TestBackends(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping the example builds in short mode")
+	}
+	cases := map[string]*logger.Backend{
+		"zap":     zaplogger.Backend,
+		"zerolog": zerologger.Backend,
+		"slog":    sloglogger.Backend,
+	}
+	for name, b := range cases {
+		t.Run(name, func(t *testing.T) {
+			zaploggerexpr.Reset()
+			httpcodegen.RunHTTPDSL(t, testdata.CalcDSL)
+			roots := []eval.Root{expr.Root}
+
+			// The example is rendered in the package directory so that its
+			// import path is known, the leading underscore keeps it out of
+			// the ./... patterns.
+			dir, err := ioutil.TempDir(".", "_example")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+			genpkg := path.Join("goa.design/plugins/logger", filepath.ToSlash(dir), codegen.Gendir)
+
+			render(t, dir, "gen", genpkg, roots, b.Generate)
+			render(t, dir, "example", genpkg, roots, b.UpdateExample)
+
+			cmd := exec.Command("go", "build", "./...")
+			cmd.Dir = dir
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Errorf("failed to build the example: %s\n%s", err, out)
+			}
+		})
+	}
+}
+
+// render runs the goa generators of the given command followed by the given
+// backend plugin function and renders the files in dir.
+func render(t *testing.T, dir, cmd, genpkg string, roots []eval.Root, plugin func(string, []eval.Root, []*codegen.File) ([]*codegen.File, error)) {
+	t.Helper()
+	gens, err := generator.Generators(cmd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var files []*codegen.File
+	for _, gen := range gens {
+		fs, err := gen(genpkg, roots)
+		if err != nil {
+			t.Fatalf("goa %s: %s", cmd, err)
+		}
+		files = append(files, fs...)
+	}
+	files, err = plugin(genpkg, roots, files)
+	if err != nil {
+		t.Fatalf("goa %s: %s", cmd, err)
+	}
+	for _, f := range files {
+		if _, err := f.Render(dir); err != nil {
+			t.Fatalf("goa %s: %s", cmd, err)
+		}
+	}
+}
@@ -0,0 +1,271 @@
+/*
+Package logger contains the code shared by the logger plugins. A logger plugin
+replaces the standard library logger used by the example generated by "goa
+example" with a structured logger backed by a third party library.
+
+Each backend generates a "gen/log" package that exposes the same API so that
+switching from one library to another only requires changing the plugin
+imported by the design:
+
+	// New creates the service logger, it returns an error if the logger
+	// cannot be built.
+	func New(serviceName string, production bool) (*Logger, error)
+
+	// Log implements the goa middleware.Logger interface. It logs the given
+	// key/value pairs.
+	func (logger *Logger) Log(keyvals ...interface{}) error
+
+	// Info, Infof and Errorf log a message at the corresponding level. Info
+	// builds the message from args like fmt.Sprint does, it replaces the
+	// standard library logger Print and Println calls.
+	func (logger *Logger) Info(args ...interface{})
+	func (logger *Logger) Infof(format string, args ...interface{})
+	func (logger *Logger) Errorf(format string, args ...interface{})
+
+	// Sync flushes the buffered log entries.
+	func (logger *Logger) Sync() error
+*/
+package logger
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"goa.design/goa/codegen"
+	"goa.design/goa/eval"
+	"goa.design/goa/expr"
+)
+
+type (
+	// Backend describes a logger plugin. The core generates the files listed
+	// by Files and rewrites the example main and service files so that they
+	// use the generated log package.
+	Backend struct {
+		// Name is the name of the plugin, e.g. "zaplogger".
+		Name string
+		// Files returns the files generated by the backend, it must include
+		// the file defining the gen/log package.
+		Files func(genpkg string, root *expr.RootExpr) []*codegen.File
+		// UpdateMain modifies the example main files (main.go, http.go and
+		// grpc.go) before the core applies the common modifications. It
+		// returns an error if the files do not contain the code modified by
+		// the backend, see ApplyHooks. It may be nil.
+		UpdateMain func(genpkg string, root *expr.RootExpr, f *codegen.File) error
+		// UpdateService modifies the example service files before the core
+		// applies the common modifications. It may be nil.
+		UpdateService func(genpkg string, root *expr.RootExpr, f *codegen.File)
+	}
+
+	// SectionHook describes the changes made by a backend to a section
+	// generated by goa. Hooks are looked up by section name so that changes
+	// made to the goa templates are detected at generation time instead of
+	// silently producing incorrect code.
+	SectionHook struct {
+		// Section is the name of the section the hook applies to.
+		Section string
+		// Snippet identifies the section the hook applies to by content
+		// when Section is empty: the hook applies to the first section
+		// whose source contains Snippet.
+		Snippet string
+		// Rewrite modifies the section in place. header is the file header
+		// section used to add imports.
+		Rewrite func(header, s *codegen.SectionTemplate) error
+	}
+
+	fileToModify struct {
+		file        *codegen.File
+		path        string
+		serviceName string
+		isMain      bool
+	}
+)
+
+// Register registers the generator functions of the given backend with the
+// goa code generator. It is typically called by the backend package init
+// function.
+func Register(b *Backend) {
+	codegen.RegisterPluginFirst(b.Name, "gen", nil, b.Generate)
+	codegen.RegisterPluginLast(b.Name+"-updater", "example", nil, b.UpdateExample)
+}
+
+// LoggerFile returns a Files function that generates the gen/log/logger.go
+// file only. The file renders the given template after a header with the
+// given title and imports.
+func LoggerFile(title, section, source string, imports []*codegen.ImportSpec) func(string, *expr.RootExpr) []*codegen.File {
+	return func(genpkg string, root *expr.RootExpr) []*codegen.File {
+		path := filepath.Join(codegen.Gendir, "log", "logger.go")
+		sections := []*codegen.SectionTemplate{
+			codegen.Header(title, "log", imports),
+			{Name: section, Source: source},
+		}
+		return []*codegen.File{{Path: path, SectionTemplates: sections}}
+	}
+}
+
+// Generate appends the backend specific files to the generated files.
+func (b *Backend) Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	for _, root := range roots {
+		if r, ok := root.(*expr.RootExpr); ok {
+			files = append(files, b.Files(genpkg, r)...)
+		}
+	}
+	return files, nil
+}
+
+// UpdateExample modifies the example generated files by replacing
+// the log import reference with the generated log package.
+// It also modify the initially generated main and service files so that
+// they use the generated logger.
+func (b *Backend) UpdateExample(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	for _, root := range roots {
+		if r, ok := root.(*expr.RootExpr); ok {
+			for _, f := range filesToModify(r, files) {
+				if err := b.updateExampleFile(genpkg, r, f); err != nil {
+					return nil, fmt.Errorf("%s: %s", b.Name, err)
+				}
+			}
+		}
+	}
+	return files, nil
+}
+
+// filesToModify returns the example main and service files that use the
+// standard library logger.
+func filesToModify(root *expr.RootExpr, files []*codegen.File) []*fileToModify {
+	var fms []*fileToModify
+
+	// Add the generated main files
+	for _, svr := range root.API.Servers {
+		pkg := codegen.SnakeCase(codegen.Goify(svr.Name, true))
+		for _, name := range []string{"main.go", "http.go", "grpc.go"} {
+			fms = append(fms, &fileToModify{path: filepath.Join("cmd", pkg, name), serviceName: svr.Name, isMain: true})
+		}
+	}
+
+	// Add the generated service files
+	for _, svc := range root.Services {
+		servicePath := codegen.SnakeCase(svc.Name) + ".go"
+		fms = append(fms, &fileToModify{path: servicePath, serviceName: svc.Name, isMain: false})
+	}
+
+	// Keep the files that were actually generated
+	var found []*fileToModify
+	for _, fm := range fms {
+		for _, file := range files {
+			if file.Path == fm.path {
+				fm.file = file
+				found = append(found, fm)
+				break
+			}
+		}
+	}
+	return found
+}
+
+// ApplyHooks runs the hooks on the sections of the given file. It returns an
+// error if the file does not contain a section matching one of the hooks or if
+// a hook fails.
+func ApplyHooks(f *codegen.File, hooks []*SectionHook) error {
+	for _, h := range hooks {
+		var found bool
+		for _, s := range f.SectionTemplates {
+			if h.Section != "" && s.Name != h.Section {
+				continue
+			}
+			if h.Section == "" && (found || !strings.Contains(s.Source, h.Snippet)) {
+				continue
+			}
+			found = true
+			if err := h.Rewrite(f.SectionTemplates[0], s); err != nil {
+				return fmt.Errorf("%s: %s", f.Path, err)
+			}
+		}
+		if !found {
+			if h.Section == "" {
+				return fmt.Errorf("%s: no section contains %q, the goa templates may have changed", f.Path, h.Snippet)
+			}
+			return fmt.Errorf("%s: section %q not found, the goa templates may have changed", f.Path, h.Section)
+		}
+	}
+	return nil
+}
+
+// ReplaceSnippet replaces all the occurrences of old with new in the section
+// source. It returns an error if the source does not contain old.
+func ReplaceSnippet(s *codegen.SectionTemplate, old, new string) error {
+	if !strings.Contains(s.Source, old) {
+		return fmt.Errorf("section %q does not contain %q, the goa templates may have changed", s.Name, old)
+	}
+	s.Source = strings.Replace(s.Source, old, new, -1)
+	return nil
+}
+
+func (b *Backend) updateExampleFile(genpkg string, root *expr.RootExpr, f *fileToModify) error {
+	header := f.file.SectionTemplates[0]
+	logPath := path.Join(genpkg, "log")
+
+	data := header.Data.(map[string]interface{})
+	specs := data["Imports"].([]*codegen.ImportSpec)
+
+	for _, spec := range specs {
+		if spec.Path == "log" {
+			spec.Name = "log"
+			spec.Path = logPath
+		}
+	}
+
+	if !f.isMain {
+		if b.UpdateService != nil {
+			b.UpdateService(genpkg, root, f.file)
+		}
+		for _, s := range f.file.SectionTemplates {
+			s.Source = replacePrint(s.Source)
+		}
+		return nil
+	}
+
+	if b.UpdateMain != nil {
+		if err := b.UpdateMain(genpkg, root, f.file); err != nil {
+			return err
+		}
+	}
+	for _, s := range f.file.SectionTemplates {
+		s.Source = strings.Replace(s.Source, `logger = log.New(os.Stderr, "[{{ .APIPkg }}] ", log.Ltime)`, newLoggerT, 1)
+		s.Source = strings.Replace(s.Source, "adapter = middleware.NewLogger(logger)", "adapter = logger", 1)
+		s.Source = strings.Replace(s.Source, "handler = middleware.RequestID()(handler)",
+			`handler = middleware.PopulateRequestContext()(handler)
+				handler = middleware.RequestID(middleware.UseXRequestIDHeaderOption(true))(handler)`, 1)
+		s.Source = strings.Replace(s.Source, `logger.Printf("[%s] ERROR: %s", id, err.Error())`,
+			`logger.Errorf("[%s] ERROR: %s", id, err.Error())`, 1)
+		s.Source = replacePrint(s.Source)
+		s.Source = strings.Replace(s.Source, `logger.Info("exited")`, syncLoggerT, 1)
+	}
+	return nil
+}
+
+// replacePrint replaces the calls to the standard library logger print
+// functions with the equivalent calls to the generated logger.
+func replacePrint(src string) string {
+	src = strings.Replace(src, "logger.Print(", "logger.Info(", -1)
+	src = strings.Replace(src, "logger.Printf(", "logger.Infof(", -1)
+	return strings.Replace(src, "logger.Println(", "logger.Info(", -1)
+}
+
+// newLoggerT creates the example server logger. Backends may replace the
+// standard library logger creation with their own code in UpdateMain.
+const newLoggerT = `var err error
+		logger, err = log.New("{{ .APIPkg }}", false)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to create logger: %s\n", err)
+			os.Exit(1)
+		}`
+
+// syncLoggerT flushes the buffered log entries once the example servers have
+// exited.
+const syncLoggerT = `logger.Info("exited")
+
+	// Flush the buffered log entries. Sync may fail when the logs are written
+	// to a terminal, there is nothing left to report the error to anyway.
+	logger.Sync()`
@@ -0,0 +1,145 @@
+package logger
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"goa.design/goa/codegen"
+	"goa.design/goa/eval"
+	"goa.design/goa/expr"
+)
+
+func TestUpdateExampleFile(t *testing.T) {
+	const (
+		mainSrc = `logger = log.New(os.Stderr, "[{{ .APIPkg }}] ", log.Ltime)
+adapter = middleware.NewLogger(logger)
+logger.Printf("[%s] ERROR: %s", id, err.Error())
+logger.Println("exiting")
+logger.Print("exited")`
+		serviceSrc = `s.logger.Print("svc.method")`
+	)
+	var calls []string
+	b := &Backend{
+		Name: "test",
+		UpdateMain: func(_ string, _ *expr.RootExpr, f *codegen.File) error {
+			calls = append(calls, "main:"+f.Path)
+			return nil
+		},
+		UpdateService: func(_ string, _ *expr.RootExpr, f *codegen.File) {
+			calls = append(calls, "service:"+f.Path)
+		},
+	}
+	cases := []struct {
+		Name     string
+		IsMain   bool
+		Source   string
+		Call     string
+		Contains []string
+	}{
+		{"main", true, mainSrc, "main:main.go", []string{
+			`logger, err = log.New("{{ .APIPkg }}", false)`,
+			"adapter = logger\n",
+			`logger.Errorf("[%s] ERROR: %s", id, err.Error())`,
+			`logger.Info("exiting")`,
+			"logger.Sync()",
+		}},
+		{"service", false, serviceSrc, "service:main.go", []string{
+			`s.logger.Info("svc.method")`,
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			calls = nil
+			header := codegen.Header("", "main", []*codegen.ImportSpec{{Path: "log"}})
+			section := &codegen.SectionTemplate{Name: "section", Source: c.Source}
+			f := &fileToModify{
+				file:   &codegen.File{Path: "main.go", SectionTemplates: []*codegen.SectionTemplate{header, section}},
+				isMain: c.IsMain,
+			}
+			if err := b.updateExampleFile("gen", nil, f); err != nil {
+				t.Fatalf("update error: %v", err)
+			}
+
+			spec := header.Data.(map[string]interface{})["Imports"].([]*codegen.ImportSpec)[0]
+			if spec.Name != "log" || spec.Path != "gen/log" {
+				t.Errorf("got import %s %q, expected log %q", spec.Name, spec.Path, "gen/log")
+			}
+			if len(calls) != 1 || calls[0] != c.Call {
+				t.Errorf("got backend calls %v, expected [%s]", calls, c.Call)
+			}
+			for _, exp := range c.Contains {
+				if !strings.Contains(section.Source, exp) {
+					t.Errorf("got source:\n%s\nexpected it to contain %q", section.Source, exp)
+				}
+			}
+			if strings.Contains(section.Source, "logger.Print") {
+				t.Errorf("got source:\n%s\nexpected no call to logger.Print", section.Source)
+			}
+		})
+	}
+}
+
+func TestUpdateExampleError(t *testing.T) {
+	b := &Backend{
+		Name: "test",
+		UpdateMain: func(_ string, _ *expr.RootExpr, f *codegen.File) error {
+			return errors.New("section not found")
+		},
+	}
+	root := &expr.RootExpr{API: &expr.APIExpr{Servers: []*expr.ServerExpr{{Name: "calc"}}}}
+	header := codegen.Header("", "main", []*codegen.ImportSpec{{Path: "log"}})
+	files := []*codegen.File{{Path: "cmd/calc/main.go", SectionTemplates: []*codegen.SectionTemplate{header}}}
+	_, err := b.UpdateExample("gen", []eval.Root{root}, files)
+	if err == nil {
+		t.Fatal("got no error, expected the UpdateMain error")
+	}
+	if exp := "test: section not found"; err.Error() != exp {
+		t.Errorf("got error %q, expected %q", err.Error(), exp)
+	}
+}
+
+func TestApplyHooks(t *testing.T) {
+	replace := func(_, s *codegen.SectionTemplate) error {
+		return ReplaceSnippet(s, "adapter", "logger")
+	}
+	fail := func(_, s *codegen.SectionTemplate) error {
+		return errors.New("failed")
+	}
+	cases := []struct {
+		Name     string
+		Hook     *SectionHook
+		Expected string
+		Error    string
+	}{
+		{"by-name", &SectionHook{Section: "middleware", Rewrite: replace}, "Log(logger)", ""},
+		{"by-snippet", &SectionHook{Snippet: "Log(", Rewrite: replace}, "Log(logger)", ""},
+		{"missing-section", &SectionHook{Section: "mux", Rewrite: replace}, "", `main.go: section "mux" not found, the goa templates may have changed`},
+		{"missing-snippet", &SectionHook{Snippet: "Mux(", Rewrite: replace}, "", `main.go: no section contains "Mux(", the goa templates may have changed`},
+		{"missing-replaced", &SectionHook{Section: "logger", Rewrite: replace}, "", `main.go: section "logger" does not contain "adapter", the goa templates may have changed`},
+		{"rewrite-error", &SectionHook{Section: "middleware", Rewrite: fail}, "", "main.go: failed"},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			middleware := &codegen.SectionTemplate{Name: "middleware", Source: "Log(adapter)"}
+			f := &codegen.File{Path: "main.go", SectionTemplates: []*codegen.SectionTemplate{
+				codegen.Header("", "main", nil),
+				{Name: "logger", Source: "logger = New()"},
+				middleware,
+			}}
+			err := ApplyHooks(f, []*SectionHook{c.Hook})
+			if c.Error != "" {
+				if err == nil || err.Error() != c.Error {
+					t.Fatalf("got error %v, expected %q", err, c.Error)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if middleware.Source != c.Expected {
+				t.Errorf("got source %q, expected %q", middleware.Source, c.Expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package testdata
+
+import (
+	. "goa.design/goa/dsl"
+)
+
+var CalcDSL = func() {
+	Service("calc", func() {
+		Method("add", func() {
+			Payload(func() {
+				Attribute("a", Int)
+				Attribute("b", Int)
+				Required("a", "b")
+			})
+			Result(Int)
+			HTTP(func() {
+				GET("/add/{a}/{b}")
+			})
+		})
+	})
+}
@@ -0,0 +1,73 @@
+package sloglogger
+
+import (
+	"goa.design/goa/codegen"
+	"goa.design/plugins/logger"
+)
+
+// Backend is the slog logger plugin backend, it generates the
+// gen/log/logger.go file only.
+var Backend = &logger.Backend{
+	Name: "sloglogger",
+	Files: logger.LoggerFile("Slog logger implementation", "sloglogger", loggerT, []*codegen.ImportSpec{
+		{Path: "context"},
+		{Path: "fmt"},
+		{Path: "log/slog"},
+		{Path: "os"},
+	}),
+}
+
+// Register the plugin Generator functions.
+func init() {
+	logger.Register(Backend)
+}
+
+// input: none
+const loggerT = `
+// Logger is an adapted slog logger
+type Logger struct {
+	*slog.Logger
+}
+
+// New creates a new slog logger. The production logger writes JSON encoded
+// entries of level info and above, the development logger writes text
+// entries of level debug and above.
+func New(serviceName string, production bool) (*Logger, error) {
+	var h slog.Handler
+	if production {
+		h = slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelInfo})
+	} else {
+		h = slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug})
+	}
+	return &Logger{Logger: slog.New(h).With("service", serviceName)}, nil
+}
+
+// Log is called by the log middleware to log HTTP requests key values
+func (logger *Logger) Log(keyvals ...interface{}) error {
+	logger.Logger.Log(context.Background(), slog.LevelInfo, "HTTP Request", keyvals...)
+	return nil
+}
+
+// Info logs a message at info level. The message is built from args like
+// fmt.Sprint does so that the example Print calls do not produce !BADKEY
+// attributes, use the embedded slog Logger to log attributes.
+func (logger *Logger) Info(args ...interface{}) {
+	logger.Logger.Info(fmt.Sprint(args...))
+}
+
+// Infof logs a formatted message at info level.
+func (logger *Logger) Infof(format string, args ...interface{}) {
+	logger.Logger.Info(fmt.Sprintf(format, args...))
+}
+
+// Errorf logs a formatted message at error level.
+func (logger *Logger) Errorf(format string, args ...interface{}) {
+	logger.Logger.Error(fmt.Sprintf(format, args...))
+}
+
+// Sync is a no-op, the slog handlers created by New do not buffer the log
+// entries.
+func (logger *Logger) Sync() error {
+	return nil
+}
+`
@@ -0,0 +1,132 @@
+package dsl
+
+import (
+	"goa.design/goa/eval"
+	goaexpr "goa.design/goa/expr"
+	"goa.design/plugins/zaplogger/expr"
+
+	// Register code generators for the zaplogger plugin
+	_ "goa.design/plugins/zaplogger"
+)
+
+// Config defines the configuration of the generated zap logger. The settings
+// not defined by the DSL keep their default values: JSON encoding, info level,
+// sampling of 100 entries per second then 1 out of 100 and output to the
+// standard error. The generated log package exposes the configuration via
+// DefaultConfig and the example server lets the -log-level and -log-format
+// flags override it.
+//
+// Config must appear in an API expression.
+//
+// Config takes a DSL function as argument.
+//
+// Example:
+//
+//    import zaplogger "goa.design/plugins/zaplogger/dsl"
+//
+//    var _ = API("calc", func() {
+//        zaplogger.Config(func() {
+//            zaplogger.Encoding("console") // "json" or "console"
+//            zaplogger.Level("debug")      // Minimum enabled level
+//            zaplogger.Sampling(100, 100)  // Initial and thereafter sampling
+//            zaplogger.Output("stdout")    // One or more URLs or file paths
+//        })
+//    })
+//
+func Config(dsl func()) {
+	if _, ok := eval.Current().(*goaexpr.APIExpr); !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	c := expr.DefaultConfig()
+	if !eval.Execute(dsl, c) {
+		return
+	}
+	expr.Root.Config = c
+}
+
+// Encoding sets the log encoding, either "json" or "console".
+//
+// Encoding must appear in a Config expression.
+//
+// Example:
+//
+//     zaplogger.Config(func() {
+//         zaplogger.Encoding("console")
+//     })
+//
+func Encoding(enc string) {
+	switch c := eval.Current().(type) {
+	case *expr.ConfigExpr:
+		c.Encoding = enc
+	default:
+		eval.IncompatibleDSL()
+	}
+}
+
+// Level sets the minimum enabled logging level, one of "debug", "info",
+// "warn", "error", "dpanic", "panic" or "fatal".
+//
+// Level must appear in a Config expression.
+//
+// Example:
+//
+//     zaplogger.Config(func() {
+//         zaplogger.Level("debug")
+//     })
+//
+func Level(lvl string) {
+	switch c := eval.Current().(type) {
+	case *expr.ConfigExpr:
+		c.Level = lvl
+	default:
+		eval.IncompatibleDSL()
+	}
+}
+
+// Sampling sets the log sampling: every second the first initial entries with
+// the same level and message are logged, then only one out of thereafter.
+// Sampling(0, 0) disables sampling.
+//
+// Sampling must appear in a Config expression.
+//
+// Example:
+//
+//     zaplogger.Config(func() {
+//         zaplogger.Sampling(100, 100)
+//     })
+//
+func Sampling(initial, thereafter int) {
+	if initial < 0 || thereafter < 0 {
+		eval.ReportError("sampling values cannot be negative, got %d and %d", initial, thereafter)
+		return
+	}
+	switch c := eval.Current().(type) {
+	case *expr.ConfigExpr:
+		c.SamplingInitial = initial
+		c.SamplingThereafter = thereafter
+	default:
+		eval.IncompatibleDSL()
+	}
+}
+
+// Output sets the URLs or file paths the logs are written to, "stdout" and
+// "stderr" denote the standard streams. Output replaces the default
+// "stderr" output.
+//
+// Output must appear in a Config expression.
+//
+// Example:
+//
+//     zaplogger.Config(func() {
+//         zaplogger.Output("stdout", "/var/log/calc.log")
+//     })
+//
+func Output(paths ...string) {
+	switch c := eval.Current().(type) {
+	case *expr.ConfigExpr:
+		c.Outputs = paths
+	default:
+		eval.IncompatibleDSL()
+	}
+}
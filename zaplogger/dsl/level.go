@@ -0,0 +1,43 @@
+package dsl
+
+import (
+	"goa.design/goa/eval"
+	goaexpr "goa.design/goa/expr"
+	"goa.design/plugins/zaplogger/expr"
+)
+
+// LevelEndpoint serves the logger level on the given HTTP path. GET requests
+// return the current level and PUT requests change it, both use the JSON
+// representation of zap.AtomicLevel, e.g. {"level":"debug"}. The example HTTP
+// server generated by "goa example" mounts the endpoint on its muxer.
+//
+// LevelEndpoint must appear in an API expression.
+//
+// LevelEndpoint takes the request path as first argument and an optional
+// security scheme that protects the endpoint as second argument.
+//
+// Example:
+//
+//    import zaplogger "goa.design/plugins/zaplogger/dsl"
+//
+//    var BasicAuth = BasicAuthSecurity("basic")
+//
+//    var _ = API("calc", func() {
+//        zaplogger.LevelEndpoint("/debug/loglevel", BasicAuth)
+//    })
+//
+func LevelEndpoint(path string, scheme ...*goaexpr.SchemeExpr) {
+	if _, ok := eval.Current().(*goaexpr.APIExpr); !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if len(scheme) > 1 {
+		eval.ReportError("too many arguments, expected a path and an optional security scheme")
+		return
+	}
+	l := &expr.LevelEndpointExpr{Path: path}
+	if len(scheme) == 1 {
+		l.Scheme = scheme[0]
+	}
+	expr.Root.LevelEndpoint = l
+}
@@ -20,6 +20,6 @@ func NewCalc(logger *log.Logger) calcsvc.Service {
 
 // Add implements add.
 func (s *calcSvc) Add(ctx context.Context, p *calcsvc.AddPayload) (res int, err error) {
-	s.logger.Info("calc.add")
+	log.FromContext(ctx).Info("calc.add")
 	return
 }
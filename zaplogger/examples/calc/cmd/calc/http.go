@@ -21,14 +21,6 @@ import (
 // URL. It shuts down the server if any error is received in the error channel.
 func handleHTTPServer(ctx context.Context, u *url.URL, calcEndpoints *calcsvc.Endpoints, wg *sync.WaitGroup, errc chan error, logger *log.Logger, debug bool) {
 
-	// Setup goa log adapter.
-	var (
-		adapter middleware.Logger
-	)
-	{
-		adapter = logger
-	}
-
 	// Provide the transport specific request decoder and response encoder.
 	// The goa http package has built-in support for JSON, XML and gob.
 	// Other encodings can be used by providing the corresponding functions,
@@ -42,7 +34,7 @@ func handleHTTPServer(ctx context.Context, u *url.URL, calcEndpoints *calcsvc.En
 	// HTTP requests to the service endpoints.
 	var mux goahttp.Muxer
 	{
-		mux = goahttp.NewMuxer()
+		mux = log.RouteMuxer(goahttp.NewMuxer())
 	}
 
 	// Wrap the endpoints with the transport specific layers. The generated
@@ -66,7 +58,9 @@ func handleHTTPServer(ctx context.Context, u *url.URL, calcEndpoints *calcsvc.En
 		if debug {
 			handler = httpmdlwr.Debug(mux, os.Stdout)(handler)
 		}
-		handler = httpmdlwr.Log(adapter)(handler)
+		handler = logger.Recover(enc)(handler)
+		handler = logger.Context()(handler)
+		handler = logger.HTTP()(handler)
 		handler = httpmdlwr.RequestID()(handler)
 	}
 
@@ -108,6 +102,6 @@ func errorHandler(logger *log.Logger) func(context.Context, http.ResponseWriter,
 	return func(ctx context.Context, w http.ResponseWriter, err error) {
 		id := ctx.Value(middleware.RequestIDKey).(string)
 		w.Write([]byte("[" + id + "] encoding: " + err.Error()))
-		logger.With(zap.String("id", id)).Error(err.Error())
+		logger.With(zap.String("request_id", id)).Error(err.Error())
 	}
 }
@@ -26,6 +26,10 @@ func main() {
 		secureF   = flag.Bool("secure", false, "Use secure scheme (https or grpcs)")
 		dbgF      = flag.Bool("debug", false, "Log request and response bodies")
 	)
+	var (
+		logLevelF  = flag.String("log-level", "", "Minimum enabled logging level (overrides level specified in design)")
+		logFormatF = flag.String("log-format", "", "Log encoding, json or console (overrides encoding specified in design)")
+	)
 	flag.Parse()
 
 	// Setup logger. Replace logger with your own log package of choice.
@@ -33,7 +37,19 @@ func main() {
 		logger *log.Logger
 	)
 	{
-		logger = log.New("calc", false)
+		cfg := log.DefaultConfig()
+		if *logLevelF != "" {
+			cfg.Level = *logLevelF
+		}
+		if *logFormatF != "" {
+			cfg.Encoding = *logFormatF
+		}
+		var err error
+		logger, err = log.NewFromConfig("calc", cfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid logger configuration: %s\n", err)
+			os.Exit(1)
+		}
 	}
 
 	// Initialize the services.
@@ -51,6 +67,7 @@ func main() {
 	)
 	{
 		calcEndpoints = calcsvc.NewEndpoints(calcSvc)
+		calcEndpoints.Use(calcsvc.LogEndpoint(logger))
 	}
 
 	// Create channel used by both the signal handler and server goroutines
@@ -129,4 +146,8 @@ func main() {
 
 	wg.Wait()
 	logger.Info("exited")
+
+	// Flush the buffered log entries. Sync may fail when the logs are written
+	// to a terminal, there is nothing left to report the error to anyway.
+	logger.Sync()
 }
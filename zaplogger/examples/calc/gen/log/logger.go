@@ -9,24 +9,43 @@
 package log
 
 import (
+	"context"
+
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"goa.design/goa/middleware"
 )
 
 // Logger is an adapted zap logger
 type Logger struct {
 	*zap.SugaredLogger
+	// level is the logger level, it is shared with the child loggers.
+	level zap.AtomicLevel
 }
 
-// New creates a new zap logger
-func New(serviceName string, production bool) *Logger {
-
+// New creates a new zap logger. It returns an error if the zap logger cannot be
+// built. Call Sync before exiting to flush the buffered log entries.
+func New(serviceName string, production bool) (*Logger, error) {
+	cfg := zap.NewDevelopmentConfig()
 	if production {
-		l, _ := zap.NewProduction()
-		return &Logger{l.Sugar().With(zap.String("service", serviceName))}
-	} else {
-		l, _ := zap.NewDevelopment()
-		return &Logger{l.Sugar().With(zap.String("service", serviceName))}
+		cfg = zap.NewProductionConfig()
+	}
+	l, err := cfg.Build()
+	if err != nil {
+		return nil, err
 	}
+	return &Logger{SugaredLogger: l.Sugar().With(zap.String("service", serviceName)), level: cfg.Level}, nil
+}
+
+// Level returns the logger level. Changing the level changes the level of the
+// logger and of all its child loggers.
+func (logger *Logger) Level() zap.AtomicLevel {
+	return logger.level
+}
+
+// with returns a child logger that adds the given fields to the entries.
+func (logger *Logger) with(fields ...zap.Field) *Logger {
+	return &Logger{SugaredLogger: logger.Desugar().With(fields...).Sugar(), level: logger.level}
 }
 
 // Log is called by the log middleware to log HTTP requests key values
@@ -34,3 +53,135 @@ func (logger *Logger) Log(keyvals ...interface{}) error {
 	logger.Infow("HTTP Request", keyvals...)
 	return nil
 }
+
+// Config is the zap logger configuration.
+type Config struct {
+	// Encoding is the log encoding, "json" or "console".
+	Encoding string
+	// Level is the minimum enabled logging level, e.g. "debug" or "info".
+	Level string
+	// SamplingInitial is the number of entries with the same level and
+	// message logged every second before sampling kicks in, zero disables
+	// sampling.
+	SamplingInitial int
+	// SamplingThereafter is the sampling rate of the entries logged after
+	// the first SamplingInitial ones.
+	SamplingThereafter int
+	// OutputPaths lists the URLs or file paths the logs are written to,
+	// "stdout" and "stderr" denote the standard streams.
+	OutputPaths []string
+}
+
+// DefaultConfig returns the logger configuration defined in the design.
+func DefaultConfig() *Config {
+	return &Config{
+		Encoding:           "json",
+		Level:              "info",
+		SamplingInitial:    100,
+		SamplingThereafter: 100,
+		OutputPaths:        []string{"stderr"},
+	}
+}
+
+// NewFromConfig creates a new zap logger using the given configuration. It
+// returns an error if the configuration is invalid or if the outputs cannot be
+// opened. Call Sync before exiting to flush the buffered log entries.
+func NewFromConfig(serviceName string, cfg *Config) (*Logger, error) {
+	var lvl zapcore.Level
+	if err := lvl.UnmarshalText([]byte(cfg.Level)); err != nil {
+		return nil, err
+	}
+	level := zap.NewAtomicLevelAt(lvl)
+	zc := zap.Config{
+		Level:            level,
+		Encoding:         cfg.Encoding,
+		EncoderConfig:    zap.NewProductionEncoderConfig(),
+		OutputPaths:      cfg.OutputPaths,
+		ErrorOutputPaths: []string{"stderr"},
+	}
+	if cfg.Encoding == "console" {
+		zc.EncoderConfig = zap.NewDevelopmentEncoderConfig()
+	}
+	if cfg.SamplingInitial > 0 {
+		zc.Sampling = &zap.SamplingConfig{Initial: cfg.SamplingInitial, Thereafter: cfg.SamplingThereafter}
+	}
+	l, err := zc.Build()
+	if err != nil {
+		return nil, err
+	}
+	return &Logger{SugaredLogger: l.Sugar().With(zap.String("service", serviceName)), level: level}, nil
+}
+
+// loggerKey is the context key used to store the request scoped loggers.
+type loggerKey struct{}
+
+// nopLogger is the logger returned by FromContext when the context does not
+// hold one.
+var nopLogger = &Logger{SugaredLogger: zap.NewNop().Sugar(), level: zap.NewAtomicLevel()}
+
+// WithContext returns a copy of ctx that holds logger.
+func WithContext(ctx context.Context, logger *Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext returns the logger held by ctx. It returns a logger that
+// discards all the entries if ctx does not hold one.
+func FromContext(ctx context.Context) *Logger {
+	if l, ok := ctx.Value(loggerKey{}).(*Logger); ok {
+		return l
+	}
+	return nopLogger
+}
+
+// contextFields returns the request_id, trace_id and span_id fields built from
+// the IDs stored in ctx by the goa request ID and tracing middlewares. The
+// fields whose ID is not set are omitted.
+func contextFields(ctx context.Context) []zap.Field {
+	var fields []zap.Field
+	if id, ok := ctx.Value(middleware.RequestIDKey).(string); ok && id != "" {
+		fields = append(fields, zap.String("request_id", id))
+	}
+	if id, ok := ctx.Value(middleware.TraceIDKey).(string); ok && id != "" {
+		fields = append(fields, zap.String("trace_id", id))
+	}
+	if id, ok := ctx.Value(middleware.TraceSpanIDKey).(string); ok && id != "" {
+		fields = append(fields, zap.String("span_id", id))
+	}
+	return fields
+}
+
+// RecoverOption configures the panic recovery middlewares.
+type RecoverOption func(*recoverOptions)
+
+// recoverOptions is the panic recovery middlewares configuration.
+type recoverOptions struct {
+	// onPanic is called each time a panic is recovered.
+	onPanic func()
+}
+
+// WithPanicCounter sets a function called each time a panic is recovered, e.g.
+// to increment a metrics counter.
+func WithPanicCounter(inc func()) RecoverOption {
+	return func(o *recoverOptions) {
+		o.onPanic = inc
+	}
+}
+
+// newRecoverOptions returns the panic recovery configuration built from opts.
+func newRecoverOptions(opts []RecoverOption) *recoverOptions {
+	o := &recoverOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// logPanic logs the recovered panic p at error level with its stack trace and
+// the given fields and calls the panic counter if any.
+func (logger *Logger) logPanic(o *recoverOptions, p interface{}, fields ...zap.Field) {
+	fields = append(fields, zap.Any("panic", p), zap.Stack("stack"))
+	logger.Desugar().Error("panic recovered", fields...)
+	if o.onPanic != nil {
+		o.onPanic()
+	}
+}
@@ -0,0 +1,83 @@
+package expr
+
+import (
+	"goa.design/goa/eval"
+)
+
+type (
+	// ConfigExpr describes the configuration of the generated zap logger.
+	ConfigExpr struct {
+		// Encoding is the log encoding, "json" or "console".
+		Encoding string
+		// Level is the minimum enabled logging level.
+		Level string
+		// SamplingInitial is the number of entries with the same level and
+		// message logged every second before sampling kicks in, zero
+		// disables sampling.
+		SamplingInitial int
+		// SamplingThereafter is the sampling rate of the entries logged
+		// after the first SamplingInitial ones.
+		SamplingThereafter int
+		// Outputs lists the URLs or file paths the logs are written to.
+		Outputs []string
+	}
+)
+
+// Encodings lists the supported log encodings.
+var Encodings = []string{"json", "console"}
+
+// Levels lists the supported logging levels.
+var Levels = []string{"debug", "info", "warn", "error", "dpanic", "panic", "fatal"}
+
+// DefaultConfig returns the configuration used when the design does not
+// define one: JSON encoded logs of level info and above written to the
+// standard error with the zap production sampling.
+func DefaultConfig() *ConfigExpr {
+	return &ConfigExpr{
+		Encoding:           "json",
+		Level:              "info",
+		SamplingInitial:    100,
+		SamplingThereafter: 100,
+		Outputs:            []string{"stderr"},
+	}
+}
+
+// Config returns the logger configuration defined in the design or the
+// default configuration if there isn't one.
+func Config() *ConfigExpr {
+	if Root.Config == nil {
+		return DefaultConfig()
+	}
+	return Root.Config
+}
+
+// EvalName returns the generic expression name used in error messages.
+func (c *ConfigExpr) EvalName() string {
+	return "zap logger configuration"
+}
+
+// Validate ensures the encoding and level are supported and that the logs are
+// written somewhere.
+func (c *ConfigExpr) Validate() *eval.ValidationErrors {
+	verr := new(eval.ValidationErrors)
+	if !contains(Encodings, c.Encoding) {
+		verr.Add(c, "invalid encoding %q, must be one of %v", c.Encoding, Encodings)
+	}
+	if !contains(Levels, c.Level) {
+		verr.Add(c, "invalid level %q, must be one of %v", c.Level, Levels)
+	}
+	if len(c.Outputs) == 0 {
+		verr.Add(c, "at least one output is required")
+	}
+	return verr
+}
+
+// contains returns true if vals contains val.
+func contains(vals []string, val string) bool {
+	for _, v := range vals {
+		if v == val {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,34 @@
+package expr
+
+import (
+	"strings"
+
+	"goa.design/goa/eval"
+	"goa.design/goa/expr"
+)
+
+type (
+	// LevelEndpointExpr describes the HTTP endpoint that reads and updates
+	// the logger level at run time.
+	LevelEndpointExpr struct {
+		// Path is the HTTP request path of the endpoint.
+		Path string
+		// Scheme is the security scheme that protects the endpoint, nil
+		// if the endpoint is not secured.
+		Scheme *expr.SchemeExpr
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (l *LevelEndpointExpr) EvalName() string {
+	return "log level endpoint " + l.Path
+}
+
+// Validate ensures the path is absolute.
+func (l *LevelEndpointExpr) Validate() *eval.ValidationErrors {
+	verr := new(eval.ValidationErrors)
+	if !strings.HasPrefix(l.Path, "/") {
+		verr.Add(l, "invalid path, must start with /")
+	}
+	return verr
+}
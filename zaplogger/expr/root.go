@@ -0,0 +1,61 @@
+package expr
+
+import (
+	"goa.design/goa/eval"
+	"goa.design/goa/expr"
+)
+
+// Root is the design root expression.
+var Root = &RootExpr{}
+
+type (
+	// RootExpr keeps track of the zap logger settings defined in the design.
+	RootExpr struct {
+		// Config is the logger configuration defined at the API level, nil
+		// if the design does not use the Config DSL.
+		Config *ConfigExpr
+		// LevelEndpoint is the endpoint serving the logger level, nil if
+		// the design does not use the LevelEndpoint DSL.
+		LevelEndpoint *LevelEndpointExpr
+	}
+)
+
+// Register design root with eval engine.
+func init() {
+	eval.Register(Root)
+}
+
+// Reset clears the zap logger settings recorded by a previous evaluation of
+// the DSL. It must be called before evaluating a new design in the same
+// process, e.g. in tests. Root keeps pointing to the same expression so that
+// it stays registered with the eval engine.
+func Reset() {
+	*Root = RootExpr{}
+}
+
+// EvalName returns the name used in error messages.
+func (r *RootExpr) EvalName() string {
+	return "zaplogger plugin"
+}
+
+// WalkSets iterates over the logger configuration and level endpoint.
+func (r *RootExpr) WalkSets(walk eval.SetWalker) {
+	if r.Config != nil {
+		walk(eval.ExpressionSet{r.Config})
+	}
+	if r.LevelEndpoint != nil {
+		walk(eval.ExpressionSet{r.LevelEndpoint})
+	}
+}
+
+// DependsOn tells the eval engine to run the goa DSL first.
+func (r *RootExpr) DependsOn() []eval.Root {
+	return []eval.Root{expr.Root}
+}
+
+// Packages returns the import path to the Go packages that make
+// up the DSL. This is used to skip frames that point to files
+// in these packages when computing the location of errors.
+func (r *RootExpr) Packages() []string {
+	return []string{"goa.design/plugins/zaplogger/dsl"}
+}
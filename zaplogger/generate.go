@@ -2,85 +2,59 @@ package zaplogger
 
 import (
 	"fmt"
-	"path"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	"goa.design/goa/codegen"
 	"goa.design/goa/eval"
 	"goa.design/goa/expr"
+	"goa.design/plugins/logger"
+	zaploggerexpr "goa.design/plugins/zaplogger/expr"
 )
 
-type fileToModify struct {
-	file        *codegen.File
-	path        string
-	serviceName string
-	isMain      bool
+// Backend is the zap logger plugin backend.
+var Backend = &logger.Backend{
+	Name:          "zaplogger",
+	Files:         generateAll,
+	UpdateMain:    updateMain,
+	UpdateService: updateService,
 }
 
 // Register the plugin Generator functions.
 func init() {
-	codegen.RegisterPluginFirst("zaplogger", "gen", nil, Generate)
-	codegen.RegisterPluginLast("zaplogger-updater", "example", nil, UpdateExample)
+	logger.Register(Backend)
 }
 
 // Generate generates zap logger specific files.
 func Generate(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
-	for _, root := range roots {
-		if r, ok := root.(*expr.RootExpr); ok {
-			files = append(files, GenerateFiles(genpkg, r)...)
-		}
-	}
-	return files, nil
+	return Backend.Generate(genpkg, roots, files)
 }
 
 // UpdateExample modifies the example generated files by replacing
 // the log import reference when needed
 // It also modify the initially generated main and service files
 func UpdateExample(genpkg string, roots []eval.Root, files []*codegen.File) ([]*codegen.File, error) {
+	return Backend.UpdateExample(genpkg, roots, files)
+}
 
-	filesToModify := []*fileToModify{}
-
-	for _, root := range roots {
-		if r, ok := root.(*expr.RootExpr); ok {
-
-			// Add the generated main files
-			for _, svr := range r.API.Servers {
-				pkg := codegen.SnakeCase(codegen.Goify(svr.Name, true))
-				filesToModify = append(filesToModify,
-					&fileToModify{path: filepath.Join("cmd", pkg, "main.go"), serviceName: svr.Name, isMain: true})
-				filesToModify = append(filesToModify,
-					&fileToModify{path: filepath.Join("cmd", pkg, "http.go"), serviceName: svr.Name, isMain: true})
-				filesToModify = append(filesToModify,
-					&fileToModify{path: filepath.Join("cmd", pkg, "grpc.go"), serviceName: svr.Name, isMain: true})
-			}
-
-			// Add the generated service files
-			for _, svc := range r.API.HTTP.Services {
-				servicePath := codegen.SnakeCase(svc.Name()) + ".go"
-				filesToModify = append(filesToModify, &fileToModify{path: servicePath, serviceName: svc.Name(), isMain: false})
-			}
-
-			// Update the added files
-			for _, fileToModify := range filesToModify {
-				for _, file := range files {
-					if file.Path == fileToModify.path {
-						fileToModify.file = file
-						updateExampleFile(genpkg, r, fileToModify)
-						break
-					}
-				}
-			}
-		}
-	}
-
-	return files, nil
+// generateAll returns the log package files and the service files.
+func generateAll(genpkg string, root *expr.RootExpr) []*codegen.File {
+	return append(GenerateFiles(genpkg, root), GenerateServiceFiles(genpkg, root)...)
 }
 
 // GenerateFiles create log specific files
 func GenerateFiles(genpkg string, root *expr.RootExpr) []*codegen.File {
-	fw := make([]*codegen.File, 1)
-	fw[0] = GenerateLoggerFile(genpkg)
+	fw := []*codegen.File{GenerateLoggerFile(genpkg)}
+	if len(root.API.HTTP.Services) > 0 {
+		fw = append(fw, GenerateHTTPFile(genpkg))
+	}
+	if len(root.API.GRPC.Services) > 0 {
+		fw = append(fw, GenerateGRPCFile(genpkg))
+	}
+	if zaploggerexpr.Root.LevelEndpoint != nil && len(root.API.HTTP.Services) > 0 {
+		fw = append(fw, GenerateLevelFile(genpkg, zaploggerexpr.Root.LevelEndpoint))
+	}
 	return fw
 }
 
@@ -90,8 +64,10 @@ func GenerateLoggerFile(genpkg string) *codegen.File {
 	title := fmt.Sprint("Zap logger implementation")
 	sections := []*codegen.SectionTemplate{
 		codegen.Header(title, "log", []*codegen.ImportSpec{
+			{Path: "context"},
 			{Path: "go.uber.org/zap"},
-			{Path: "goa.design/goa/http/middleware"},
+			{Path: "go.uber.org/zap/zapcore"},
+			{Path: "goa.design/goa/middleware"},
 		}),
 	}
 
@@ -99,66 +75,354 @@ func GenerateLoggerFile(genpkg string) *codegen.File {
 		Name:   "zaplooger",
 		Source: loggerT,
 	})
+	sections = append(sections, &codegen.SectionTemplate{
+		Name:   "zaplogger-config",
+		Source: configT,
+		Data:   zaploggerexpr.Config(),
+	})
+	sections = append(sections, &codegen.SectionTemplate{
+		Name:   "zaplogger-context",
+		Source: contextT,
+	})
+	sections = append(sections, &codegen.SectionTemplate{
+		Name:   "zaplogger-recover",
+		Source: recoverT,
+	})
+
+	return &codegen.File{Path: path, SectionTemplates: sections}
+}
+
+// GenerateHTTPFile returns the generated zap HTTP access log middleware file.
+func GenerateHTTPFile(genpkg string) *codegen.File {
+	path := filepath.Join(codegen.Gendir, "log", "http.go")
+	sections := []*codegen.SectionTemplate{
+		codegen.Header("Zap HTTP access log middleware", "log", []*codegen.ImportSpec{
+			{Path: "bufio"},
+			{Path: "context"},
+			{Path: "fmt"},
+			{Path: "net"},
+			{Path: "net/http"},
+			{Path: "strings"},
+			{Path: "time"},
+			{Path: "go.uber.org/zap"},
+			{Path: "go.uber.org/zap/zapcore"},
+			{Path: "goa.design/goa", Name: "goa"},
+			{Path: "goa.design/goa/http", Name: "goahttp"},
+			{Path: "goa.design/goa/middleware"},
+		}),
+		{
+			Name:   "zaplogger-http",
+			Source: httpT,
+		},
+		{
+			Name:   "zaplogger-http-recover",
+			Source: httpRecoverT,
+		},
+	}
 
 	return &codegen.File{Path: path, SectionTemplates: sections}
 }
 
-func updateExampleFile(genpkg string, root *expr.RootExpr, f *fileToModify) {
+// GenerateGRPCFile returns the generated zap gRPC interceptors file.
+func GenerateGRPCFile(genpkg string) *codegen.File {
+	path := filepath.Join(codegen.Gendir, "log", "grpc.go")
+	sections := []*codegen.SectionTemplate{
+		codegen.Header("Zap gRPC interceptors", "log", []*codegen.ImportSpec{
+			{Path: "context"},
+			{Path: "time"},
+			{Path: "go.uber.org/zap"},
+			{Path: "go.uber.org/zap/zapcore"},
+			{Path: "google.golang.org/grpc"},
+			{Path: "google.golang.org/grpc/codes"},
+			{Path: "google.golang.org/grpc/metadata"},
+			{Path: "google.golang.org/grpc/peer"},
+			{Path: "google.golang.org/grpc/status"},
+			{Path: "goa.design/goa/middleware"},
+		}),
+		{
+			Name:   "zaplogger-grpc",
+			Source: grpcT,
+		},
+		{
+			Name:   "zaplogger-grpc-recover",
+			Source: grpcRecoverT,
+		},
+	}
 
-	header := f.file.SectionTemplates[0]
-	logPath := path.Join(genpkg, "log")
+	return &codegen.File{Path: path, SectionTemplates: sections}
+}
 
-	data := header.Data.(map[string]interface{})
-	specs := data["Imports"].([]*codegen.ImportSpec)
+// GenerateLevelFile returns the generated file serving the logger level over
+// HTTP.
+func GenerateLevelFile(genpkg string, l *zaploggerexpr.LevelEndpointExpr) *codegen.File {
+	path := filepath.Join(codegen.Gendir, "log", "level.go")
+	sections := []*codegen.SectionTemplate{
+		codegen.Header("Zap logger level endpoint", "log", []*codegen.ImportSpec{
+			{Path: "net/http"},
+			{Path: "strings"},
+			{Path: "goa.design/goa/http", Name: "goahttp"},
+			{Path: "goa.design/goa/security"},
+		}),
+		{
+			Name:   "zaplogger-level",
+			Source: levelT,
+			Data:   newLevelData(l),
+		},
+	}
+
+	return &codegen.File{Path: path, SectionTemplates: sections}
+}
+
+// levelData contains the data needed to render the logger level endpoint
+// template.
+type levelData struct {
+	// Path is the HTTP request path of the endpoint.
+	Path string
+	// SchemeName is the name of the security scheme protecting the
+	// endpoint, empty if the endpoint is not secured.
+	SchemeName string
+	// SchemeType is the name of the goa security package scheme type.
+	SchemeType string
+	// AuthFunc is the name of the goa security package authorization
+	// function type.
+	AuthFunc string
+	// Credentials is the Go code that initializes the request credentials
+	// and ok, which is false if the request has no credentials.
+	Credentials string
+	// Args lists the credential arguments given to the authorization
+	// function.
+	Args string
+	// AuthSignature is the signature of the authorization function.
+	AuthSignature string
+}
 
-	for _, spec := range specs {
-		if spec.Path == "log" {
-			spec.Name = "log"
-			spec.Path = logPath
+// newLevelData returns the data needed to render the given logger level
+// endpoint.
+func newLevelData(l *zaploggerexpr.LevelEndpointExpr) *levelData {
+	ld := &levelData{Path: l.Path}
+	if l.Scheme == nil {
+		return ld
+	}
+	ld.SchemeName = l.Scheme.SchemeName
+	switch l.Scheme.Kind {
+	case expr.BasicAuthKind:
+		ld.SchemeType, ld.AuthFunc, ld.Args = "BasicScheme", "AuthBasicFunc", "user, pass"
+		ld.Credentials = "user, pass, ok := r.BasicAuth()"
+		ld.AuthSignature = "ctx context.Context, user, pass string, scheme *security.BasicScheme"
+	case expr.APIKeyKind:
+		ld.SchemeType, ld.AuthFunc, ld.Args = "APIKeyScheme", "AuthAPIKeyFunc", "key"
+		if l.Scheme.In == "query" {
+			ld.Credentials = fmt.Sprintf("key := r.URL.Query().Get(%q)\nok := key != \"\"", l.Scheme.Name)
+		} else {
+			ld.Credentials = fmt.Sprintf("key := r.Header.Get(%q)\nok := key != \"\"", l.Scheme.Name)
 		}
+		ld.AuthSignature = "ctx context.Context, key string, scheme *security.APIKeyScheme"
+	case expr.JWTKind:
+		ld.SchemeType, ld.AuthFunc, ld.Args = "JWTScheme", "AuthJWTFunc", "token"
+		ld.Credentials = "token := strings.TrimPrefix(r.Header.Get(\"Authorization\"), \"Bearer \")\nok := token != \"\""
+		ld.AuthSignature = "ctx context.Context, token string, scheme *security.JWTScheme"
+	case expr.OAuth2Kind:
+		ld.SchemeType, ld.AuthFunc, ld.Args = "OAuth2Scheme", "AuthOAuth2Func", "token"
+		ld.Credentials = "token := strings.TrimPrefix(r.Header.Get(\"Authorization\"), \"Bearer \")\nok := token != \"\""
+		ld.AuthSignature = "ctx context.Context, token string, scheme *security.OAuth2Scheme"
+	default:
+		ld.SchemeName = ""
 	}
+	return ld
+}
 
-	if f.isMain {
+// endpointsRe matches the initialization of the service endpoints in the
+// example main.
+var endpointsRe = regexp.MustCompile(`(\{\{[^}]*\}\})Endpoints = (\{\{[^}]*\}\})\.NewEndpoints\((\{\{[^}]*\}\})Svc\)`)
 
-		codegen.AddImport(header, &codegen.ImportSpec{Path: "go.uber.org/zap"})
+// adapterRe matches the declaration of the goa log adapter used by the HTTP
+// and gRPC log middlewares in the example servers.
+var adapterRe = regexp.MustCompile(`// Setup goa log adapter\.\s*var \(\s*adapter middleware\.Logger\s*\)\s*\{\s*adapter = middleware\.NewLogger\(logger\)\s*\}\s*`)
 
-		for _, s := range f.file.SectionTemplates {
-			s.Source = strings.Replace(s.Source, `logger = log.New(os.Stderr, "[{{ .APIPkg }}] ", log.Ltime)`, `logger = log.New("{{ .APIPkg }}", false)`, 1)
-			s.Source = strings.Replace(s.Source, "adapter = middleware.NewLogger(logger)", "adapter = logger", 1)
-			s.Source = strings.Replace(s.Source, "handler = middleware.RequestID()(handler)",
-				`handler = middleware.PopulateRequestContext()(handler)
-				handler = middleware.RequestID(middleware.UseXRequestIDHeaderOption(true))(handler)`, 1)
-			s.Source = strings.Replace(s.Source, `logger.Printf("[%s] ERROR: %s", id, err.Error())`,
-				`logger.With(zap.String("id",id)).Error(err.Error())`, 1)
-			s.Source = strings.Replace(s.Source, "logger.Print(", "logger.Info(", -1)
-			s.Source = strings.Replace(s.Source, "logger.Printf(", "logger.Infof(", -1)
-			s.Source = strings.Replace(s.Source, "logger.Println(", "logger.Info(", -1)
-		}
-	} else {
-		for _, s := range f.file.SectionTemplates {
-			s.Source = strings.Replace(s.Source, "logger.Print(", "logger.Info(", -1)
-			s.Source = strings.Replace(s.Source, "logger.Printf(", "logger.Infof(", -1)
-			s.Source = strings.Replace(s.Source, "logger.Println(", "logger.Info(", -1)
-		}
+// updateMain replaces the goa log adapter and middlewares of the example
+// main files with the zap logger middlewares. It returns an error if the
+// files do not contain the sections modified by the plugin.
+func updateMain(genpkg string, root *expr.RootExpr, f *codegen.File) error {
+	codegen.AddImport(f.SectionTemplates[0], &codegen.ImportSpec{Path: "go.uber.org/zap"})
+
+	switch filepath.Base(f.Path) {
+	case "main.go":
+		return logger.ApplyHooks(f, mainHooks())
+	case "http.go":
+		return logger.ApplyHooks(f, httpHooks(f))
+	case "grpc.go":
+		return logger.ApplyHooks(f, grpcHooks())
+	}
+	return nil
+}
+
+// mainHooks returns the hooks applied to the example server main file.
+func mainHooks() []*logger.SectionHook {
+	return []*logger.SectionHook{
+		{
+			Snippet: "flag.Parse()",
+			Rewrite: func(_, s *codegen.SectionTemplate) error {
+				return logger.ReplaceSnippet(s, "flag.Parse()", logFlagsT)
+			},
+		},
+		{
+			Section: "server-main-logger",
+			Rewrite: func(_, s *codegen.SectionTemplate) error {
+				return logger.ReplaceSnippet(s, `logger = log.New(os.Stderr, "[{{ .APIPkg }}] ", log.Ltime)`, newLoggerT)
+			},
+		},
+		{
+			// Log the invocations of the service endpoints.
+			Snippet: ".NewEndpoints(",
+			Rewrite: func(_, s *codegen.SectionTemplate) error {
+				if !endpointsRe.MatchString(s.Source) {
+					return fmt.Errorf("section %q does not initialize the service endpoints, the goa templates may have changed", s.Name)
+				}
+				s.Source = endpointsRe.ReplaceAllString(s.Source, "$0\n\t\t${1}Endpoints.Use(${2}.LogEndpoint(logger))")
+				return nil
+			},
+		},
+	}
+}
+
+// httpHooks returns the hooks applied to the example HTTP server file f.
+func httpHooks(f *codegen.File) []*logger.SectionHook {
+	return []*logger.SectionHook{
+		{
+			// The zap access log middleware replaces the goa log adapter.
+			Section: "server-http-logger",
+			Rewrite: removeAdapter,
+		},
+		{
+			// Record the route path patterns in the access log entries.
+			Snippet: "mux = goahttp.NewMuxer()",
+			Rewrite: func(_, s *codegen.SectionTemplate) error {
+				return logger.ReplaceSnippet(s, "mux = goahttp.NewMuxer()", "mux = log.RouteMuxer(goahttp.NewMuxer())")
+			},
+		},
+		{
+			Section: "server-http-middleware",
+			Rewrite: func(_, s *codegen.SectionTemplate) error {
+				err := logger.ReplaceSnippet(s, "httpmdlwr.Log(adapter)(handler)", `logger.Recover(enc)(handler)
+		handler = logger.Context()(handler)
+		handler = logger.HTTP()(handler)`)
+				if err != nil {
+					return err
+				}
+				if l := zaploggerexpr.Root.LevelEndpoint; l != nil {
+					return mountLevel(f, s, newLevelData(l))
+				}
+				return nil
+			},
+		},
+		{
+			Snippet: `logger.Printf("[%s] ERROR: %s", id, err.Error())`,
+			Rewrite: func(_, s *codegen.SectionTemplate) error {
+				return logger.ReplaceSnippet(s, `logger.Printf("[%s] ERROR: %s", id, err.Error())`,
+					`logger.With(zap.String("request_id", id)).Error(err.Error())`)
+			},
+		},
 	}
 }
 
+// grpcHooks returns the hooks applied to the example gRPC server file.
+func grpcHooks() []*logger.SectionHook {
+	return []*logger.SectionHook{
+		{
+			// The zap interceptors replace the goa log adapter.
+			Snippet: "adapter = middleware.NewLogger(logger)",
+			Rewrite: removeAdapter,
+		},
+		{
+			Snippet: "grpcmdlwr.UnaryServerLog(adapter)",
+			Rewrite: func(_, s *codegen.SectionTemplate) error {
+				err := logger.ReplaceSnippet(s, "grpcmdlwr.UnaryServerLog(adapter)", `logger.UnaryServerInterceptor(),
+			logger.UnaryServerRecoverInterceptor()`)
+				if err != nil {
+					return err
+				}
+				// The stream interceptors are only rendered for services
+				// with streaming endpoints.
+				s.Source = strings.Replace(s.Source, "grpcmdlwr.StreamServerLog(adapter)", `logger.StreamServerInterceptor(),
+			logger.StreamServerRecoverInterceptor()`, 1)
+				return nil
+			},
+		},
+	}
+}
+
+// removeAdapter removes the declaration of the goa log adapter from the
+// section s.
+func removeAdapter(_, s *codegen.SectionTemplate) error {
+	if !adapterRe.MatchString(s.Source) {
+		return fmt.Errorf("section %q does not define adapter, the goa templates may have changed", s.Name)
+	}
+	s.Source = adapterRe.ReplaceAllString(s.Source, "")
+	return nil
+}
+
+// updateService makes the example service methods log using the request
+// scoped logger.
+func updateService(genpkg string, root *expr.RootExpr, f *codegen.File) {
+	for _, s := range f.SectionTemplates {
+		s.Source = strings.Replace(s.Source, "s.logger.Print(", "log.FromContext(ctx).Info(", -1)
+	}
+}
+
+// mountLevel mounts the logger level endpoint in the section s of the example
+// HTTP server file f. It also adds the stub of the authorization function if
+// the endpoint is secured.
+func mountLevel(f *codegen.File, s *codegen.SectionTemplate, ld *levelData) error {
+	const wrap = "// Wrap the multiplexer with additional middlewares."
+	if ld.SchemeName == "" {
+		return logger.ReplaceSnippet(s, wrap, mountLevelT)
+	}
+	if err := logger.ReplaceSnippet(s, wrap, mountSecureLevelT); err != nil {
+		return err
+	}
+	header := f.SectionTemplates[0]
+	codegen.AddImport(header, &codegen.ImportSpec{Path: "context"})
+	codegen.AddImport(header, &codegen.ImportSpec{Path: "fmt"})
+	codegen.AddImport(header, &codegen.ImportSpec{Path: "goa.design/goa/security"})
+	f.SectionTemplates = append(f.SectionTemplates, &codegen.SectionTemplate{
+		Name:   "zaplogger-level-auth",
+		Source: levelAuthT,
+		Data:   ld,
+	})
+	return nil
+}
+
 const loggerT = `
 // Logger is an adapted zap logger
 type Logger struct {
 	*zap.SugaredLogger
+	// level is the logger level, it is shared with the child loggers.
+	level zap.AtomicLevel
 }
 
-// New creates a new zap logger
-func New(serviceName string, production bool) *Logger {
-
+// New creates a new zap logger. It returns an error if the zap logger cannot be
+// built. Call Sync before exiting to flush the buffered log entries.
+func New(serviceName string, production bool) (*Logger, error) {
+	cfg := zap.NewDevelopmentConfig()
 	if production {
-		l, _ := zap.NewProduction()
-		return &Logger{l.Sugar().With(zap.String("service", serviceName))}
-	} else {
-		l, _ := zap.NewDevelopment()
-		return &Logger{l.Sugar().With(zap.String("service", serviceName))}
+		cfg = zap.NewProductionConfig()
+	}
+	l, err := cfg.Build()
+	if err != nil {
+		return nil, err
 	}
+	return &Logger{SugaredLogger: l.Sugar().With(zap.String("service", serviceName)), level: cfg.Level}, nil
+}
+
+// Level returns the logger level. Changing the level changes the level of the
+// logger and of all its child loggers.
+func (logger *Logger) Level() zap.AtomicLevel {
+	return logger.level
+}
+
+// with returns a child logger that adds the given fields to the entries.
+func (logger *Logger) with(fields ...zap.Field) *Logger {
+	return &Logger{SugaredLogger: logger.Desugar().With(fields...).Sugar(), level: logger.level}
 }
 
 // Log is called by the log middleware to log HTTP requests key values
@@ -167,3 +431,604 @@ func (logger *Logger) Log(keyvals ...interface{}) error {
 	return nil
 }
 `
+
+// input: expr.ConfigExpr
+const configT = `
+// Config is the zap logger configuration.
+type Config struct {
+	// Encoding is the log encoding, "json" or "console".
+	Encoding string
+	// Level is the minimum enabled logging level, e.g. "debug" or "info".
+	Level string
+	// SamplingInitial is the number of entries with the same level and
+	// message logged every second before sampling kicks in, zero disables
+	// sampling.
+	SamplingInitial int
+	// SamplingThereafter is the sampling rate of the entries logged after
+	// the first SamplingInitial ones.
+	SamplingThereafter int
+	// OutputPaths lists the URLs or file paths the logs are written to,
+	// "stdout" and "stderr" denote the standard streams.
+	OutputPaths []string
+}
+
+// DefaultConfig returns the logger configuration defined in the design.
+func DefaultConfig() *Config {
+	return &Config{
+		Encoding:           {{ printf "%q" .Encoding }},
+		Level:              {{ printf "%q" .Level }},
+		SamplingInitial:    {{ .SamplingInitial }},
+		SamplingThereafter: {{ .SamplingThereafter }},
+		OutputPaths:        []string{ {{- range $i, $o := .Outputs }}{{ if $i }}, {{ end }}{{ printf "%q" $o }}{{ end -}} },
+	}
+}
+
+// NewFromConfig creates a new zap logger using the given configuration. It
+// returns an error if the configuration is invalid or if the outputs cannot be
+// opened. Call Sync before exiting to flush the buffered log entries.
+func NewFromConfig(serviceName string, cfg *Config) (*Logger, error) {
+	var lvl zapcore.Level
+	if err := lvl.UnmarshalText([]byte(cfg.Level)); err != nil {
+		return nil, err
+	}
+	level := zap.NewAtomicLevelAt(lvl)
+	zc := zap.Config{
+		Level:            level,
+		Encoding:         cfg.Encoding,
+		EncoderConfig:    zap.NewProductionEncoderConfig(),
+		OutputPaths:      cfg.OutputPaths,
+		ErrorOutputPaths: []string{"stderr"},
+	}
+	if cfg.Encoding == "console" {
+		zc.EncoderConfig = zap.NewDevelopmentEncoderConfig()
+	}
+	if cfg.SamplingInitial > 0 {
+		zc.Sampling = &zap.SamplingConfig{Initial: cfg.SamplingInitial, Thereafter: cfg.SamplingThereafter}
+	}
+	l, err := zc.Build()
+	if err != nil {
+		return nil, err
+	}
+	return &Logger{SugaredLogger: l.Sugar().With(zap.String("service", serviceName)), level: level}, nil
+}
+`
+
+// logFlagsT declares the example server flags that override the logger
+// configuration defined in the design.
+const logFlagsT = `var (
+		logLevelF  = flag.String("log-level", "", "Minimum enabled logging level (overrides level specified in design)")
+		logFormatF = flag.String("log-format", "", "Log encoding, json or console (overrides encoding specified in design)")
+	)
+	flag.Parse()`
+
+// newLoggerT creates the example server logger from the design configuration
+// and the command line flags.
+const newLoggerT = `cfg := log.DefaultConfig()
+		if *logLevelF != "" {
+			cfg.Level = *logLevelF
+		}
+		if *logFormatF != "" {
+			cfg.Encoding = *logFormatF
+		}
+		var err error
+		logger, err = log.NewFromConfig("{{ .APIPkg }}", cfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid logger configuration: %s\n", err)
+			os.Exit(1)
+		}`
+
+// input: none
+const httpT = `
+// HTTPOption configures the HTTP access log middleware.
+type HTTPOption func(*httpOptions)
+
+// httpOptions is the HTTP access log middleware configuration.
+type httpOptions struct {
+	// levels maps the response status classes (e.g. 4 for 4xx) to the level
+	// of the access log entries.
+	levels map[int]zapcore.Level
+}
+
+// WithStatusLevel sets the level of the entries logged for the responses whose
+// status code belongs to the given class, e.g. 4 for 4xx responses. By default
+// 4xx responses are logged at warn level, 5xx responses at error level and the
+// other responses at info level.
+func WithStatusLevel(class int, lvl zapcore.Level) HTTPOption {
+	return func(o *httpOptions) {
+		o.levels[class] = lvl
+	}
+}
+
+// HTTP returns a middleware that logs one entry per HTTP request with the
+// request ID, method, path, path pattern, remote address and user agent
+// together with the response status, size and latency. The path pattern is
+// only logged if the muxer is wrapped with RouteMuxer.
+func (logger *Logger) HTTP(opts ...HTTPOption) func(http.Handler) http.Handler {
+	o := &httpOptions{levels: map[int]zapcore.Level{
+		4: zapcore.WarnLevel,
+		5: zapcore.ErrorLevel,
+	}}
+	for _, opt := range opts {
+		opt(o)
+	}
+	l := logger.Desugar()
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			started := time.Now()
+			e := &httpEntry{ResponseWriter: w, status: http.StatusOK}
+			h.ServeHTTP(e, r.WithContext(context.WithValue(r.Context(), httpEntryKey, e)))
+
+			lvl, ok := o.levels[e.status/100]
+			if !ok {
+				lvl = zapcore.InfoLevel
+			}
+			ce := l.Check(lvl, "HTTP request")
+			if ce == nil {
+				return
+			}
+			id, _ := r.Context().Value(middleware.RequestIDKey).(string)
+			ce.Write(
+				zap.String("request_id", id),
+				zap.String("method", r.Method),
+				zap.String("path", r.URL.Path),
+				zap.String("pattern", e.pattern),
+				zap.Int("status", e.status),
+				zap.Int("bytes", e.bytes),
+				zap.Duration("latency", time.Since(started)),
+				zap.String("remote_addr", remoteAddr(r)),
+				zap.String("user_agent", r.UserAgent()),
+			)
+		})
+	}
+}
+
+// Context returns a middleware that stores in the request context a child of
+// logger carrying the request ID and the trace and span IDs, see FromContext.
+// The middleware must be wrapped by the goa request ID and tracing
+// middlewares so that the IDs are set when it runs.
+func (logger *Logger) Context() func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			h.ServeHTTP(w, r.WithContext(WithContext(ctx, logger.with(contextFields(ctx)...))))
+		})
+	}
+}
+
+// RouteMuxer wraps mux so that the entries logged by the HTTP access log
+// middleware include the path pattern of the routes serving the requests.
+func RouteMuxer(mux goahttp.Muxer) goahttp.Muxer {
+	return &routeMuxer{Muxer: mux}
+}
+
+// routeMuxer is a muxer that records the path pattern of the served routes in
+// the HTTP access log entries.
+type routeMuxer struct {
+	goahttp.Muxer
+}
+
+// Handle registers the handler for the given method and pattern.
+func (m *routeMuxer) Handle(method, pattern string, handler http.HandlerFunc) {
+	m.Muxer.Handle(method, pattern, func(w http.ResponseWriter, r *http.Request) {
+		if e, ok := r.Context().Value(httpEntryKey).(*httpEntry); ok {
+			e.pattern = pattern
+		}
+		handler(w, r)
+	})
+}
+
+// httpEntry records the response status and size and the route path pattern
+// of a request.
+type httpEntry struct {
+	http.ResponseWriter
+	status  int
+	bytes   int
+	pattern string
+}
+
+// WriteHeader records the response status code.
+func (e *httpEntry) WriteHeader(code int) {
+	e.status = code
+	e.ResponseWriter.WriteHeader(code)
+}
+
+// Write records the response size.
+func (e *httpEntry) Write(b []byte) (int, error) {
+	n, err := e.ResponseWriter.Write(b)
+	e.bytes += n
+	return n, err
+}
+
+// Hijack implements http.Hijacker so that the websocket endpoints can take over
+// the connection.
+func (e *httpEntry) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := e.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("response writer %T does not implement http.Hijacker", e.ResponseWriter)
+	}
+	conn, rw, err := h.Hijack()
+	if err == nil {
+		e.status = http.StatusSwitchingProtocols
+	}
+	return conn, rw, err
+}
+
+// Flush implements http.Flusher so that the streaming endpoints can flush the
+// buffered response data.
+func (e *httpEntry) Flush() {
+	if f, ok := e.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// httpEntryKeyType is the type of the context key used to store the HTTP access
+// log entries.
+type httpEntryKeyType struct{}
+
+// httpEntryKey is the context key used to store the HTTP access log entries.
+var httpEntryKey = httpEntryKeyType{}
+
+// remoteAddr returns the address of the client that made the request, using
+// the X-Forwarded-For header when set by a proxy.
+func remoteAddr(r *http.Request) string {
+	if f := r.Header.Get("X-Forwarded-For"); f != "" {
+		return strings.TrimSpace(strings.Split(f, ",")[0])
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+`
+
+// input: none
+const grpcT = `
+// UnaryServerInterceptor returns a gRPC unary server interceptor that logs one
+// entry per request with the full method name, status code, duration, peer
+// address and request ID.
+func (logger *Logger) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	l := logger.Desugar()
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		started := time.Now()
+		resp, err := handler(logger.grpcContext(ctx), req)
+		logGRPC(ctx, l, "gRPC request", info.FullMethod, err, started)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor returns a gRPC stream server interceptor that logs one
+// entry per stream with the full method name, status code, duration, peer
+// address and request ID.
+func (logger *Logger) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	l := logger.Desugar()
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		started := time.Now()
+		err := handler(srv, &contextStream{ServerStream: ss, ctx: logger.grpcContext(ss.Context())})
+		logGRPC(ss.Context(), l, "gRPC stream", info.FullMethod, err, started)
+		return err
+	}
+}
+
+// contextStream is a server stream whose context holds the request scoped
+// logger.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the stream context.
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// grpcContext returns a copy of ctx that holds a child of logger carrying the
+// request ID and the trace and span IDs, see FromContext.
+func (logger *Logger) grpcContext(ctx context.Context) context.Context {
+	fields := contextFields(ctx)
+	if _, ok := ctx.Value(middleware.RequestIDKey).(string); !ok {
+		if id := grpcRequestID(ctx); id != "" {
+			fields = append(fields, zap.String("request_id", id))
+		}
+	}
+	return WithContext(ctx, logger.with(fields...))
+}
+
+// UnaryClientInterceptor returns a gRPC unary client interceptor that logs one
+// entry per request with the full method name, status code, duration and
+// server address.
+func (logger *Logger) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	l := logger.Desugar()
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		started := time.Now()
+		var p peer.Peer
+		err := invoker(ctx, method, req, reply, cc, append(opts, grpc.Peer(&p))...)
+		logGRPC(peer.NewContext(ctx, &p), l, "gRPC call", method, err, started)
+		return err
+	}
+}
+
+// StreamClientInterceptor returns a gRPC stream client interceptor that logs
+// one entry per stream creation with the full method name, status code,
+// duration and server address.
+func (logger *Logger) StreamClientInterceptor() grpc.StreamClientInterceptor {
+	l := logger.Desugar()
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		started := time.Now()
+		var p peer.Peer
+		cs, err := streamer(ctx, desc, cc, method, append(opts, grpc.Peer(&p))...)
+		logGRPC(peer.NewContext(ctx, &p), l, "gRPC stream", method, err, started)
+		return cs, err
+	}
+}
+
+// logGRPC logs a gRPC request. OK responses are logged at info level, the
+// errors caused by the client at warn level and the other errors at error
+// level.
+func logGRPC(ctx context.Context, l *zap.Logger, msg, method string, err error, started time.Time) {
+	code := status.Code(err)
+	lvl := zapcore.ErrorLevel
+	switch code {
+	case codes.OK:
+		lvl = zapcore.InfoLevel
+	case codes.Canceled, codes.InvalidArgument, codes.NotFound, codes.AlreadyExists,
+		codes.PermissionDenied, codes.Unauthenticated, codes.FailedPrecondition, codes.OutOfRange:
+		lvl = zapcore.WarnLevel
+	}
+	ce := l.Check(lvl, msg)
+	if ce == nil {
+		return
+	}
+	fields := []zap.Field{
+		zap.String("method", method),
+		zap.String("code", code.String()),
+		zap.Duration("duration", time.Since(started)),
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		fields = append(fields, zap.String("peer", p.Addr.String()))
+	}
+	if id := grpcRequestID(ctx); id != "" {
+		fields = append(fields, zap.String("request_id", id))
+	}
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+	ce.Write(fields...)
+}
+
+// grpcRequestID returns the ID of the gRPC request, empty if there isn't one.
+// goa gRPC request ID middlewares store the request ID in the "x-request-id"
+// metadata key.
+func grpcRequestID(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if ids := md.Get("x-request-id"); len(ids) > 0 {
+		return ids[0]
+	}
+	return ""
+}
+`
+
+// input: none
+const contextT = `
+// loggerKey is the context key used to store the request scoped loggers.
+type loggerKey struct{}
+
+// nopLogger is the logger returned by FromContext when the context does not
+// hold one.
+var nopLogger = &Logger{SugaredLogger: zap.NewNop().Sugar(), level: zap.NewAtomicLevel()}
+
+// WithContext returns a copy of ctx that holds logger.
+func WithContext(ctx context.Context, logger *Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext returns the logger held by ctx. It returns a logger that
+// discards all the entries if ctx does not hold one.
+func FromContext(ctx context.Context) *Logger {
+	if l, ok := ctx.Value(loggerKey{}).(*Logger); ok {
+		return l
+	}
+	return nopLogger
+}
+
+// contextFields returns the request_id, trace_id and span_id fields built from
+// the IDs stored in ctx by the goa request ID and tracing middlewares. The
+// fields whose ID is not set are omitted.
+func contextFields(ctx context.Context) []zap.Field {
+	var fields []zap.Field
+	if id, ok := ctx.Value(middleware.RequestIDKey).(string); ok && id != "" {
+		fields = append(fields, zap.String("request_id", id))
+	}
+	if id, ok := ctx.Value(middleware.TraceIDKey).(string); ok && id != "" {
+		fields = append(fields, zap.String("trace_id", id))
+	}
+	if id, ok := ctx.Value(middleware.TraceSpanIDKey).(string); ok && id != "" {
+		fields = append(fields, zap.String("span_id", id))
+	}
+	return fields
+}
+`
+
+// input: levelData
+const levelT = `
+// LevelPath is the HTTP request path of the logger level endpoint.
+const LevelPath = {{ printf "%q" .Path }}
+
+// LevelHandler returns a HTTP handler that serves GET requests with the logger
+// level and PUT requests that change it, see zap.AtomicLevel.
+func (logger *Logger) LevelHandler() http.Handler {
+	return logger.level
+}
+
+// MountLevelHandler configures the mux to serve GET and PUT requests made to
+// LevelPath with h.
+func MountLevelHandler(mux goahttp.Muxer, h http.Handler) {
+	mux.Handle("GET", LevelPath, h.ServeHTTP)
+	mux.Handle("PUT", LevelPath, h.ServeHTTP)
+}
+{{- if .SchemeName }}
+
+{{ printf "SecureLevelHandler returns a HTTP handler that authorizes the requests using auth and the %q security scheme before calling h. It responds with 401 Unauthorized if the request has no credentials or if auth fails." .SchemeName | comment }}
+func SecureLevelHandler(h http.Handler, auth security.{{ .AuthFunc }}) http.Handler {
+	scheme := &security.{{ .SchemeType }}{Name: {{ printf "%q" .SchemeName }}}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		{{ .Credentials }}
+		if !ok {
+			http.Error(w, "missing credentials", http.StatusUnauthorized)
+			return
+		}
+		ctx, err := auth(r.Context(), {{ .Args }}, scheme)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		h.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+{{- end }}
+`
+
+// mountLevelT mounts the logger level endpoint in the example HTTP server.
+const mountLevelT = `// Serve the logger level, see the LevelEndpoint DSL.
+	log.MountLevelHandler(mux, logger.LevelHandler())
+
+	// Wrap the multiplexer with additional middlewares.`
+
+// mountSecureLevelT mounts the secured logger level endpoint in the example
+// HTTP server.
+const mountSecureLevelT = `// Serve the logger level, see the LevelEndpoint DSL.
+	log.MountLevelHandler(mux, log.SecureLevelHandler(logger.LevelHandler(), levelAuth))
+
+	// Wrap the multiplexer with additional middlewares.`
+
+// input: levelData
+const levelAuthT = `
+{{ printf "levelAuth implements the authorization logic of the logger level endpoint for the %q security scheme." .SchemeName | comment }}
+func levelAuth({{ .AuthSignature }}) (context.Context, error) {
+	//
+	// TBD: add authorization logic.
+	//
+	// In case of authorization failure this function should return
+	// one of the generated error structs, e.g.:
+	//
+	//    return ctx, myservice.MakeUnauthorizedError("invalid token")
+	//
+	// Alternatively this function may return an instance of
+	// goa.ServiceError with a Name field value that matches one of
+	// the design error names, e.g:
+	//
+	//    return ctx, goa.PermanentError("unauthorized", "invalid token")
+	//
+	return ctx, fmt.Errorf("not implemented")
+}
+`
+
+// input: none
+const recoverT = `
+// RecoverOption configures the panic recovery middlewares.
+type RecoverOption func(*recoverOptions)
+
+// recoverOptions is the panic recovery middlewares configuration.
+type recoverOptions struct {
+	// onPanic is called each time a panic is recovered.
+	onPanic func()
+}
+
+// WithPanicCounter sets a function called each time a panic is recovered, e.g.
+// to increment a metrics counter.
+func WithPanicCounter(inc func()) RecoverOption {
+	return func(o *recoverOptions) {
+		o.onPanic = inc
+	}
+}
+
+// newRecoverOptions returns the panic recovery configuration built from opts.
+func newRecoverOptions(opts []RecoverOption) *recoverOptions {
+	o := &recoverOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// logPanic logs the recovered panic p at error level with its stack trace and
+// the given fields and calls the panic counter if any.
+func (logger *Logger) logPanic(o *recoverOptions, p interface{}, fields ...zap.Field) {
+	fields = append(fields, zap.Any("panic", p), zap.Stack("stack"))
+	logger.Desugar().Error("panic recovered", fields...)
+	if o.onPanic != nil {
+		o.onPanic()
+	}
+}
+`
+
+// input: none
+const httpRecoverT = `
+// Recover returns a middleware that recovers from the panics of the HTTP
+// handlers. It logs the panics with their stack trace, the request ID, method
+// and path and responds with a goa fault error encoded using enc, that is with
+// a 500 Internal Server Error response.
+func (logger *Logger) Recover(enc func(context.Context, http.ResponseWriter) goahttp.Encoder, opts ...RecoverOption) func(http.Handler) http.Handler {
+	o := newRecoverOptions(opts)
+	encodeError := goahttp.ErrorEncoder(enc)
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				p := recover()
+				if p == nil {
+					return
+				}
+				if p == http.ErrAbortHandler {
+					// Let net/http abort the response.
+					panic(p)
+				}
+				id, _ := r.Context().Value(middleware.RequestIDKey).(string)
+				logger.logPanic(o, p, zap.String("request_id", id), zap.String("method", r.Method), zap.String("path", r.URL.Path))
+				ctx := context.WithValue(r.Context(), goahttp.AcceptTypeKey, r.Header.Get("Accept"))
+				if err := encodeError(ctx, w, goa.Fault("internal error")); err != nil {
+					logger.Desugar().Error("failed to encode panic response", zap.String("request_id", id), zap.Error(err))
+				}
+			}()
+			h.ServeHTTP(w, r)
+		})
+	}
+}
+`
+
+// input: none
+const grpcRecoverT = `
+// UnaryServerRecoverInterceptor returns a gRPC unary server interceptor that
+// recovers from the panics of the handlers. It logs the panics with their stack
+// trace, the request ID and the full method name and returns an Internal error.
+func (logger *Logger) UnaryServerRecoverInterceptor(opts ...RecoverOption) grpc.UnaryServerInterceptor {
+	o := newRecoverOptions(opts)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				logger.logPanic(o, p, zap.String("request_id", grpcRequestID(ctx)), zap.String("method", info.FullMethod))
+				err = status.Error(codes.Internal, "internal error")
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerRecoverInterceptor returns a gRPC stream server interceptor that
+// recovers from the panics of the handlers. It logs the panics with their stack
+// trace, the request ID and the full method name and returns an Internal error.
+func (logger *Logger) StreamServerRecoverInterceptor(opts ...RecoverOption) grpc.StreamServerInterceptor {
+	o := newRecoverOptions(opts)
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				logger.logPanic(o, p, zap.String("request_id", grpcRequestID(ss.Context())), zap.String("method", info.FullMethod))
+				err = status.Error(codes.Internal, "internal error")
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
+`
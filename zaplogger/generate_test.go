@@ -1,19 +1,24 @@
 package zaplogger
 
 import (
+	"bytes"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"goa.design/goa/codegen"
 	"goa.design/goa/codegen/generator"
 	"goa.design/goa/eval"
 	"goa.design/goa/expr"
+	grpccodegen "goa.design/goa/grpc/codegen"
 	httpcodegen "goa.design/goa/http/codegen"
+	zaploggerexpr "goa.design/plugins/zaplogger/expr"
 	"goa.design/plugins/zaplogger/testdata"
 )
 
 func TestGenerate(t *testing.T) {
 
-	httpcodegen.RunHTTPDSL(t, testdata.SimpleServiceDSL)
+	runHTTPDSL(t, testdata.SimpleServiceDSL)
 
 	roots := []eval.Root{expr.Root}
 	files := generateFiles(t, roots)
@@ -24,8 +29,72 @@ func TestGenerate(t *testing.T) {
 	}
 	newFilesCount := len(newFiles) - len(files)
 
-	if newFilesCount != 1 {
-		t.Errorf("invalid code: number of new files expected %d, got %d", 1, newFilesCount)
+	if newFilesCount != 3 {
+		t.Errorf("invalid code: number of new files expected %d, got %d", 3, newFilesCount)
+	}
+}
+
+func TestGenerateConfig(t *testing.T) {
+	cases := []struct {
+		Name string
+		DSL  func()
+		Code string
+	}{
+		{"config", testdata.ConfigDSL, testdata.ConfigDSLConfigCode},
+		{"default", testdata.SimpleServiceDSL, testdata.DefaultConfigCode},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			runHTTPDSL(t, c.DSL)
+			fs := GenerateFiles("", expr.Root)
+			if len(fs) != 2 {
+				t.Fatalf("got %d files, expected 2", len(fs))
+			}
+			if filepath.Base(fs[0].Path) != "logger.go" {
+				t.Fatalf("got file %s, expected logger.go", fs[0].Path)
+			}
+			testCode(t, fs[0], "zaplogger-config", c.Code)
+			testCode(t, fs[0], "zaplogger-context", testdata.ContextCode)
+			testCode(t, fs[0], "zaplogger-recover", testdata.RecoverCode)
+		})
+	}
+}
+
+func TestGenerateHTTP(t *testing.T) {
+	runHTTPDSL(t, testdata.SimpleServiceDSL)
+	fs := GenerateFiles("", expr.Root)
+	if len(fs) != 2 {
+		t.Fatalf("got %d files, expected 2", len(fs))
+	}
+	if filepath.Base(fs[1].Path) != "http.go" {
+		t.Fatalf("got file %s, expected http.go", fs[1].Path)
+	}
+	testCode(t, fs[1], "zaplogger-http", testdata.HTTPCode)
+	testCode(t, fs[1], "zaplogger-http-recover", testdata.HTTPRecoverCode)
+}
+
+// runHTTPDSL resets the zaplogger settings recorded by the previous tests and
+// runs the given HTTP design.
+func runHTTPDSL(t *testing.T, dsl func()) *expr.RootExpr {
+	zaploggerexpr.Reset()
+	return httpcodegen.RunHTTPDSL(t, dsl)
+}
+
+// runGRPCDSL resets the zaplogger settings recorded by the previous tests and
+// runs the given gRPC design.
+func runGRPCDSL(t *testing.T, dsl func()) *expr.RootExpr {
+	zaploggerexpr.Reset()
+	return grpccodegen.RunGRPCDSL(t, dsl)
+}
+
+func testCode(t *testing.T, file *codegen.File, section, expCode string) {
+	sections := file.Section(section)
+	if len(sections) < 1 {
+		t.Fatalf("%s: got %d sections, expected at least 1", section, len(sections))
+	}
+	code := codegen.SectionCode(t, sections[0])
+	if code != expCode {
+		t.Errorf("invalid code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, expCode))
 	}
 }
 
@@ -42,3 +111,133 @@ func generateFiles(t *testing.T, roots []eval.Root) []*codegen.File {
 	files = append(files, httpFiles...)
 	return files
 }
+
+func TestGenerateGRPC(t *testing.T) {
+	runGRPCDSL(t, testdata.GRPCServiceDSL)
+	fs := GenerateFiles("", expr.Root)
+	if len(fs) != 2 {
+		t.Fatalf("got %d files, expected 2", len(fs))
+	}
+	if filepath.Base(fs[1].Path) != "grpc.go" {
+		t.Fatalf("got file %s, expected grpc.go", fs[1].Path)
+	}
+	testCode(t, fs[1], "zaplogger-grpc", testdata.GRPCCode)
+	testCode(t, fs[1], "zaplogger-grpc-recover", testdata.GRPCRecoverCode)
+}
+
+func TestGenerateLevel(t *testing.T) {
+	cases := []struct {
+		Name string
+		DSL  func()
+		Code string
+	}{
+		{"level", testdata.LevelDSL, testdata.LevelCode},
+		{"secure-level", testdata.SecureLevelDSL, testdata.SecureLevelCode},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			runHTTPDSL(t, c.DSL)
+			fs := GenerateFiles("", expr.Root)
+			if len(fs) != 3 {
+				t.Fatalf("got %d files, expected 3", len(fs))
+			}
+			if filepath.Base(fs[2].Path) != "level.go" {
+				t.Fatalf("got file %s, expected level.go", fs[2].Path)
+			}
+			testCode(t, fs[2], "zaplogger-level", c.Code)
+		})
+	}
+}
+
+func TestAdapterRe(t *testing.T) {
+	src := `
+	// Setup goa log adapter.
+	var (
+    adapter middleware.Logger
+  )
+  {
+    adapter = middleware.NewLogger(logger)
+  }
+
+	// Provide the transport specific request decoder and response encoder.`
+	exp := `
+	// Provide the transport specific request decoder and response encoder.`
+	if got := adapterRe.ReplaceAllString(src, ""); got != exp {
+		t.Errorf("invalid code, got:\n%s\nexpected:\n%s", got, exp)
+	}
+}
+
+func TestUpdateExample(t *testing.T) {
+	cases := []struct {
+		Name     string
+		DSL      func()
+		Run      func(*testing.T, func()) *expr.RootExpr
+		File     string
+		Contains []string
+	}{
+		{"main", testdata.SimpleServiceDSL, runHTTPDSL, "main.go", []string{
+			`logLevelF  = flag.String("log-level", "",`,
+			`logger, err = log.NewFromConfig(`,
+			`Endpoints.Use(`,
+			`.LogEndpoint(logger))`,
+		}},
+		{"http", testdata.SimpleServiceDSL, runHTTPDSL, "http.go", []string{
+			"mux = log.RouteMuxer(goahttp.NewMuxer())",
+			"handler = logger.Recover(enc)(handler)",
+			"handler = logger.Context()(handler)",
+			"handler = logger.HTTP()(handler)",
+			`logger.With(zap.String("request_id", id)).Error(err.Error())`,
+		}},
+		{"level", testdata.LevelDSL, runHTTPDSL, "http.go", []string{
+			"log.MountLevelHandler(mux, logger.LevelHandler())",
+		}},
+		{"secure-level", testdata.SecureLevelDSL, runHTTPDSL, "http.go", []string{
+			"log.MountLevelHandler(mux, log.SecureLevelHandler(logger.LevelHandler(), levelAuth))",
+			"func levelAuth(",
+		}},
+		{"grpc", testdata.GRPCServiceDSL, runGRPCDSL, "grpc.go", []string{
+			"logger.UnaryServerInterceptor(),",
+			"logger.UnaryServerRecoverInterceptor()",
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			c.Run(t, c.DSL)
+			roots := []eval.Root{expr.Root}
+			files, err := generator.Example("", roots)
+			if err != nil {
+				t.Fatalf("error in example generation: %v", err)
+			}
+			files, err = UpdateExample("", roots, files)
+			if err != nil {
+				t.Fatalf("update example error: %v", err)
+			}
+			var code string
+			for _, f := range files {
+				if filepath.Base(f.Path) != c.File || !strings.HasPrefix(f.Path, "cmd") {
+					continue
+				}
+				buf := new(bytes.Buffer)
+				for _, s := range f.SectionTemplates[1:] {
+					if err := s.Write(buf); err != nil {
+						t.Fatalf("error writing section %s in file %s: %v", s.Name, f.Path, err)
+					}
+				}
+				code = buf.String()
+			}
+			if code == "" {
+				t.Fatalf("example file %s not found", c.File)
+			}
+			for _, exp := range c.Contains {
+				if !strings.Contains(code, exp) {
+					t.Errorf("got code:\n%s\nexpected it to contain %q", code, exp)
+				}
+			}
+			// The generated logger replaces the goa log adapter, leaving it
+			// declared would not compile.
+			if strings.Contains(code, "adapter") {
+				t.Errorf("got code:\n%s\nexpected no goa log adapter", code)
+			}
+		})
+	}
+}
@@ -0,0 +1,322 @@
+package zaplogger
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+
+	"goa.design/goa/codegen"
+	"goa.design/goa/codegen/service"
+	"goa.design/goa/expr"
+)
+
+type (
+	// marshalerData contains the data needed to render the MarshalLogObject
+	// method of a service type.
+	marshalerData struct {
+		// TypeName is the name of the Go struct.
+		TypeName string
+		// Fields lists the Go statements that add the struct fields to the
+		// object encoder.
+		Fields []string
+	}
+)
+
+// GenerateServiceFiles returns the files defining the endpoint logging
+// middleware and the zapcore.ObjectMarshaler implementations of the services.
+func GenerateServiceFiles(genpkg string, root *expr.RootExpr) []*codegen.File {
+	fw := make([]*codegen.File, len(root.Services))
+	for i, svc := range root.Services {
+		fw[i] = GenerateServiceFile(genpkg, svc)
+	}
+	return fw
+}
+
+// GenerateServiceFile returns the file defining the endpoint logging
+// middleware and the zapcore.ObjectMarshaler implementations of the user types
+// of the given service.
+func GenerateServiceFile(genpkg string, svc *expr.ServiceExpr) *codegen.File {
+	data := service.Services.Get(svc.Name)
+	path := filepath.Join(codegen.Gendir, codegen.SnakeCase(svc.Name), "log.go")
+	title := fmt.Sprintf("%s zap logging", svc.Name)
+	sections := []*codegen.SectionTemplate{
+		codegen.Header(title, data.PkgName, []*codegen.ImportSpec{
+			{Path: "context"},
+			{Path: "fmt"},
+			{Path: "time"},
+			{Path: "go.uber.org/zap"},
+			{Path: "go.uber.org/zap/zapcore"},
+			{Path: "goa.design/goa", Name: "goa"},
+			{Path: path.Join(genpkg, "log"), Name: "log"},
+			{Path: path.Join(genpkg, codegen.SnakeCase(svc.Name), "views"), Name: data.ViewsPkg},
+		}),
+		{
+			Name:   "zaplogger-endpoint",
+			Source: endpointT,
+			Data:   data,
+		},
+	}
+	for _, m := range marshalers(svc, data.Scope) {
+		sections = append(sections, &codegen.SectionTemplate{
+			Name:   "zaplogger-marshaler",
+			Source: marshalerT,
+			Data:   m,
+		})
+	}
+
+	return &codegen.File{Path: path, SectionTemplates: sections}
+}
+
+// marshalers returns the data needed to render the MarshalLogObject methods of
+// the object user types used by the given service methods, including the
+// types of the nested attributes, array elements and map values.
+func marshalers(svc *expr.ServiceExpr, scope *codegen.NameScope) []*marshalerData {
+	var (
+		mds     []*marshalerData
+		seen    = make(map[string]struct{})
+		collect func(*expr.AttributeExpr)
+	)
+	collect = func(att *expr.AttributeExpr) {
+		if att == nil {
+			return
+		}
+		switch dt := att.Type.(type) {
+		case expr.UserType:
+			if !isMarshaler(dt) {
+				// Collect the element types of the collections.
+				if dt != expr.Empty && dt != expr.ErrorResult {
+					collect(dt.Attribute())
+				}
+				return
+			}
+			name := scope.GoTypeName(att)
+			if _, ok := seen[name]; ok {
+				return
+			}
+			seen[name] = struct{}{}
+			mds = append(mds, &marshalerData{TypeName: name, Fields: marshalFields(dt.Attribute())})
+			collect(dt.Attribute())
+		case *expr.Object:
+			for _, nat := range *dt {
+				collect(nat.Attribute)
+			}
+		case *expr.Array:
+			collect(dt.ElemType)
+		case *expr.Map:
+			collect(dt.KeyType)
+			collect(dt.ElemType)
+		}
+	}
+	for _, m := range svc.Methods {
+		collect(m.Payload)
+		collect(m.StreamingPayload)
+		collect(m.Result)
+	}
+	return mds
+}
+
+// marshalFields returns the Go statements that add the fields of the given
+// object to a zapcore.ObjectEncoder. The fields marked with the "zap:omit" meta
+// are skipped and the value of the fields marked with the "zap:redact" meta is
+// replaced with "[REDACTED]".
+func marshalFields(att *expr.AttributeExpr) []string {
+	var fields []string
+	for _, nat := range *expr.AsObject(att.Type) {
+		if _, ok := nat.Attribute.Meta["zap:omit"]; ok {
+			continue
+		}
+		if _, ok := nat.Attribute.Meta["zap:redact"]; ok {
+			fields = append(fields, fmt.Sprintf("enc.AddString(%q, %q)", nat.Name, "[REDACTED]"))
+			continue
+		}
+		field := "v." + codegen.Goify(nat.Name, true)
+		if add, ok := primitiveEncoder(nat.Attribute.Type); ok && att.IsPrimitivePointer(nat.Name, true) {
+			fields = append(fields, fmt.Sprintf("if %s != nil {\n%s\n}", field, fmt.Sprintf("enc.%s(%q, *%s)", add, nat.Name, field)))
+			continue
+		}
+		fields = append(fields, addCode(fmt.Sprintf("%q", nat.Name), field, nat.Attribute.Type))
+	}
+	return fields
+}
+
+// addCode returns the Go statement that adds the value val of type dt under
+// the given key to the zapcore.ObjectEncoder enc. key is a Go expression.
+func addCode(key, val string, dt expr.DataType) string {
+	if add, ok := primitiveEncoder(dt); ok {
+		return fmt.Sprintf("enc.%s(%s, %s)", add, key, val)
+	}
+	switch {
+	case expr.IsArray(dt):
+		return fmt.Sprintf("if err := enc.AddArray(%s, %s); err != nil {\nreturn err\n}", key, arrayMarshaler(val, dt))
+	case expr.IsMap(dt):
+		return fmt.Sprintf("if err := enc.AddObject(%s, %s); err != nil {\nreturn err\n}", key, mapMarshaler(val, dt))
+	case isMarshaler(dt):
+		return fmt.Sprintf("if err := enc.AddObject(%s, %s); err != nil {\nreturn err\n}", key, val)
+	}
+	return fmt.Sprintf("if err := enc.AddReflected(%s, %s); err != nil {\nreturn err\n}", key, val)
+}
+
+// appendCode returns the Go statement that appends the value val of type dt to
+// the zapcore.ArrayEncoder enc.
+func appendCode(val string, dt expr.DataType) string {
+	if add, ok := primitiveEncoder(dt); ok {
+		return fmt.Sprintf("enc.Append%s(%s)", add[len("Add"):], val)
+	}
+	switch {
+	case expr.IsArray(dt):
+		return fmt.Sprintf("if err := enc.AppendArray(%s); err != nil {\nreturn err\n}", arrayMarshaler(val, dt))
+	case expr.IsMap(dt):
+		return fmt.Sprintf("if err := enc.AppendObject(%s); err != nil {\nreturn err\n}", mapMarshaler(val, dt))
+	case isMarshaler(dt):
+		return fmt.Sprintf("if err := enc.AppendObject(%s); err != nil {\nreturn err\n}", val)
+	}
+	return fmt.Sprintf("if err := enc.AppendReflected(%s); err != nil {\nreturn err\n}", val)
+}
+
+// arrayMarshaler returns the Go expression that marshals the array val of
+// type dt.
+func arrayMarshaler(val string, dt expr.DataType) string {
+	elem := expr.AsArray(dt).ElemType.Type
+	return fmt.Sprintf("zapcore.ArrayMarshalerFunc(func(enc zapcore.ArrayEncoder) error {\nfor _, e := range %s {\n%s\n}\nreturn nil\n})",
+		val, appendCode("e", elem))
+}
+
+// mapMarshaler returns the Go expression that marshals the map val of type dt
+// as an object whose keys are the map keys formatted with fmt.Sprint.
+func mapMarshaler(val string, dt expr.DataType) string {
+	m := expr.AsMap(dt)
+	key := "k"
+	if m.KeyType.Type.Kind() != expr.StringKind {
+		key = "fmt.Sprint(k)"
+	}
+	return fmt.Sprintf("zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {\nfor k, e := range %s {\n%s\n}\nreturn nil\n})",
+		val, addCode(key, "e", m.ElemType.Type))
+}
+
+// isMarshaler returns true if the Go type generated for dt implements
+// zapcore.ObjectMarshaler, see marshalers. The Empty type has no Go type and
+// ErrorResult is generated as goa.ServiceError.
+func isMarshaler(dt expr.DataType) bool {
+	if _, ok := dt.(expr.UserType); !ok {
+		return false
+	}
+	return dt != expr.Empty && dt != expr.ErrorResult && expr.IsObject(dt)
+}
+
+// primitiveEncoder returns the name of the zapcore.ObjectEncoder method that
+// adds values of the given type. It returns false if dt is not a primitive
+// type or is the Any type.
+func primitiveEncoder(dt expr.DataType) (string, bool) {
+	if !expr.IsPrimitive(dt) {
+		return "", false
+	}
+	switch dt.Kind() {
+	case expr.BooleanKind:
+		return "AddBool", true
+	case expr.IntKind:
+		return "AddInt", true
+	case expr.Int32Kind:
+		return "AddInt32", true
+	case expr.Int64Kind:
+		return "AddInt64", true
+	case expr.UIntKind:
+		return "AddUint", true
+	case expr.UInt32Kind:
+		return "AddUint32", true
+	case expr.UInt64Kind:
+		return "AddUint64", true
+	case expr.Float32Kind:
+		return "AddFloat32", true
+	case expr.Float64Kind:
+		return "AddFloat64", true
+	case expr.StringKind:
+		return "AddString", true
+	case expr.BytesKind:
+		return "AddBinary", true
+	}
+	return "", false
+}
+
+// input: service.Data
+const endpointT = `{{ printf "LogEndpoint returns an endpoint middleware that logs the invocations of the %s service methods at debug level with their payload, result, duration and error. The payload and result types implement zapcore.ObjectMarshaler so that the fields marked with the \"zap:omit\" meta are not logged and the fields marked with the \"zap:redact\" meta are redacted, the values of other types are not logged unless they are primitive. Apply the middleware with Endpoints.Use." .Name | comment }}
+func LogEndpoint(logger *log.Logger) func(goa.Endpoint) goa.Endpoint {
+	l := logger.Desugar()
+	return func(e goa.Endpoint) goa.Endpoint {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			started := time.Now()
+			res, err := e(ctx, req)
+			ce := l.Check(zapcore.DebugLevel, "endpoint")
+			if ce == nil {
+				return res, err
+			}
+			method, _ := ctx.Value(goa.MethodKey).(string)
+			fields := []zap.Field{
+				zap.String("service", ServiceName),
+				zap.String("method", method),
+				zap.Duration("duration", time.Since(started)),
+				logField("payload", req),
+			}
+			if err != nil {
+				fields = append(fields, zap.Error(err))
+				if se, ok := err.(*goa.ServiceError); ok {
+					fields = append(fields,
+						zap.String("error_name", se.Name),
+						zap.Bool("temporary", se.Temporary),
+						zap.Bool("timeout", se.Timeout),
+						zap.Bool("fault", se.Fault),
+					)
+				}
+			} else {
+				fields = append(fields, logField("result", res))
+			}
+			ce.Write(fields...)
+			return res, err
+		}
+	}
+}
+
+// logField returns the field that logs v under the given key. It uses the
+// zapcore.ObjectMarshaler implementation of v if any. The viewed results are
+// converted back to the service result types so that their marshaler applies.
+// Only the type of the other non primitive values is logged, they may contain
+// fields that must not be logged.
+func logField(key string, v interface{}) zap.Field {
+	switch v := v.(type) {
+	case zapcore.ObjectMarshaler:
+		return zap.Object(key, v)
+{{- range .ViewedResultTypes }}
+	{{- if .ToResult }}
+	case {{ .Ref }}:
+		{{- if .IsCollection }}
+		return zap.Array(key, zapcore.ArrayMarshalerFunc(func(enc zapcore.ArrayEncoder) error {
+			for _, e := range {{ .ToResult.Name }}(v) {
+				if err := enc.AppendObject(e); err != nil {
+					return err
+				}
+			}
+			return nil
+		}))
+		{{- else }}
+		return zap.Object(key, {{ .ToResult.Name }}(v))
+		{{- end }}
+	{{- end }}
+{{- end }}
+	case nil, bool, string, []byte, int, int32, int64, uint, uint32, uint64, float32, float64:
+		return zap.Any(key, v)
+	}
+	return zap.String(key, fmt.Sprintf("%T (not logged)", v))
+}
+`
+
+// input: marshalerData
+const marshalerT = `// MarshalLogObject implements zapcore.ObjectMarshaler.
+func (v *{{ .TypeName }}) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	if v == nil {
+		return nil
+	}
+{{- range .Fields }}
+	{{ . }}
+{{- end }}
+	return nil
+}
+`
@@ -0,0 +1,61 @@
+package zaplogger
+
+import (
+	"testing"
+
+	"goa.design/goa/codegen"
+	"goa.design/goa/expr"
+	"goa.design/plugins/zaplogger/testdata"
+)
+
+func TestGenerateServiceFiles(t *testing.T) {
+	cases := []struct {
+		Name string
+		DSL  func()
+		Code string
+	}{
+		{"redact", testdata.RedactServiceDSL, testdata.RedactServiceEndpointCode},
+		{"viewed", testdata.ViewedServiceDSL, testdata.ViewedServiceEndpointCode},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			runHTTPDSL(t, c.DSL)
+			fs := GenerateServiceFiles("", expr.Root)
+			if len(fs) != 1 {
+				t.Fatalf("got %d files, expected 1", len(fs))
+			}
+			testCode(t, fs[0], "zaplogger-endpoint", c.Code)
+		})
+	}
+}
+
+func TestGenerateMarshalers(t *testing.T) {
+	cases := []struct {
+		Name string
+		DSL  func()
+		Code []string
+	}{
+		{"redact", testdata.RedactServiceDSL, []string{testdata.RedactMethodPayloadMarshalerCode, testdata.RedactMethodResultMarshalerCode}},
+		{"nested", testdata.NestedServiceDSL, []string{testdata.NestedMethodPayloadMarshalerCode, testdata.ItemMarshalerCode}},
+		{"viewed", testdata.ViewedServiceDSL, []string{testdata.AccountMarshalerCode}},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			runHTTPDSL(t, c.DSL)
+			fs := GenerateServiceFiles("", expr.Root)
+			if len(fs) != 1 {
+				t.Fatalf("got %d files, expected 1", len(fs))
+			}
+			sections := fs[0].Section("zaplogger-marshaler")
+			if len(sections) != len(c.Code) {
+				t.Fatalf("got %d marshalers, expected %d", len(sections), len(c.Code))
+			}
+			for i, s := range sections {
+				code := codegen.SectionCode(t, s)
+				if code != c.Code[i] {
+					t.Errorf("invalid code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, c.Code[i]))
+				}
+			}
+		})
+	}
+}
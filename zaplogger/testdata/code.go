@@ -0,0 +1,826 @@
+package testdata
+
+var DefaultConfigCode = `// Config is the zap logger configuration.
+type Config struct {
+	// Encoding is the log encoding, "json" or "console".
+	Encoding string
+	// Level is the minimum enabled logging level, e.g. "debug" or "info".
+	Level string
+	// SamplingInitial is the number of entries with the same level and
+	// message logged every second before sampling kicks in, zero disables
+	// sampling.
+	SamplingInitial int
+	// SamplingThereafter is the sampling rate of the entries logged after
+	// the first SamplingInitial ones.
+	SamplingThereafter int
+	// OutputPaths lists the URLs or file paths the logs are written to,
+	// "stdout" and "stderr" denote the standard streams.
+	OutputPaths []string
+}
+
+// DefaultConfig returns the logger configuration defined in the design.
+func DefaultConfig() *Config {
+	return &Config{
+		Encoding:           "json",
+		Level:              "info",
+		SamplingInitial:    100,
+		SamplingThereafter: 100,
+		OutputPaths:        []string{"stderr"},
+	}
+}
+
+// NewFromConfig creates a new zap logger using the given configuration. It
+// returns an error if the configuration is invalid or if the outputs cannot be
+// opened. Call Sync before exiting to flush the buffered log entries.
+func NewFromConfig(serviceName string, cfg *Config) (*Logger, error) {
+	var lvl zapcore.Level
+	if err := lvl.UnmarshalText([]byte(cfg.Level)); err != nil {
+		return nil, err
+	}
+	level := zap.NewAtomicLevelAt(lvl)
+	zc := zap.Config{
+		Level:            level,
+		Encoding:         cfg.Encoding,
+		EncoderConfig:    zap.NewProductionEncoderConfig(),
+		OutputPaths:      cfg.OutputPaths,
+		ErrorOutputPaths: []string{"stderr"},
+	}
+	if cfg.Encoding == "console" {
+		zc.EncoderConfig = zap.NewDevelopmentEncoderConfig()
+	}
+	if cfg.SamplingInitial > 0 {
+		zc.Sampling = &zap.SamplingConfig{Initial: cfg.SamplingInitial, Thereafter: cfg.SamplingThereafter}
+	}
+	l, err := zc.Build()
+	if err != nil {
+		return nil, err
+	}
+	return &Logger{SugaredLogger: l.Sugar().With(zap.String("service", serviceName)), level: level}, nil
+}
+`
+
+var ConfigDSLConfigCode = `// Config is the zap logger configuration.
+type Config struct {
+	// Encoding is the log encoding, "json" or "console".
+	Encoding string
+	// Level is the minimum enabled logging level, e.g. "debug" or "info".
+	Level string
+	// SamplingInitial is the number of entries with the same level and
+	// message logged every second before sampling kicks in, zero disables
+	// sampling.
+	SamplingInitial int
+	// SamplingThereafter is the sampling rate of the entries logged after
+	// the first SamplingInitial ones.
+	SamplingThereafter int
+	// OutputPaths lists the URLs or file paths the logs are written to,
+	// "stdout" and "stderr" denote the standard streams.
+	OutputPaths []string
+}
+
+// DefaultConfig returns the logger configuration defined in the design.
+func DefaultConfig() *Config {
+	return &Config{
+		Encoding:           "console",
+		Level:              "debug",
+		SamplingInitial:    0,
+		SamplingThereafter: 0,
+		OutputPaths:        []string{"stdout", "/var/log/config.log"},
+	}
+}
+
+// NewFromConfig creates a new zap logger using the given configuration. It
+// returns an error if the configuration is invalid or if the outputs cannot be
+// opened. Call Sync before exiting to flush the buffered log entries.
+func NewFromConfig(serviceName string, cfg *Config) (*Logger, error) {
+	var lvl zapcore.Level
+	if err := lvl.UnmarshalText([]byte(cfg.Level)); err != nil {
+		return nil, err
+	}
+	level := zap.NewAtomicLevelAt(lvl)
+	zc := zap.Config{
+		Level:            level,
+		Encoding:         cfg.Encoding,
+		EncoderConfig:    zap.NewProductionEncoderConfig(),
+		OutputPaths:      cfg.OutputPaths,
+		ErrorOutputPaths: []string{"stderr"},
+	}
+	if cfg.Encoding == "console" {
+		zc.EncoderConfig = zap.NewDevelopmentEncoderConfig()
+	}
+	if cfg.SamplingInitial > 0 {
+		zc.Sampling = &zap.SamplingConfig{Initial: cfg.SamplingInitial, Thereafter: cfg.SamplingThereafter}
+	}
+	l, err := zc.Build()
+	if err != nil {
+		return nil, err
+	}
+	return &Logger{SugaredLogger: l.Sugar().With(zap.String("service", serviceName)), level: level}, nil
+}
+`
+
+var HTTPCode = `// HTTPOption configures the HTTP access log middleware.
+type HTTPOption func(*httpOptions)
+
+// httpOptions is the HTTP access log middleware configuration.
+type httpOptions struct {
+	// levels maps the response status classes (e.g. 4 for 4xx) to the level
+	// of the access log entries.
+	levels map[int]zapcore.Level
+}
+
+// WithStatusLevel sets the level of the entries logged for the responses whose
+// status code belongs to the given class, e.g. 4 for 4xx responses. By default
+// 4xx responses are logged at warn level, 5xx responses at error level and the
+// other responses at info level.
+func WithStatusLevel(class int, lvl zapcore.Level) HTTPOption {
+	return func(o *httpOptions) {
+		o.levels[class] = lvl
+	}
+}
+
+// HTTP returns a middleware that logs one entry per HTTP request with the
+// request ID, method, path, path pattern, remote address and user agent
+// together with the response status, size and latency. The path pattern is
+// only logged if the muxer is wrapped with RouteMuxer.
+func (logger *Logger) HTTP(opts ...HTTPOption) func(http.Handler) http.Handler {
+	o := &httpOptions{levels: map[int]zapcore.Level{
+		4: zapcore.WarnLevel,
+		5: zapcore.ErrorLevel,
+	}}
+	for _, opt := range opts {
+		opt(o)
+	}
+	l := logger.Desugar()
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			started := time.Now()
+			e := &httpEntry{ResponseWriter: w, status: http.StatusOK}
+			h.ServeHTTP(e, r.WithContext(context.WithValue(r.Context(), httpEntryKey, e)))
+
+			lvl, ok := o.levels[e.status/100]
+			if !ok {
+				lvl = zapcore.InfoLevel
+			}
+			ce := l.Check(lvl, "HTTP request")
+			if ce == nil {
+				return
+			}
+			id, _ := r.Context().Value(middleware.RequestIDKey).(string)
+			ce.Write(
+				zap.String("request_id", id),
+				zap.String("method", r.Method),
+				zap.String("path", r.URL.Path),
+				zap.String("pattern", e.pattern),
+				zap.Int("status", e.status),
+				zap.Int("bytes", e.bytes),
+				zap.Duration("latency", time.Since(started)),
+				zap.String("remote_addr", remoteAddr(r)),
+				zap.String("user_agent", r.UserAgent()),
+			)
+		})
+	}
+}
+
+// Context returns a middleware that stores in the request context a child of
+// logger carrying the request ID and the trace and span IDs, see FromContext.
+// The middleware must be wrapped by the goa request ID and tracing
+// middlewares so that the IDs are set when it runs.
+func (logger *Logger) Context() func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			h.ServeHTTP(w, r.WithContext(WithContext(ctx, logger.with(contextFields(ctx)...))))
+		})
+	}
+}
+
+// RouteMuxer wraps mux so that the entries logged by the HTTP access log
+// middleware include the path pattern of the routes serving the requests.
+func RouteMuxer(mux goahttp.Muxer) goahttp.Muxer {
+	return &routeMuxer{Muxer: mux}
+}
+
+// routeMuxer is a muxer that records the path pattern of the served routes in
+// the HTTP access log entries.
+type routeMuxer struct {
+	goahttp.Muxer
+}
+
+// Handle registers the handler for the given method and pattern.
+func (m *routeMuxer) Handle(method, pattern string, handler http.HandlerFunc) {
+	m.Muxer.Handle(method, pattern, func(w http.ResponseWriter, r *http.Request) {
+		if e, ok := r.Context().Value(httpEntryKey).(*httpEntry); ok {
+			e.pattern = pattern
+		}
+		handler(w, r)
+	})
+}
+
+// httpEntry records the response status and size and the route path pattern
+// of a request.
+type httpEntry struct {
+	http.ResponseWriter
+	status  int
+	bytes   int
+	pattern string
+}
+
+// WriteHeader records the response status code.
+func (e *httpEntry) WriteHeader(code int) {
+	e.status = code
+	e.ResponseWriter.WriteHeader(code)
+}
+
+// Write records the response size.
+func (e *httpEntry) Write(b []byte) (int, error) {
+	n, err := e.ResponseWriter.Write(b)
+	e.bytes += n
+	return n, err
+}
+
+// Hijack implements http.Hijacker so that the websocket endpoints can take over
+// the connection.
+func (e *httpEntry) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := e.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("response writer %T does not implement http.Hijacker", e.ResponseWriter)
+	}
+	conn, rw, err := h.Hijack()
+	if err == nil {
+		e.status = http.StatusSwitchingProtocols
+	}
+	return conn, rw, err
+}
+
+// Flush implements http.Flusher so that the streaming endpoints can flush the
+// buffered response data.
+func (e *httpEntry) Flush() {
+	if f, ok := e.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// httpEntryKeyType is the type of the context key used to store the HTTP access
+// log entries.
+type httpEntryKeyType struct{}
+
+// httpEntryKey is the context key used to store the HTTP access log entries.
+var httpEntryKey = httpEntryKeyType{}
+
+// remoteAddr returns the address of the client that made the request, using
+// the X-Forwarded-For header when set by a proxy.
+func remoteAddr(r *http.Request) string {
+	if f := r.Header.Get("X-Forwarded-For"); f != "" {
+		return strings.TrimSpace(strings.Split(f, ",")[0])
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+`
+
+var GRPCCode = `// UnaryServerInterceptor returns a gRPC unary server interceptor that logs one
+// entry per request with the full method name, status code, duration, peer
+// address and request ID.
+func (logger *Logger) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	l := logger.Desugar()
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		started := time.Now()
+		resp, err := handler(logger.grpcContext(ctx), req)
+		logGRPC(ctx, l, "gRPC request", info.FullMethod, err, started)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor returns a gRPC stream server interceptor that logs one
+// entry per stream with the full method name, status code, duration, peer
+// address and request ID.
+func (logger *Logger) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	l := logger.Desugar()
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		started := time.Now()
+		err := handler(srv, &contextStream{ServerStream: ss, ctx: logger.grpcContext(ss.Context())})
+		logGRPC(ss.Context(), l, "gRPC stream", info.FullMethod, err, started)
+		return err
+	}
+}
+
+// contextStream is a server stream whose context holds the request scoped
+// logger.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the stream context.
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// grpcContext returns a copy of ctx that holds a child of logger carrying the
+// request ID and the trace and span IDs, see FromContext.
+func (logger *Logger) grpcContext(ctx context.Context) context.Context {
+	fields := contextFields(ctx)
+	if _, ok := ctx.Value(middleware.RequestIDKey).(string); !ok {
+		if id := grpcRequestID(ctx); id != "" {
+			fields = append(fields, zap.String("request_id", id))
+		}
+	}
+	return WithContext(ctx, logger.with(fields...))
+}
+
+// UnaryClientInterceptor returns a gRPC unary client interceptor that logs one
+// entry per request with the full method name, status code, duration and
+// server address.
+func (logger *Logger) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	l := logger.Desugar()
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		started := time.Now()
+		var p peer.Peer
+		err := invoker(ctx, method, req, reply, cc, append(opts, grpc.Peer(&p))...)
+		logGRPC(peer.NewContext(ctx, &p), l, "gRPC call", method, err, started)
+		return err
+	}
+}
+
+// StreamClientInterceptor returns a gRPC stream client interceptor that logs
+// one entry per stream creation with the full method name, status code,
+// duration and server address.
+func (logger *Logger) StreamClientInterceptor() grpc.StreamClientInterceptor {
+	l := logger.Desugar()
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		started := time.Now()
+		var p peer.Peer
+		cs, err := streamer(ctx, desc, cc, method, append(opts, grpc.Peer(&p))...)
+		logGRPC(peer.NewContext(ctx, &p), l, "gRPC stream", method, err, started)
+		return cs, err
+	}
+}
+
+// logGRPC logs a gRPC request. OK responses are logged at info level, the
+// errors caused by the client at warn level and the other errors at error
+// level.
+func logGRPC(ctx context.Context, l *zap.Logger, msg, method string, err error, started time.Time) {
+	code := status.Code(err)
+	lvl := zapcore.ErrorLevel
+	switch code {
+	case codes.OK:
+		lvl = zapcore.InfoLevel
+	case codes.Canceled, codes.InvalidArgument, codes.NotFound, codes.AlreadyExists,
+		codes.PermissionDenied, codes.Unauthenticated, codes.FailedPrecondition, codes.OutOfRange:
+		lvl = zapcore.WarnLevel
+	}
+	ce := l.Check(lvl, msg)
+	if ce == nil {
+		return
+	}
+	fields := []zap.Field{
+		zap.String("method", method),
+		zap.String("code", code.String()),
+		zap.Duration("duration", time.Since(started)),
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		fields = append(fields, zap.String("peer", p.Addr.String()))
+	}
+	if id := grpcRequestID(ctx); id != "" {
+		fields = append(fields, zap.String("request_id", id))
+	}
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+	ce.Write(fields...)
+}
+
+// grpcRequestID returns the ID of the gRPC request, empty if there isn't one.
+// goa gRPC request ID middlewares store the request ID in the "x-request-id"
+// metadata key.
+func grpcRequestID(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if ids := md.Get("x-request-id"); len(ids) > 0 {
+		return ids[0]
+	}
+	return ""
+}
+`
+
+var ContextCode = `// loggerKey is the context key used to store the request scoped loggers.
+type loggerKey struct{}
+
+// nopLogger is the logger returned by FromContext when the context does not
+// hold one.
+var nopLogger = &Logger{SugaredLogger: zap.NewNop().Sugar(), level: zap.NewAtomicLevel()}
+
+// WithContext returns a copy of ctx that holds logger.
+func WithContext(ctx context.Context, logger *Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext returns the logger held by ctx. It returns a logger that
+// discards all the entries if ctx does not hold one.
+func FromContext(ctx context.Context) *Logger {
+	if l, ok := ctx.Value(loggerKey{}).(*Logger); ok {
+		return l
+	}
+	return nopLogger
+}
+
+// contextFields returns the request_id, trace_id and span_id fields built from
+// the IDs stored in ctx by the goa request ID and tracing middlewares. The
+// fields whose ID is not set are omitted.
+func contextFields(ctx context.Context) []zap.Field {
+	var fields []zap.Field
+	if id, ok := ctx.Value(middleware.RequestIDKey).(string); ok && id != "" {
+		fields = append(fields, zap.String("request_id", id))
+	}
+	if id, ok := ctx.Value(middleware.TraceIDKey).(string); ok && id != "" {
+		fields = append(fields, zap.String("trace_id", id))
+	}
+	if id, ok := ctx.Value(middleware.TraceSpanIDKey).(string); ok && id != "" {
+		fields = append(fields, zap.String("span_id", id))
+	}
+	return fields
+}
+`
+
+var RedactServiceEndpointCode = `// LogEndpoint returns an endpoint middleware that logs the invocations of the
+// RedactService service methods at debug level with their payload, result,
+// duration and error. The payload and result types implement
+// zapcore.ObjectMarshaler so that the fields marked with the "zap:omit" meta
+// are not logged and the fields marked with the "zap:redact" meta are
+// redacted, the values of other types are not logged unless they are
+// primitive. Apply the middleware with Endpoints.Use.
+func LogEndpoint(logger *log.Logger) func(goa.Endpoint) goa.Endpoint {
+	l := logger.Desugar()
+	return func(e goa.Endpoint) goa.Endpoint {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			started := time.Now()
+			res, err := e(ctx, req)
+			ce := l.Check(zapcore.DebugLevel, "endpoint")
+			if ce == nil {
+				return res, err
+			}
+			method, _ := ctx.Value(goa.MethodKey).(string)
+			fields := []zap.Field{
+				zap.String("service", ServiceName),
+				zap.String("method", method),
+				zap.Duration("duration", time.Since(started)),
+				logField("payload", req),
+			}
+			if err != nil {
+				fields = append(fields, zap.Error(err))
+				if se, ok := err.(*goa.ServiceError); ok {
+					fields = append(fields,
+						zap.String("error_name", se.Name),
+						zap.Bool("temporary", se.Temporary),
+						zap.Bool("timeout", se.Timeout),
+						zap.Bool("fault", se.Fault),
+					)
+				}
+			} else {
+				fields = append(fields, logField("result", res))
+			}
+			ce.Write(fields...)
+			return res, err
+		}
+	}
+}
+
+// logField returns the field that logs v under the given key. It uses the
+// zapcore.ObjectMarshaler implementation of v if any. The viewed results are
+// converted back to the service result types so that their marshaler applies.
+// Only the type of the other non primitive values is logged, they may contain
+// fields that must not be logged.
+func logField(key string, v interface{}) zap.Field {
+	switch v := v.(type) {
+	case zapcore.ObjectMarshaler:
+		return zap.Object(key, v)
+	case nil, bool, string, []byte, int, int32, int64, uint, uint32, uint64, float32, float64:
+		return zap.Any(key, v)
+	}
+	return zap.String(key, fmt.Sprintf("%T (not logged)", v))
+}
+`
+
+var ViewedServiceEndpointCode = `// LogEndpoint returns an endpoint middleware that logs the invocations of the
+// ViewedService service methods at debug level with their payload, result,
+// duration and error. The payload and result types implement
+// zapcore.ObjectMarshaler so that the fields marked with the "zap:omit" meta
+// are not logged and the fields marked with the "zap:redact" meta are
+// redacted, the values of other types are not logged unless they are
+// primitive. Apply the middleware with Endpoints.Use.
+func LogEndpoint(logger *log.Logger) func(goa.Endpoint) goa.Endpoint {
+	l := logger.Desugar()
+	return func(e goa.Endpoint) goa.Endpoint {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			started := time.Now()
+			res, err := e(ctx, req)
+			ce := l.Check(zapcore.DebugLevel, "endpoint")
+			if ce == nil {
+				return res, err
+			}
+			method, _ := ctx.Value(goa.MethodKey).(string)
+			fields := []zap.Field{
+				zap.String("service", ServiceName),
+				zap.String("method", method),
+				zap.Duration("duration", time.Since(started)),
+				logField("payload", req),
+			}
+			if err != nil {
+				fields = append(fields, zap.Error(err))
+				if se, ok := err.(*goa.ServiceError); ok {
+					fields = append(fields,
+						zap.String("error_name", se.Name),
+						zap.Bool("temporary", se.Temporary),
+						zap.Bool("timeout", se.Timeout),
+						zap.Bool("fault", se.Fault),
+					)
+				}
+			} else {
+				fields = append(fields, logField("result", res))
+			}
+			ce.Write(fields...)
+			return res, err
+		}
+	}
+}
+
+// logField returns the field that logs v under the given key. It uses the
+// zapcore.ObjectMarshaler implementation of v if any. The viewed results are
+// converted back to the service result types so that their marshaler applies.
+// Only the type of the other non primitive values is logged, they may contain
+// fields that must not be logged.
+func logField(key string, v interface{}) zap.Field {
+	switch v := v.(type) {
+	case zapcore.ObjectMarshaler:
+		return zap.Object(key, v)
+	case *viewedserviceviews.Account:
+		return zap.Object(key, NewAccount(v))
+	case viewedserviceviews.AccountCollection:
+		return zap.Array(key, zapcore.ArrayMarshalerFunc(func(enc zapcore.ArrayEncoder) error {
+			for _, e := range NewAccountCollection(v) {
+				if err := enc.AppendObject(e); err != nil {
+					return err
+				}
+			}
+			return nil
+		}))
+	case nil, bool, string, []byte, int, int32, int64, uint, uint32, uint64, float32, float64:
+		return zap.Any(key, v)
+	}
+	return zap.String(key, fmt.Sprintf("%T (not logged)", v))
+}
+`
+
+var RedactMethodPayloadMarshalerCode = `// MarshalLogObject implements zapcore.ObjectMarshaler.
+func (v *RedactMethodPayload) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	if v == nil {
+		return nil
+	}
+	enc.AddString("user", v.User)
+	enc.AddString("password", "[REDACTED]")
+	if v.Count != nil {
+		enc.AddInt("count", *v.Count)
+	}
+	if err := enc.AddReflected("tags", v.Tags); err != nil {
+		return err
+	}
+	return nil
+}
+`
+
+var RedactMethodResultMarshalerCode = `// MarshalLogObject implements zapcore.ObjectMarshaler.
+func (v *RedactMethodResult) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	if v == nil {
+		return nil
+	}
+	enc.AddInt64("id", v.ID)
+	return nil
+}
+`
+
+var NestedMethodPayloadMarshalerCode = `// MarshalLogObject implements zapcore.ObjectMarshaler.
+func (v *NestedMethodPayload) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	if v == nil {
+		return nil
+	}
+	if err := enc.AddObject("item", v.Item); err != nil {
+		return err
+	}
+	if err := enc.AddArray("items", zapcore.ArrayMarshalerFunc(func(enc zapcore.ArrayEncoder) error {
+		for _, e := range v.Items {
+			if err := enc.AppendObject(e); err != nil {
+				return err
+			}
+		}
+		return nil
+	})); err != nil {
+		return err
+	}
+	if err := enc.AddObject("counts", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+		for k, e := range v.Counts {
+			if err := enc.AddArray(fmt.Sprint(k), zapcore.ArrayMarshalerFunc(func(enc zapcore.ArrayEncoder) error {
+				for _, e := range e {
+					enc.AppendInt(e)
+				}
+				return nil
+			})); err != nil {
+				return err
+			}
+		}
+		return nil
+	})); err != nil {
+		return err
+	}
+	if err := enc.AddObject("labels", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+		for k, e := range v.Labels {
+			enc.AddString(k, e)
+		}
+		return nil
+	})); err != nil {
+		return err
+	}
+	return nil
+}
+`
+
+var ItemMarshalerCode = `// MarshalLogObject implements zapcore.ObjectMarshaler.
+func (v *Item) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	if v == nil {
+		return nil
+	}
+	enc.AddString("name", v.Name)
+	enc.AddString("secret", "[REDACTED]")
+	return nil
+}
+`
+
+var LevelCode = `// LevelPath is the HTTP request path of the logger level endpoint.
+const LevelPath = "/debug/loglevel"
+
+// LevelHandler returns a HTTP handler that serves GET requests with the logger
+// level and PUT requests that change it, see zap.AtomicLevel.
+func (logger *Logger) LevelHandler() http.Handler {
+	return logger.level
+}
+
+// MountLevelHandler configures the mux to serve GET and PUT requests made to
+// LevelPath with h.
+func MountLevelHandler(mux goahttp.Muxer, h http.Handler) {
+	mux.Handle("GET", LevelPath, h.ServeHTTP)
+	mux.Handle("PUT", LevelPath, h.ServeHTTP)
+}
+`
+
+var SecureLevelCode = `// LevelPath is the HTTP request path of the logger level endpoint.
+const LevelPath = "/debug/loglevel"
+
+// LevelHandler returns a HTTP handler that serves GET requests with the logger
+// level and PUT requests that change it, see zap.AtomicLevel.
+func (logger *Logger) LevelHandler() http.Handler {
+	return logger.level
+}
+
+// MountLevelHandler configures the mux to serve GET and PUT requests made to
+// LevelPath with h.
+func MountLevelHandler(mux goahttp.Muxer, h http.Handler) {
+	mux.Handle("GET", LevelPath, h.ServeHTTP)
+	mux.Handle("PUT", LevelPath, h.ServeHTTP)
+}
+
+// SecureLevelHandler returns a HTTP handler that authorizes the requests using
+// auth and the "basic" security scheme before calling h. It responds with 401
+// Unauthorized if the request has no credentials or if auth fails.
+func SecureLevelHandler(h http.Handler, auth security.AuthBasicFunc) http.Handler {
+	scheme := &security.BasicScheme{Name: "basic"}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok {
+			http.Error(w, "missing credentials", http.StatusUnauthorized)
+			return
+		}
+		ctx, err := auth(r.Context(), user, pass, scheme)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		h.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+`
+
+var RecoverCode = `// RecoverOption configures the panic recovery middlewares.
+type RecoverOption func(*recoverOptions)
+
+// recoverOptions is the panic recovery middlewares configuration.
+type recoverOptions struct {
+	// onPanic is called each time a panic is recovered.
+	onPanic func()
+}
+
+// WithPanicCounter sets a function called each time a panic is recovered, e.g.
+// to increment a metrics counter.
+func WithPanicCounter(inc func()) RecoverOption {
+	return func(o *recoverOptions) {
+		o.onPanic = inc
+	}
+}
+
+// newRecoverOptions returns the panic recovery configuration built from opts.
+func newRecoverOptions(opts []RecoverOption) *recoverOptions {
+	o := &recoverOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// logPanic logs the recovered panic p at error level with its stack trace and
+// the given fields and calls the panic counter if any.
+func (logger *Logger) logPanic(o *recoverOptions, p interface{}, fields ...zap.Field) {
+	fields = append(fields, zap.Any("panic", p), zap.Stack("stack"))
+	logger.Desugar().Error("panic recovered", fields...)
+	if o.onPanic != nil {
+		o.onPanic()
+	}
+}
+`
+
+var HTTPRecoverCode = `// Recover returns a middleware that recovers from the panics of the HTTP
+// handlers. It logs the panics with their stack trace, the request ID, method
+// and path and responds with a goa fault error encoded using enc, that is with
+// a 500 Internal Server Error response.
+func (logger *Logger) Recover(enc func(context.Context, http.ResponseWriter) goahttp.Encoder, opts ...RecoverOption) func(http.Handler) http.Handler {
+	o := newRecoverOptions(opts)
+	encodeError := goahttp.ErrorEncoder(enc)
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				p := recover()
+				if p == nil {
+					return
+				}
+				if p == http.ErrAbortHandler {
+					// Let net/http abort the response.
+					panic(p)
+				}
+				id, _ := r.Context().Value(middleware.RequestIDKey).(string)
+				logger.logPanic(o, p, zap.String("request_id", id), zap.String("method", r.Method), zap.String("path", r.URL.Path))
+				ctx := context.WithValue(r.Context(), goahttp.AcceptTypeKey, r.Header.Get("Accept"))
+				if err := encodeError(ctx, w, goa.Fault("internal error")); err != nil {
+					logger.Desugar().Error("failed to encode panic response", zap.String("request_id", id), zap.Error(err))
+				}
+			}()
+			h.ServeHTTP(w, r)
+		})
+	}
+}
+`
+
+var GRPCRecoverCode = `// UnaryServerRecoverInterceptor returns a gRPC unary server interceptor that
+// recovers from the panics of the handlers. It logs the panics with their stack
+// trace, the request ID and the full method name and returns an Internal error.
+func (logger *Logger) UnaryServerRecoverInterceptor(opts ...RecoverOption) grpc.UnaryServerInterceptor {
+	o := newRecoverOptions(opts)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				logger.logPanic(o, p, zap.String("request_id", grpcRequestID(ctx)), zap.String("method", info.FullMethod))
+				err = status.Error(codes.Internal, "internal error")
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerRecoverInterceptor returns a gRPC stream server interceptor that
+// recovers from the panics of the handlers. It logs the panics with their stack
+// trace, the request ID and the full method name and returns an Internal error.
+func (logger *Logger) StreamServerRecoverInterceptor(opts ...RecoverOption) grpc.StreamServerInterceptor {
+	o := newRecoverOptions(opts)
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				logger.logPanic(o, p, zap.String("request_id", grpcRequestID(ss.Context())), zap.String("method", info.FullMethod))
+				err = status.Error(codes.Internal, "internal error")
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
+`
+
+var AccountMarshalerCode = `// MarshalLogObject implements zapcore.ObjectMarshaler.
+func (v *Account) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	if v == nil {
+		return nil
+	}
+	enc.AddString("name", v.Name)
+	enc.AddString("password", "[REDACTED]")
+	return nil
+}
+`
@@ -2,6 +2,7 @@ package testdata
 
 import (
 	. "goa.design/goa/dsl"
+	zaplogger "goa.design/plugins/zaplogger/dsl"
 )
 
 var SimpleServiceDSL = func() {
@@ -13,3 +14,144 @@ var SimpleServiceDSL = func() {
 		})
 	})
 }
+
+var ConfigDSL = func() {
+	API("ConfigAPI", func() {
+		zaplogger.Config(func() {
+			zaplogger.Encoding("console")
+			zaplogger.Level("debug")
+			zaplogger.Sampling(0, 0)
+			zaplogger.Output("stdout", "/var/log/config.log")
+		})
+	})
+	Service("ConfigService", func() {
+		Method("ConfigMethod", func() {
+			HTTP(func() {
+				GET("/")
+			})
+		})
+	})
+}
+
+var GRPCServiceDSL = func() {
+	Service("GRPCService", func() {
+		Method("GRPCMethod", func() {
+			GRPC(func() {})
+		})
+	})
+}
+
+var RedactServiceDSL = func() {
+	Service("RedactService", func() {
+		Method("RedactMethod", func() {
+			Payload(func() {
+				Attribute("user", String)
+				Attribute("password", String, func() {
+					Meta("zap:redact", "true")
+				})
+				Attribute("token", String, func() {
+					Meta("zap:omit", "true")
+				})
+				Attribute("count", Int)
+				Attribute("tags", ArrayOf(String))
+				Required("user")
+			})
+			Result(func() {
+				Attribute("id", Int64)
+				Required("id")
+			})
+			HTTP(func() {
+				POST("/")
+			})
+		})
+	})
+}
+
+var ViewedServiceDSL = func() {
+	var Account = ResultType("application/vnd.account", func() {
+		TypeName("Account")
+		Attributes(func() {
+			Attribute("name", String)
+			Attribute("password", String, func() {
+				Meta("zap:redact", "true")
+			})
+			Required("name")
+		})
+		View("default", func() {
+			Attribute("name")
+			Attribute("password")
+		})
+		View("tiny", func() {
+			Attribute("name")
+		})
+	})
+	Service("ViewedService", func() {
+		Method("ViewedMethod", func() {
+			Result(Account)
+			HTTP(func() {
+				GET("/")
+			})
+		})
+		Method("ViewedListMethod", func() {
+			Result(CollectionOf(Account))
+			HTTP(func() {
+				GET("/list")
+			})
+		})
+	})
+}
+
+var NestedServiceDSL = func() {
+	var Item = Type("Item", func() {
+		Attribute("name", String)
+		Attribute("secret", String, func() {
+			Meta("zap:redact", "true")
+		})
+		Required("name")
+	})
+	Service("NestedService", func() {
+		Method("NestedMethod", func() {
+			Payload(func() {
+				Attribute("item", Item)
+				Attribute("items", ArrayOf(Item))
+				Attribute("counts", MapOf(Int, ArrayOf(Int)))
+				Attribute("labels", MapOf(String, String))
+			})
+			HTTP(func() {
+				POST("/")
+			})
+		})
+		Method("NestedNoPayloadMethod", func() {
+			HTTP(func() {
+				GET("/")
+			})
+		})
+	})
+}
+
+var LevelDSL = func() {
+	API("LevelAPI", func() {
+		zaplogger.LevelEndpoint("/debug/loglevel")
+	})
+	Service("LevelService", func() {
+		Method("LevelMethod", func() {
+			HTTP(func() {
+				GET("/")
+			})
+		})
+	})
+}
+
+var SecureLevelDSL = func() {
+	var BasicAuth = BasicAuthSecurity("basic")
+	API("SecureLevelAPI", func() {
+		zaplogger.LevelEndpoint("/debug/loglevel", BasicAuth)
+	})
+	Service("SecureLevelService", func() {
+		Method("SecureLevelMethod", func() {
+			HTTP(func() {
+				GET("/")
+			})
+		})
+	})
+}
@@ -0,0 +1,85 @@
+package zerologger
+
+import (
+	"goa.design/goa/codegen"
+	"goa.design/plugins/logger"
+)
+
+// Backend is the zerolog logger plugin backend, it generates the
+// gen/log/logger.go file only.
+var Backend = &logger.Backend{
+	Name: "zerologger",
+	Files: logger.LoggerFile("Zerolog logger implementation", "zerologger", loggerT, []*codegen.ImportSpec{
+		{Path: "fmt"},
+		{Path: "io"},
+		{Path: "os"},
+		{Path: "github.com/rs/zerolog"},
+	}),
+}
+
+// Register the plugin Generator functions.
+func init() {
+	logger.Register(Backend)
+}
+
+// input: none
+const loggerT = `
+// Logger is an adapted zerolog logger
+type Logger struct {
+	logger zerolog.Logger
+}
+
+// New creates a new zerolog logger. The production logger writes JSON encoded
+// entries of level info and above, the development logger writes human
+// friendly entries of level debug and above.
+func New(serviceName string, production bool) (*Logger, error) {
+	var w io.Writer = os.Stderr
+	lvl := zerolog.InfoLevel
+	if !production {
+		w = zerolog.ConsoleWriter{Out: os.Stderr}
+		lvl = zerolog.DebugLevel
+	}
+	l := zerolog.New(w).Level(lvl).With().Timestamp().Str("service", serviceName).Logger()
+	return &Logger{logger: l}, nil
+}
+
+// Zerolog returns the underlying zerolog logger.
+func (logger *Logger) Zerolog() *zerolog.Logger {
+	return &logger.logger
+}
+
+// Log is called by the log middleware to log HTTP requests key values
+func (logger *Logger) Log(keyvals ...interface{}) error {
+	e := logger.logger.Info()
+	for i := 0; i < len(keyvals); i += 2 {
+		var v interface{} = "MISSING"
+		if i+1 < len(keyvals) {
+			v = keyvals[i+1]
+		}
+		e = e.Interface(fmt.Sprint(keyvals[i]), v)
+	}
+	e.Msg("HTTP Request")
+	return nil
+}
+
+// Info logs a message at info level. The message is built from args like
+// fmt.Sprint does.
+func (logger *Logger) Info(args ...interface{}) {
+	logger.logger.Info().Msg(fmt.Sprint(args...))
+}
+
+// Infof logs a formatted message at info level.
+func (logger *Logger) Infof(format string, args ...interface{}) {
+	logger.logger.Info().Msgf(format, args...)
+}
+
+// Errorf logs a formatted message at error level.
+func (logger *Logger) Errorf(format string, args ...interface{}) {
+	logger.logger.Error().Msgf(format, args...)
+}
+
+// Sync is a no-op, zerolog does not buffer the log entries.
+func (logger *Logger) Sync() error {
+	return nil
+}
+`
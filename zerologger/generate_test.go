@@ -0,0 +1,85 @@
+package zerologger
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"goa.design/goa/codegen"
+	"goa.design/goa/codegen/generator"
+	"goa.design/goa/eval"
+	"goa.design/goa/expr"
+	httpcodegen "goa.design/goa/http/codegen"
+	"goa.design/plugins/zerologger/testdata"
+)
+
+func TestGenerate(t *testing.T) {
+	httpcodegen.RunHTTPDSL(t, testdata.SimpleServiceDSL)
+	fs := Backend.Files("", expr.Root)
+	if len(fs) != 1 {
+		t.Fatalf("got %d files, expected 1", len(fs))
+	}
+	if exp := filepath.Join(codegen.Gendir, "log", "logger.go"); fs[0].Path != exp {
+		t.Fatalf("got file %s, expected %s", fs[0].Path, exp)
+	}
+	testCode(t, fs[0], "zerologger", testdata.LoggerCode)
+}
+
+func TestUpdateExample(t *testing.T) {
+	httpcodegen.RunHTTPDSL(t, testdata.SimpleServiceDSL)
+	roots := []eval.Root{expr.Root}
+	files, err := generator.Example("", roots)
+	if err != nil {
+		t.Fatalf("error in example generation: %v", err)
+	}
+	files, err = Backend.UpdateExample("", roots, files)
+	if err != nil {
+		t.Fatalf("update example error: %v", err)
+	}
+	code := make(map[string]string)
+	for _, f := range files {
+		base := filepath.Base(f.Path)
+		if !strings.HasPrefix(f.Path, "cmd") && base != "simple_service.go" {
+			continue
+		}
+		buf := new(bytes.Buffer)
+		for _, s := range f.SectionTemplates[1:] {
+			if err := s.Write(buf); err != nil {
+				t.Fatalf("error writing section %s in file %s: %v", s.Name, f.Path, err)
+			}
+		}
+		code[base] += buf.String()
+	}
+	cases := map[string][]string{
+		"main.go":           {`logger, err = log.New("`, "logger.Sync()"},
+		"http.go":           {`logger.Errorf("[%s] ERROR: %s", id, err.Error())`, "adapter = logger"},
+		"simple_service.go": {"s.logger.Info("},
+	}
+	for file, exps := range cases {
+		c, ok := code[file]
+		if !ok {
+			t.Errorf("example file %s not found", file)
+			continue
+		}
+		for _, exp := range exps {
+			if !strings.Contains(c, exp) {
+				t.Errorf("%s: got code:\n%s\nexpected it to contain %q", file, c, exp)
+			}
+		}
+		if strings.Contains(c, "logger.Print") {
+			t.Errorf("%s: got code:\n%s\nexpected no call to logger.Print", file, c)
+		}
+	}
+}
+
+func testCode(t *testing.T, file *codegen.File, section, expCode string) {
+	sections := file.Section(section)
+	if len(sections) < 1 {
+		t.Fatalf("%s: got %d sections, expected at least 1", section, len(sections))
+	}
+	code := codegen.SectionCode(t, sections[0])
+	if code != expCode {
+		t.Errorf("invalid code, got:\n%s\ngot vs. expected:\n%s", code, codegen.Diff(t, code, expCode))
+	}
+}
@@ -0,0 +1,15 @@
+package testdata
+
+import (
+	. "goa.design/goa/dsl"
+)
+
+var SimpleServiceDSL = func() {
+	Service("SimpleService", func() {
+		Method("SimpleMethod", func() {
+			HTTP(func() {
+				GET("/")
+			})
+		})
+	})
+}